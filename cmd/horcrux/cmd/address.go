@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/cometbft/cometbft/crypto"
-	cometprivval "github.com/cometbft/cometbft/privval"
 	"github.com/cosmos/cosmos-sdk/types/bech32"
 	"github.com/spf13/cobra"
 	"github.com/strangelove-ventures/horcrux/v3/signer"
@@ -45,12 +44,12 @@ func addressCmd() *cobra.Command {
 					return err
 				}
 
-				key, err := signer.LoadCosignerEd25519Key(keyFile)
+				key, err := signer.LoadCosignerKey(keyFile)
 				if err != nil {
 					return fmt.Errorf("error reading cosigner key: %w, check that key is present for chain ID: %s", err, chainID)
 				}
 
-				pubKey = key.PubKey
+				pubKey = key.CombinedPubKey()
 			case signer.SignModeSingle:
 				err := config.Config.ValidateSingleSignerConfig()
 				if err != nil {
@@ -61,7 +60,10 @@ func addressCmd() *cobra.Command {
 					return fmt.Errorf("error reading priv-validator key: %w, check that key is present for chain ID: %s", err, chainID)
 				}
 
-				filePV := cometprivval.LoadFilePVEmptyState(keyFile, "")
+				filePV, err := signer.LoadFilePV(keyFile, "", false)
+				if err != nil {
+					return fmt.Errorf("error reading priv-validator key: %w, check that key is present for chain ID: %s", err, chainID)
+				}
 				pubKey = filePV.Key.PubKey
 			default:
 				panic(fmt.Errorf("unexpected sign mode: %s", config.Config.SignMode))
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	cometjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/cometbft/cometbft/privval"
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/v3/signer"
+)
+
+const (
+	flagStdout = "stdout"
+	flagYes    = "yes"
+
+	combineConfirmation = "COMBINE"
+)
+
+const combineWarning = `
+!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!
+!!  WARNING: this reconstructs the FULL validator private key from shards.    !!
+!!                                                                            !!
+!!  Anyone with the combined key can sign as your validator. Only combine     !!
+!!  shards to decommission the horcrux cluster or move the validator          !!
+!!  off-cluster. Stop ALL cosigners before signing with the combined key,     !!
+!!  or your validator WILL double sign and be slashed.                        !!
+!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!
+
+`
+
+const combineLimitation = `The combined key has the expanded key type horcrux/PrivKeyEd25519Expanded, ` +
+	`which CometBFT and tmkms can NOT load. Use it with horcrux single signer mode by writing it ` +
+	`to the horcrux home or key directory, or re-shard it with horcrux create-ed25519-shards.`
+
+func combineCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "combine shard-file...",
+		Short: "Reconstruct the full key from the threshold number of Ed25519 shards, for horcrux only",
+		Long: `Reconstruct the full priv-validator key from at least the threshold number of Ed25519 shards.
+
+The shards are combined with lagrange interpolation and the combined key is verified against
+the public key of the shards.
+
+LIMITATION: the combined key can NOT be loaded by CometBFT or tmkms. The shards are shares of the
+Ed25519 secret scalar, not of the seed that the original priv_validator_key.json was generated from,
+so the seed can not be reconstructed. CometBFT derives the secret scalar by hashing the seed, so
+no seed exists for a combined scalar. The combined key is written with the expanded key type
+"horcrux/PrivKeyEd25519Expanded", which produces the same signatures, but can only be used by
+horcrux single signer mode or re-sharded with horcrux create-ed25519-shards. Write it to the
+horcrux home or key directory with --out to load it in single signer mode.`,
+		Example: `horcrux shards combine --chain-id cosmoshub-4 \
+  cosigner_1/cosmoshub-4_shard.json cosigner_2/cosmoshub-4_shard.json
+horcrux shards combine --chain-id cosmoshub-4 --encrypt \
+  cosigner_1/cosmoshub-4_shard.json cosigner_2/cosmoshub-4_shard.json
+horcrux shards combine --chain-id cosmoshub-4 --stdout \
+  cosigner_1/cosmoshub-4_shard.json cosigner_3/cosmoshub-4_shard.json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+
+			chainID, _ := flags.GetString(flagChainID)
+			outDir, _ := flags.GetString(flagOutputDir)
+			stdout, _ := flags.GetBool(flagStdout)
+			yes, _ := flags.GetBool(flagYes)
+
+			if chainID == "" {
+				return fmt.Errorf("chain-id flag must not be empty")
+			}
+
+			if encrypt, _ := flags.GetBool(flagEncrypt); encrypt && stdout {
+				return fmt.Errorf("%s flag can not be used with %s flag", flagEncrypt, flagStdout)
+			}
+
+			keyFile := filepath.Join(outDir, fmt.Sprintf("%s_priv_validator_key.json", chainID))
+			if !stdout {
+				if _, err := os.Stat(keyFile); err == nil {
+					return fmt.Errorf("priv-validator key already exists: %s", keyFile)
+				}
+			}
+
+			shards := make([]signer.CosignerEd25519Key, len(args))
+			for i, file := range args {
+				shard, err := signer.LoadCosignerEd25519Key(file)
+				if err != nil {
+					return fmt.Errorf("error reading cosigner key (%s): %w", file, err)
+				}
+				shards[i] = shard
+			}
+
+			// silence usage after all input has been validated
+			cmd.SilenceUsage = true
+
+			stderr := cmd.ErrOrStderr()
+			fmt.Fprint(stderr, combineWarning)
+
+			if !yes {
+				if err := confirmCombine(cmd.InOrStdin(), stderr); err != nil {
+					return err
+				}
+			}
+
+			privKey, err := signer.CombineCosignerEd25519Shards(shards)
+			if err != nil {
+				return err
+			}
+
+			pubKey := privKey.PubKey()
+			pvKey := privval.FilePVKey{
+				Address: pubKey.Address(),
+				PubKey:  pubKey,
+				PrivKey: privKey,
+			}
+
+			if stdout {
+				jsonBytes, err := cometjson.MarshalIndent(pvKey, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(jsonBytes))
+				fmt.Fprintln(stderr, combineLimitation)
+				return nil
+			}
+
+			if err := setNewKeyFilePassphrase(cmd); err != nil {
+				return err
+			}
+
+			if outDir != "" {
+				if err := os.MkdirAll(outDir, 0700); err != nil {
+					return err
+				}
+			}
+
+			if err := signer.WritePrivValidatorFile(pvKey, keyFile); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(stderr, "Combined %d shards into FULL private key %s\n", len(shards), keyFile)
+			fmt.Fprintf(stderr, "Validator address: %X\n", pubKey.Address())
+			fmt.Fprintln(stderr, combineLimitation)
+
+			return nil
+		},
+	}
+
+	addOutputDirFlag(cmd)
+	addEncryptFlag(cmd)
+
+	f := cmd.Flags()
+	f.String(flagChainID, "", "chain ID of the shards, used for the output file name")
+	_ = cmd.MarkFlagRequired(flagChainID)
+	f.Bool(flagStdout, false, "write the combined key to stdout only instead of a file")
+	f.Bool(flagYes, false, "skip the interactive confirmation")
+
+	return cmd
+}
+
+// confirmCombine requires the user to type the confirmation before the full key is reconstructed.
+func confirmCombine(in io.Reader, out io.Writer) error {
+	fmt.Fprintf(out, "Type %s to reconstruct the full private key: ", combineConfirmation)
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return fmt.Errorf("failed to read confirmation: %w", err)
+	}
+
+	if strings.TrimSpace(line) != combineConfirmation {
+		return fmt.Errorf("aborted, confirmation not given")
+	}
+
+	return nil
+}
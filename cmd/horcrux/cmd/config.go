@@ -20,6 +20,7 @@ const (
 	flagBare        = "bare"
 	flagGRPCAddress = "gprc-address"
 	flagMaxReadSize = "max-read-size"
+	flagSignScheme  = "sign-scheme"
 )
 
 func configCmd() *cobra.Command {
@@ -78,6 +79,7 @@ for threshold signer mode, --cosigner flags and --threshold flag are required.
 				threshold, _ := cmdFlags.GetInt(flagThreshold)
 				raftTimeout, _ := cmdFlags.GetString(flagRaftTimeout)
 				grpcTimeout, _ := cmdFlags.GetString(flagGRPCTimeout)
+				signScheme, _ := cmdFlags.GetString(flagSignScheme)
 				cosigners, err := signer.CosignersFromFlag(cosignersFlag)
 				if err != nil {
 					return err
@@ -91,6 +93,7 @@ for threshold signer mode, --cosigner flags and --threshold flag are required.
 						Cosigners:   cosigners,
 						GRPCTimeout: grpcTimeout,
 						RaftTimeout: raftTimeout,
+						SignScheme:  signScheme,
 					},
 					ChainNodes:  cn,
 					DebugAddr:   debugAddr,
@@ -159,6 +162,8 @@ for threshold signer mode, --cosigner flags and --threshold flag are required.
 		"accepts valid duration strings for Go's time.ParseDuration() e.g. 1s, 1000ms, 1.5m")
 	f.String(flagGRPCTimeout, "500ms", "cosigner grpc timeout value, \n"+
 		"accepts valid duration strings for Go's time.ParseDuration() e.g. 1s, 1000ms, 1.5m")
+	f.String(flagSignScheme, "", "threshold signing scheme, \"tsed25519\" (default) or \"frost\". \n"+
+		"all cosigners of the cluster must use the same scheme")
 	f.BoolP(flagOverwrite, "o", false, "overwrite an existing config.yaml")
 	f.Bool(
 		flagBare,
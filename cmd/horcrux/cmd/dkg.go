@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/v3/signer"
+	"google.golang.org/grpc/credentials"
+)
+
+const flagTimeout = "timeout"
+
+func dkgCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dkg",
+		Short: "Generate a new cosigner Ed25519 shard with a distributed key generation ceremony",
+		Long: `Generate a new cosigner Ed25519 shard with a dealerless distributed key generation ceremony.
+
+Every cosigner in the threshold mode config must run this command at the same time.
+Cosigners exchange deals over their p2p addresses, authenticated with the ECIES keys
+in ecies_keys.json. Each cosigner ends up with its own {chain-id}_shard.json,
+and the combined private key never exists on any single machine.
+
+The horcrux signer must be stopped while the ceremony is running.`,
+		Example:      `horcrux dkg --chain-id cosmoshub-4`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chainID, _ := cmd.Flags().GetString(flagChainID)
+			timeout, _ := cmd.Flags().GetDuration(flagTimeout)
+
+			if chainID == "" {
+				return fmt.Errorf("chain-id flag must not be empty")
+			}
+
+			if err := config.Config.ValidateThresholdModeConfig(); err != nil {
+				return err
+			}
+
+			keyFile := config.KeyFilePathCosigner(chainID)
+			if _, err := os.Stat(keyFile); err == nil {
+				return fmt.Errorf("cosigner shard already exists for chain ID %s: %s", chainID, keyFile)
+			}
+
+			out := cmd.OutOrStdout()
+			logger := cometlog.NewTMLogger(cometlog.NewSyncWriter(out))
+
+			if err := signer.RequireNotRunning(logger, config.PidFile); err != nil {
+				return err
+			}
+
+			security, err := config.CosignerSecurityECIES()
+			if err != nil {
+				return fmt.Errorf("failed to initialize cosigner ECIES security: %w", err)
+			}
+
+			thresholdCfg := config.Config.ThresholdModeConfig
+
+			if err := setNewKeyFilePassphrase(cmd); err != nil {
+				return err
+			}
+
+			creds, err := keyCeremonyCredentials(thresholdCfg.Cosigners)
+			if err != nil {
+				return err
+			}
+
+			ceremony, err := signer.NewKeyCeremony(logger, security, thresholdCfg.Cosigners, creds)
+			if err != nil {
+				return err
+			}
+
+			if err := ceremony.Start(); err != nil {
+				return fmt.Errorf("failed to start key ceremony: %w", err)
+			}
+			defer ceremony.Stop()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			key, err := ceremony.DKG(ctx, chainID, uint8(thresholdCfg.Threshold))
+			if err != nil {
+				return fmt.Errorf("distributed key generation failed: %w", err)
+			}
+
+			if err := signer.WriteCosignerEd25519ShardFile(*key, keyFile); err != nil {
+				return err
+			}
+
+			pubKey, err := signer.PubKey("", key.PubKey)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "Created Ed25519 Shard %s\n", keyFile)
+			fmt.Fprintf(out, "Public key: %s\n", pubKey)
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.String(flagChainID, "", "key shards will sign for this chain ID")
+	_ = cmd.MarkFlagRequired(flagChainID)
+	f.Duration(flagTimeout, 5*time.Minute, "time to wait for all cosigners to complete the ceremony")
+	addEncryptFlag(cmd)
+
+	return cmd
+}
+
+// keyCeremonyCredentials returns the transport credentials of the p2p connections between the cosigners
+// of a key ceremony, for the TLS mode of the threshold mode config.
+func keyCeremonyCredentials(cosigners signer.CosignersConfig) (credentials.TransportCredentials, error) {
+	thresholdCfg := *config.Config.ThresholdModeConfig
+	thresholdCfg.Cosigners = cosigners
+
+	ceremonyConfig := config
+	ceremonyConfig.Config.ThresholdModeConfig = &thresholdCfg
+
+	creds, err := ceremonyConfig.CosignerTransportCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize cosigner transport credentials: %w", err)
+	}
+	return creds, nil
+}
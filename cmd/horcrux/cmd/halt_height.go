@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/v3/signer/proto"
+)
+
+func haltHeightCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "halt",
+		Short: "Commands to halt signing for a chain at an upgrade height",
+		Long: `Commands to halt signing for a chain at an upgrade height, and to resume signing after the upgrade.
+
+The halt height is the last height that is signed for the chain. It is set on the raft leader
+and replicated to all cosigners, in addition to the haltHeight of the chain in the chains config.`,
+	}
+
+	cmd.AddCommand(setHaltHeightCmd())
+	cmd.AddCommand(liftHaltHeightCmd())
+
+	return cmd
+}
+
+func setHaltHeightCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set chain-id height",
+		Short: "Stop signing for the chain above the height, or clear the halt height with 0",
+		Example: `horcrux halt set cosmoshub-4 18000000 # sign up to and including height 18000000
+horcrux halt set cosmoshub-4 0 # clear the halt height set with horcrux halt set`,
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			height, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil || height < 0 {
+				cmd.SilenceUsage = false
+				return fmt.Errorf("invalid height (%s), must be a non-negative integer", args[1])
+			}
+			return setHaltHeight(cmd, &proto.SetHaltHeightRequest{ChainID: args[0], HaltHeight: height})
+		},
+	}
+}
+
+func liftHaltHeightCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lift chain-id",
+		Short: "Resume signing for the chain once the upgraded chain is confirmed",
+		Long: `Resume signing for the chain once the upgraded chain is confirmed.
+
+The current halt height is lifted, whether it was set with horcrux halt set or in the chains config,
+so the haltHeight does not need to be removed from the config of each cosigner first.`,
+		Example:      `horcrux halt lift cosmoshub-4`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setHaltHeight(cmd, &proto.SetHaltHeightRequest{ChainID: args[0], Lift: true})
+		},
+	}
+}
+
+// setHaltHeight sends the request to the raft leader, and prints the resulting halt height of the chain.
+func setHaltHeight(cmd *cobra.Command, req *proto.SetHaltHeightRequest) error {
+	conn, err := dialLeader()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelFunc()
+
+	res, err := proto.NewCosignerClient(conn).SetHaltHeight(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	if res.HaltHeight == 0 {
+		fmt.Fprintf(out, "Chain %s does not halt\n", req.ChainID)
+	} else {
+		fmt.Fprintf(out, "Chain %s halts after height %d\n", req.ChainID, res.HaltHeight)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/v3/signer"
+)
+
+const flagEncrypt = "encrypt"
+
+func addEncryptFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(flagEncrypt, false, "encrypt the new key files with a passphrase, like horcrux shards encrypt")
+}
+
+// setNewKeyFilePassphrase reads the passphrase that the new key files are encrypted with if --encrypt is set,
+// before any key file is written. Otherwise the new key files are plaintext.
+func setNewKeyFilePassphrase(cmd *cobra.Command) error {
+	if encrypt, _ := cmd.Flags().GetBool(flagEncrypt); !encrypt {
+		return nil
+	}
+
+	passphrase, err := readNewPassphrase()
+	if err != nil {
+		return err
+	}
+	signer.SetNewKeyFilePassphrase(passphrase)
+	return nil
+}
+
+// keyFilesToConvert returns the key files given as args, or the key files in the key directory
+// and of the chains config that are not yet in the desired encryption state.
+func keyFilesToConvert(args []string, encrypted bool) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+
+	files, err := config.KeyFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []string
+	for _, file := range files {
+		bz, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if signer.IsEncryptedKeyFile(bz) != encrypted {
+			out = append(out, file)
+		}
+	}
+
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no key files to convert in key directory")
+	}
+
+	return out, nil
+}
+
+func encryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt [key-file...]",
+		Short: "Encrypt shard and key files with a passphrase",
+		Long: `Encrypt shard and key files in place with a passphrase.
+
+If no files are given, all plaintext shard, priv-validator key and cosigner key files
+in the key directory, and the keyFile and keyRotation keyFile of each chain, are encrypted.
+The passphrase is read from --passphrase-fd, the HORCRUX_PASSPHRASE environment variable, or a terminal prompt.
+The same passphrase must be provided to horcrux start.`,
+		Example: `horcrux shards encrypt
+horcrux shards encrypt ~/.horcrux/cosmoshub-4_shard.json ~/.horcrux/ecies_keys.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := keyFilesToConvert(args, false)
+			if err != nil {
+				return err
+			}
+
+			// silence usage after all input has been validated
+			cmd.SilenceUsage = true
+
+			passphrase, err := readNewPassphrase()
+			if err != nil {
+				return err
+			}
+
+			for _, file := range files {
+				if err := signer.EncryptKeyFile(file, passphrase); err != nil {
+					return fmt.Errorf("failed to encrypt %s: %w", file, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Encrypted %s\n", file)
+			}
+
+			return nil
+		},
+	}
+}
+
+func decryptCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "decrypt [key-file...]",
+		Short: "Decrypt passphrase encrypted shard and key files",
+		Long: `Decrypt passphrase encrypted shard and key files in place.
+
+If no files are given, all encrypted files in the key directory, and the keyFile and keyRotation keyFile
+of each chain, are decrypted.
+The passphrase is read from --passphrase-fd, the HORCRUX_PASSPHRASE environment variable,
+or a terminal prompt.`,
+		Example: `horcrux shards decrypt
+horcrux shards decrypt ~/.horcrux/cosmoshub-4_shard.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := keyFilesToConvert(args, true)
+			if err != nil {
+				return err
+			}
+
+			// silence usage after all input has been validated
+			cmd.SilenceUsage = true
+
+			passphrase, err := keyPassphrase()
+			if err != nil {
+				return err
+			}
+
+			for _, file := range files {
+				if err := signer.DecryptKeyFile(file, passphrase); err != nil {
+					return fmt.Errorf("failed to decrypt %s: %w", file, err)
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Decrypted %s\n", file)
+			}
+
+			return nil
+		},
+	}
+}
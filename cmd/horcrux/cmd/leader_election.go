@@ -9,16 +9,10 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/strangelove-ventures/horcrux/v3/client"
 	"github.com/strangelove-ventures/horcrux/v3/signer"
-	"github.com/strangelove-ventures/horcrux/v3/signer/multiresolver"
 	"github.com/strangelove-ventures/horcrux/v3/signer/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
-func init() {
-	multiresolver.Register()
-}
-
 func leaderElectionCmd() *cobra.Command {
 	return &cobra.Command{
 		Use:   "elect [node_id]",
@@ -31,33 +25,10 @@ To choose a specific leader, pass that leader's ID as an argument.
 horcrux elect 2 # elect specific leader`,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			if config.Config.ThresholdModeConfig == nil {
-				return fmt.Errorf("threshold mode configuration is not present in config file")
-			}
-
-			if len(config.Config.ThresholdModeConfig.Cosigners) == 0 {
-				return fmt.Errorf("threshold mode configuration has no cosigners")
-			}
-
-			serviceConfig := `{"healthCheckConfig": {"serviceName": "Leader"}, "loadBalancingConfig": [ { "round_robin": {} } ]}`
-			retryOpts := []grpcretry.CallOption{
-				grpcretry.WithBackoff(grpcretry.BackoffExponential(100 * time.Millisecond)),
-				grpcretry.WithMax(5),
-			}
-
-			grpcAddress, err := config.Config.ThresholdModeConfig.LeaderElectMultiAddress()
+			conn, err := dialLeader()
 			if err != nil {
 				return err
 			}
-
-			fmt.Printf("Broadcasting to address: %s\n", grpcAddress)
-			conn, err := grpc.Dial(grpcAddress,
-				grpc.WithDefaultServiceConfig(serviceConfig), grpc.WithTransportCredentials(insecure.NewCredentials()),
-				grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
-				grpc.WithUnaryInterceptor(grpcretry.UnaryClientInterceptor(retryOpts...)))
-			if err != nil {
-				return fmt.Errorf("dialing failed: %v", err)
-			}
 			defer conn.Close()
 
 			leaderID := ""
@@ -107,59 +78,14 @@ func getLeaderCmd() *cobra.Command {
 				return fmt.Errorf("threshold mode configuration has no cosigners")
 			}
 
-			var id int
-
-			keyFileECIES, err := config.KeyFileExistsCosignerECIES()
-			if err != nil {
-				keyFileRSA, err := config.KeyFileExistsCosignerRSA()
-				if err != nil {
-					return fmt.Errorf("cosigner encryption keys not found (%s) - (%s): %w", keyFileECIES, keyFileRSA, err)
-				}
-
-				key, err := signer.LoadCosignerRSAKey(keyFileRSA)
-				if err != nil {
-					return fmt.Errorf("error reading cosigner key (%s): %w", keyFileRSA, err)
-				}
-
-				id = key.ID
-			} else {
-				key, err := signer.LoadCosignerECIESKey(keyFileECIES)
-				if err != nil {
-					return fmt.Errorf("error reading cosigner key (%s): %w", keyFileECIES, err)
-				}
-
-				id = key.ID
-			}
-
-			var p2pListen string
-
-			for _, c := range thresholdCfg.Cosigners {
-				if c.ShardID == id {
-					p2pListen = c.P2PAddr
-				}
-			}
-
-			if p2pListen == "" {
-				return fmt.Errorf("cosigner config does not exist for our shard ID %d", id)
-			}
-
-			retryOpts := []grpcretry.CallOption{
-				grpcretry.WithBackoff(grpcretry.BackoffExponential(100 * time.Millisecond)),
-				grpcretry.WithMax(5),
-			}
-
-			grpcAddress, err := client.SanitizeAddress(p2pListen)
+			security, err := newCosignerSecurity()
 			if err != nil {
 				return err
 			}
 
-			fmt.Printf("Request address: %s\n", grpcAddress)
-			conn, err := grpc.Dial(grpcAddress,
-				grpc.WithTransportCredentials(insecure.NewCredentials()),
-				grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
-				grpc.WithUnaryInterceptor(grpcretry.UnaryClientInterceptor(retryOpts...)))
+			conn, err := dialCosigner(security, security.GetID())
 			if err != nil {
-				return fmt.Errorf("dialing failed: %v", err)
+				return err
 			}
 			defer conn.Close()
 
@@ -180,3 +106,87 @@ func getLeaderCmd() *cobra.Command {
 	}
 
 }
+
+// dialLeader dials the raft leader of the cosigners in the config, for the admin requests that only the leader
+// can handle. The leader is looked up on the cosigner this runs on, and requests are signed with its key.
+func dialLeader() (*grpc.ClientConn, error) {
+	if config.Config.ThresholdModeConfig == nil {
+		return nil, fmt.Errorf("threshold mode configuration is not present in config file")
+	}
+
+	if len(config.Config.ThresholdModeConfig.Cosigners) == 0 {
+		return nil, fmt.Errorf("threshold mode configuration has no cosigners")
+	}
+
+	security, err := newCosignerSecurity()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dialCosigner(security, security.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelFunc()
+
+	res, err := proto.NewCosignerClient(conn).GetLeader(ctx, &proto.GetLeaderRequest{})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	leader := int(res.Leader)
+	if leader == security.GetID() {
+		return conn, nil
+	}
+	conn.Close()
+
+	if leader <= 0 {
+		return nil, fmt.Errorf("no raft leader is elected")
+	}
+	return dialCosigner(security, leader)
+}
+
+// dialCosigner dials the cosigner with the shard ID in the config. Requests are retried,
+// and signed with the key of the cosigner this runs on for the dialed cosigner.
+func dialCosigner(security signer.CosignerSecurity, shardID int) (*grpc.ClientConn, error) {
+	var p2pAddr string
+	for _, c := range config.Config.ThresholdModeConfig.Cosigners {
+		if c.ShardID == shardID {
+			p2pAddr = c.P2PAddr
+		}
+	}
+
+	if p2pAddr == "" {
+		return nil, fmt.Errorf("cosigner config does not exist for shard ID %d", shardID)
+	}
+
+	retryOpts := []grpcretry.CallOption{
+		grpcretry.WithBackoff(grpcretry.BackoffExponential(100 * time.Millisecond)),
+		grpcretry.WithMax(5),
+	}
+
+	grpcAddress, err := client.SanitizeAddress(p2pAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := config.CosignerTransportCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := grpc.Dial(grpcAddress,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
+		grpc.WithChainUnaryInterceptor(
+			grpcretry.UnaryClientInterceptor(retryOpts...),
+			signer.CosignerAuthUnaryClientInterceptor(security, shardID),
+		))
+	if err != nil {
+		return nil, fmt.Errorf("dialing failed: %v", err)
+	}
+	return conn, nil
+}
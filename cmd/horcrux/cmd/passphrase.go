@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/v3/signer"
+	"golang.org/x/term"
+)
+
+const (
+	flagPassphraseFD = "passphrase-fd"
+
+	envPassphrase = "HORCRUX_PASSPHRASE"
+)
+
+// passphraseFD is the file descriptor to read the key file passphrase from, if not negative.
+var passphraseFD int
+
+var (
+	passphraseOnce sync.Once
+	passphrase     []byte
+	passphraseErr  error
+)
+
+func addPassphraseFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().IntVar(
+		&passphraseFD,
+		flagPassphraseFD,
+		-1,
+		fmt.Sprintf(
+			"File descriptor to read the key file passphrase from (default is $%s or a terminal prompt)",
+			envPassphrase,
+		),
+	)
+}
+
+// keyPassphrase returns the passphrase for encrypted key files.
+// It is read once from the --passphrase-fd file descriptor, the HORCRUX_PASSPHRASE environment variable,
+// or a terminal prompt, in that order of precedence.
+func keyPassphrase() ([]byte, error) {
+	passphraseOnce.Do(func() {
+		passphrase, passphraseErr = readPassphrase("Enter key file passphrase: ")
+	})
+	return passphrase, passphraseErr
+}
+
+// readPassphrase reads a passphrase without caching it.
+func readPassphrase(prompt string) ([]byte, error) {
+	if passphraseFD >= 0 {
+		f := os.NewFile(uintptr(passphraseFD), "passphrase")
+		if f == nil {
+			return nil, fmt.Errorf("invalid passphrase file descriptor: %d", passphraseFD)
+		}
+		line, err := bufio.NewReader(f).ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read passphrase from file descriptor %d: %w", passphraseFD, err)
+		}
+		return nonEmptyPassphrase(bytes.TrimRight(line, "\r\n"))
+	}
+
+	if env, ok := os.LookupEnv(envPassphrase); ok {
+		return nonEmptyPassphrase([]byte(env))
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, fmt.Errorf("no terminal to prompt for the passphrase, use --%s or $%s", flagPassphraseFD, envPassphrase)
+	}
+
+	fmt.Fprint(os.Stderr, prompt)
+	p, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	return nonEmptyPassphrase(p)
+}
+
+// readNewPassphrase reads a passphrase for encrypting key files,
+// asking for confirmation when it is entered at a terminal prompt.
+func readNewPassphrase() ([]byte, error) {
+	_, fromEnv := os.LookupEnv(envPassphrase)
+	if passphraseFD >= 0 || fromEnv {
+		return keyPassphrase()
+	}
+
+	p, err := readPassphrase("Enter new key file passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	confirm, err := readPassphrase("Confirm new key file passphrase: ")
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(p, confirm) {
+		return nil, errors.New("passphrases do not match")
+	}
+	return p, nil
+}
+
+func nonEmptyPassphrase(p []byte) ([]byte, error) {
+	if len(p) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+	return p, nil
+}
+
+// requireKeyPassphrase resolves the passphrase up front if any key files are encrypted,
+// and verifies that it decrypts them, so that the signer does not fail later when a key is first loaded.
+// Key files created by the signer, i.e. the connection key, are then encrypted with the same passphrase.
+func requireKeyPassphrase() error {
+	files, err := config.EncryptedKeyFiles()
+	if err != nil {
+		return fmt.Errorf("failed to check for encrypted key files: %w", err)
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	p, err := keyPassphrase()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		bz, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if _, err := signer.DecryptKeyFileBytes(bz, p); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	signer.SetNewKeyFilePassphrase(p)
+	return nil
+}
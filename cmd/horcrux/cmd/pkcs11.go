@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/v3/signer"
+)
+
+func importPKCS11Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-pkcs11 [shard-file]",
+		Short: "Store a cosigner shard in the PKCS#11 token of this cosigner",
+		Long: `Store a cosigner shard in the PKCS#11 token configured under thresholdMode.pkcs11ShardStorage.
+
+The shard is stored as a private data object with the configured key label, which can only be read
+after logging in with the user PIN. If no shard file is given, {chain-id}_shard.json in the key directory
+is imported. Once imported, remove the shard file from the key directory, horcrux reads the shard
+from the token at startup instead.
+
+This is PIN-protected shard storage, not hardware-backed signing. Nothing is computed inside the token:
+horcrux reads the shard into memory and signs with it exactly like a shard file, so the token only
+protects the shard at rest.`,
+		Example: `horcrux shards import-pkcs11 --chain-id cosmoshub-4
+horcrux shards import-pkcs11 --chain-id cosmoshub-4 /mnt/usb/cosmoshub-4_shard.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			chainID, _ := cmd.Flags().GetString(flagChainID)
+			if chainID == "" {
+				return fmt.Errorf("chain-id flag must not be empty")
+			}
+
+			if config.Config.ThresholdModeConfig == nil || config.Config.ThresholdModeConfig.PKCS11ShardStorage == nil {
+				return fmt.Errorf("no PKCS#11 token configured under thresholdMode.pkcs11ShardStorage")
+			}
+			pkcs11Config := config.Config.ThresholdModeConfig.PKCS11ShardStorage
+
+			keyFile := config.KeyFilePathCosigner(chainID)
+			if len(args) > 0 {
+				keyFile = args[0]
+			}
+
+			key, err := signer.LoadCosignerKey(keyFile)
+			if err != nil {
+				return fmt.Errorf("error reading cosigner key (%s): %w", keyFile, err)
+			}
+
+			// silence usage after all input has been validated
+			cmd.SilenceUsage = true
+
+			store, err := signer.OpenPKCS11ShardStore(pkcs11Config)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			label := pkcs11Config.KeyLabelFor(chainID)
+			if err := store.WriteCosignerKey(label, key); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported shard %d of %s into PKCS#11 token slot %d with label %s\n",
+				key.ShardID(), keyFile, pkcs11Config.Slot, label)
+			fmt.Fprintf(cmd.OutOrStdout(), "Remove %s from the key directory once the import is verified\n", keyFile)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagChainID, "", "chain ID of the shard")
+	_ = cmd.MarkFlagRequired(flagChainID)
+
+	return cmd
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/v3/signer"
+)
+
+const (
+	flagTarget  = "target"
+	flagHelpers = "helpers"
+)
+
+func recoverCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recover",
+		Short: "Recover a lost cosigner Ed25519 shard from the surviving cosigners",
+		Long: `Recover a lost cosigner Ed25519 shard from the surviving cosigners.
+
+The replacement cosigner for the --target shard ID and at least the threshold number of
+surviving cosigners (the helpers) must run this command at the same time with the same flags.
+Each helper sends a blinded contribution of its own shard, encrypted with the replacement
+cosigner's ECIES key, so that the replacement only learns its own shard for the same shard ID.
+
+The replacement cosigner needs the config.yaml and its ecies_keys.json in place.
+The horcrux signer must be stopped on the helpers while the ceremony is running.`,
+		Example: `horcrux shards recover --chain-id cosmoshub-4 --target 3 --helpers 1,2`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+
+			chainIDs, _ := flags.GetStringSlice(flagChainID)
+			target, _ := flags.GetInt(flagTarget)
+			helpers, _ := flags.GetIntSlice(flagHelpers)
+			timeout, _ := flags.GetDuration(flagTimeout)
+
+			if len(chainIDs) == 0 {
+				return fmt.Errorf("chain-id flag must not be empty")
+			}
+
+			if err := config.Config.ValidateThresholdModeConfig(); err != nil {
+				return err
+			}
+
+			thresholdCfg := config.Config.ThresholdModeConfig
+
+			if !cosignerConfigured(thresholdCfg.Cosigners, target) {
+				return fmt.Errorf("target %d is not a configured cosigner", target)
+			}
+
+			helpers = distinctShardIDs(helpers)
+			if len(helpers) == 0 {
+				for _, c := range thresholdCfg.Cosigners {
+					if c.ShardID != target {
+						helpers = append(helpers, c.ShardID)
+					}
+				}
+			}
+
+			for _, helper := range helpers {
+				if helper == target {
+					return fmt.Errorf("target %d can not be a helper", target)
+				}
+				if !cosignerConfigured(thresholdCfg.Cosigners, helper) {
+					return fmt.Errorf("helper %d is not a configured cosigner", helper)
+				}
+			}
+
+			if len(helpers) < thresholdCfg.Threshold {
+				return fmt.Errorf("number of helpers (%d) must be at least the threshold (%d)",
+					len(helpers), thresholdCfg.Threshold)
+			}
+
+			// silence usage after all input has been validated
+			cmd.SilenceUsage = true
+
+			out := cmd.OutOrStdout()
+			logger := cometlog.NewTMLogger(cometlog.NewSyncWriter(out))
+
+			if err := signer.RequireNotRunning(logger, config.PidFile); err != nil {
+				return err
+			}
+
+			security, err := config.CosignerSecurityECIES()
+			if err != nil {
+				return fmt.Errorf("failed to initialize cosigner ECIES security: %w", err)
+			}
+
+			id := security.GetID()
+
+			keys := make([]*signer.CosignerEd25519Key, len(chainIDs))
+			for i, chainID := range chainIDs {
+				keyFile := config.KeyFilePathCosigner(chainID)
+				if id == target {
+					if _, err := os.Stat(keyFile); err == nil {
+						return fmt.Errorf("cosigner shard already exists for chain ID %s: %s", chainID, keyFile)
+					}
+					continue
+				}
+				if !containsShardID(helpers, id) {
+					continue
+				}
+				key, err := signer.LoadCosignerEd25519Key(keyFile)
+				if err != nil {
+					return fmt.Errorf("error reading cosigner key: %w", err)
+				}
+				if key.ID != id {
+					return fmt.Errorf("key shard ID (%d) in (%s) does not match cosigner ID (%d)", key.ID, keyFile, id)
+				}
+				keys[i] = &key
+			}
+
+			if err := setNewKeyFilePassphrase(cmd); err != nil {
+				return err
+			}
+
+			creds, err := keyCeremonyCredentials(thresholdCfg.Cosigners)
+			if err != nil {
+				return err
+			}
+
+			ceremony, err := signer.NewKeyCeremony(logger, security, thresholdCfg.Cosigners, creds)
+			if err != nil {
+				return err
+			}
+
+			if err := ceremony.Start(); err != nil {
+				return fmt.Errorf("failed to start key ceremony: %w", err)
+			}
+			defer ceremony.Stop()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			for i, chainID := range chainIDs {
+				key, err := ceremony.Recover(ctx, chainID, keys[i], target, helpers, uint8(thresholdCfg.Threshold))
+				if err != nil {
+					return fmt.Errorf("recovery failed for chain ID %s: %w", chainID, err)
+				}
+
+				if key == nil {
+					fmt.Fprintf(out, "Sent recovery contribution for chain ID %s to cosigner %d\n", chainID, target)
+					continue
+				}
+
+				keyFile := config.KeyFilePathCosigner(chainID)
+				if err := signer.WriteCosignerEd25519ShardFile(*key, keyFile); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Recovered Ed25519 Shard %s\n", keyFile)
+			}
+
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringSlice(flagChainID, nil, "chain ID(s) of the shards to recover")
+	_ = cmd.MarkFlagRequired(flagChainID)
+	f.Int(flagTarget, 0, "shard ID of the cosigner to recover")
+	_ = cmd.MarkFlagRequired(flagTarget)
+	f.IntSlice(flagHelpers, nil, "shard IDs of the cosigners that help recover the shard (default all other cosigners)")
+	f.Duration(flagTimeout, 5*time.Minute, "time to wait for all cosigners to complete the ceremony")
+	addEncryptFlag(cmd)
+
+	return cmd
+}
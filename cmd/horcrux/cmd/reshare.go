@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/v3/signer"
+)
+
+const flagDealers = "dealers"
+
+func reshareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reshare",
+		Short: "Reshare cosigner Ed25519 shards to a new threshold and set of cosigners",
+		Long: `Reshare cosigner Ed25519 shards to a new threshold and set of cosigners without reconstructing the key.
+
+The current cosigners (the dealers) jointly deal new shards of the same key to the new set of
+cosigners given with --cosigner and --threshold. The combined public key stays identical, and
+shards from before the reshare can not be combined with the new shards. Running a reshare with
+the same threshold and cosigners refreshes all shards, e.g. after an operator leaves.
+
+Every dealer and every new cosigner must run this command at the same time with the same flags.
+Current cosigners that are not dealers and not part of the new set do not take part and may be offline.
+Cosigners that are new to the cluster need a copy of the current config.yaml. All participants
+need an ecies_keys.json that includes the keys of every participant.
+
+On success, the {chain-id}_shard.json files and the threshold mode config are replaced,
+and the raft directory is removed so that the cluster starts with the new membership.
+The horcrux signer must be stopped while the ceremony is running.`,
+		Example: `horcrux shards reshare --chain-id cosmoshub-4 --threshold 3 \
+  --cosigner tcp://horcrux-1:2222 --cosigner tcp://horcrux-2:2222 --cosigner tcp://horcrux-3:2222 \
+  --cosigner tcp://horcrux-4:2222 --cosigner tcp://horcrux-5:2222`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			flags := cmd.Flags()
+
+			chainIDs, _ := flags.GetStringSlice(flagChainID)
+			threshold, _ := flags.GetInt(flagThreshold)
+			cosignersFlag, _ := flags.GetStringSlice(flagCosigner)
+			dealers, _ := flags.GetIntSlice(flagDealers)
+			timeout, _ := flags.GetDuration(flagTimeout)
+
+			if len(chainIDs) == 0 {
+				return fmt.Errorf("chain-id flag must not be empty")
+			}
+
+			if err := config.Config.ValidateThresholdModeConfig(); err != nil {
+				return err
+			}
+
+			currentCfg := config.Config.ThresholdModeConfig
+
+			cosigners, err := signer.CosignersFromFlag(cosignersFlag)
+			if err != nil {
+				return err
+			}
+
+			newCfg := &signer.ThresholdModeConfig{
+				Threshold:   threshold,
+				Cosigners:   cosigners,
+				GRPCTimeout: currentCfg.GRPCTimeout,
+				RaftTimeout: currentCfg.RaftTimeout,
+			}
+
+			newConfig := config.Config
+			newConfig.ThresholdModeConfig = newCfg
+			if err := newConfig.ValidateThresholdModeConfig(); err != nil {
+				return fmt.Errorf("invalid new cosigner configuration: %w", err)
+			}
+
+			if len(dealers) == 0 {
+				for _, c := range currentCfg.Cosigners {
+					dealers = append(dealers, c.ShardID)
+				}
+			}
+			dealers = distinctShardIDs(dealers)
+
+			for _, dealer := range dealers {
+				if !cosignerConfigured(currentCfg.Cosigners, dealer) {
+					return fmt.Errorf("dealer %d is not a current cosigner", dealer)
+				}
+			}
+
+			if len(dealers) < currentCfg.Threshold {
+				return fmt.Errorf("number of dealers (%d) must be at least the current threshold (%d)",
+					len(dealers), currentCfg.Threshold)
+			}
+
+			receivers := make([]int, len(cosigners))
+			for i, c := range cosigners {
+				receivers[i] = c.ShardID
+			}
+
+			// silence usage after all input has been validated
+			cmd.SilenceUsage = true
+
+			out := cmd.OutOrStdout()
+			logger := cometlog.NewTMLogger(cometlog.NewSyncWriter(out))
+
+			if err := signer.RequireNotRunning(logger, config.PidFile); err != nil {
+				return err
+			}
+
+			security, err := config.CosignerSecurityECIES()
+			if err != nil {
+				return fmt.Errorf("failed to initialize cosigner ECIES security: %w", err)
+			}
+
+			id := security.GetID()
+
+			if !containsShardID(dealers, id) && !cosignerConfigured(cosigners, id) {
+				return fmt.Errorf("cosigner %d is neither a dealer nor a new cosigner, it does not take part in the reshare", id)
+			}
+
+			var currentKeys []*signer.CosignerEd25519Key
+			for _, chainID := range chainIDs {
+				var key *signer.CosignerEd25519Key
+				if containsShardID(dealers, id) {
+					keyFile, err := config.KeyFileExistsCosigner(chainID)
+					if err != nil {
+						return err
+					}
+					k, err := signer.LoadCosignerEd25519Key(keyFile)
+					if err != nil {
+						return fmt.Errorf("error reading cosigner key: %w", err)
+					}
+					if k.ID != id {
+						return fmt.Errorf("key shard ID (%d) in (%s) does not match cosigner ID (%d)", k.ID, keyFile, id)
+					}
+					key = &k
+				}
+				currentKeys = append(currentKeys, key)
+			}
+
+			participants := reshareParticipants(currentCfg.Cosigners, dealers, cosigners)
+
+			if err := setNewKeyFilePassphrase(cmd); err != nil {
+				return err
+			}
+
+			creds, err := keyCeremonyCredentials(participants)
+			if err != nil {
+				return err
+			}
+
+			ceremony, err := signer.NewKeyCeremony(logger, security, participants, creds)
+			if err != nil {
+				return err
+			}
+
+			if err := ceremony.Start(); err != nil {
+				return fmt.Errorf("failed to start key ceremony: %w", err)
+			}
+			defer ceremony.Stop()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			newKeys := make([]*signer.CosignerEd25519Key, len(chainIDs))
+			for i, chainID := range chainIDs {
+				newKeys[i], err = ceremony.Reshare(ctx, chainID, currentKeys[i], dealers, receivers, uint8(threshold))
+				if err != nil {
+					return fmt.Errorf("reshare failed for chain ID %s: %w", chainID, err)
+				}
+			}
+
+			// All chains were reshared successfully, persist the new shards and config.
+			for i, chainID := range chainIDs {
+				keyFile := config.KeyFilePathCosigner(chainID)
+				if newKeys[i] == nil {
+					if err := os.Remove(keyFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+						return err
+					}
+					fmt.Fprintf(out, "Removed Ed25519 Shard %s, cosigner %d is not part of the new set\n", keyFile, id)
+					continue
+				}
+				if err := signer.WriteCosignerEd25519ShardFile(*newKeys[i], keyFile); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "Created Ed25519 Shard %s\n", keyFile)
+			}
+
+			config.Config = newConfig
+			if err := config.WriteConfigFile(); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "Updated threshold mode config %s\n", config.ConfigFile)
+
+			if err := os.RemoveAll(filepath.Join(config.HomeDir, "raft")); err != nil {
+				return fmt.Errorf("error removing raft directory: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	f := cmd.Flags()
+	f.StringSlice(flagChainID, nil, "chain ID(s) of the shards to reshare")
+	_ = cmd.MarkFlagRequired(flagChainID)
+	f.IntP(flagThreshold, "t", 0, "new number of shards required for threshold signature")
+	_ = cmd.MarkFlagRequired(flagThreshold)
+	f.StringSliceP(flagCosigner, "c", []string{},
+		`new cosigners in format tcp://{cosigner-addr}:{p2p-port}
+(e.g. --cosigner tcp://horcrux-1:2222 --cosigner tcp://horcrux-2:2222 --cosigner tcp://horcrux-3:2222)`)
+	_ = cmd.MarkFlagRequired(flagCosigner)
+	f.IntSlice(flagDealers, nil,
+		"shard IDs of the current cosigners that deal the new shards (default all current cosigners)")
+	f.Duration(flagTimeout, 5*time.Minute, "time to wait for all cosigners to complete the ceremony")
+	addEncryptFlag(cmd)
+
+	return cmd
+}
+
+// reshareParticipants returns the union of the dealers and the new cosigners.
+// Current cosigners that are not dealers are left out, so they may be offline.
+// The address of a cosigner in the new set takes precedence.
+func reshareParticipants(current signer.CosignersConfig, dealers []int, next signer.CosignersConfig) signer.CosignersConfig {
+	addrs := make(map[int]string, len(dealers)+len(next))
+	for _, c := range current {
+		if containsShardID(dealers, c.ShardID) {
+			addrs[c.ShardID] = c.P2PAddr
+		}
+	}
+	for _, c := range next {
+		addrs[c.ShardID] = c.P2PAddr
+	}
+
+	out := make(signer.CosignersConfig, 0, len(addrs))
+	for id, addr := range addrs {
+		out = append(out, signer.CosignerConfig{ShardID: id, P2PAddr: addr})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ShardID < out[j].ShardID
+	})
+	return out
+}
+
+func cosignerConfigured(cosigners signer.CosignersConfig, id int) bool {
+	for _, c := range cosigners {
+		if c.ShardID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// distinctShardIDs returns the ids without repetitions, in the order they were given.
+func distinctShardIDs(ids []int) []int {
+	var out []int
+	for _, id := range ids {
+		if !containsShardID(out, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func containsShardID(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -24,7 +24,12 @@ func rootCmd() *cobra.Command {
 	cmd.AddCommand(startCmd())
 	cmd.AddCommand(addressCmd())
 	cmd.AddCommand(createCosignerEd25519ShardsCmd())
+	cmd.AddCommand(createCosignerBLS12381ShardsCmd())
 	cmd.AddCommand(createCosignerECIESShardsCmd())
+	cmd.AddCommand(createCosignerX25519ShardsCmd())
+	cmd.AddCommand(createCosignerTLSCertsCmd())
+	cmd.AddCommand(dkgCmd())
+	cmd.AddCommand(shardsCmd())
 
 	rsaCmd := createCosignerRSAShardsCmd()
 	rsaCmd.Deprecated = `
@@ -38,6 +43,8 @@ horcrux create-ecies-shards
 	cmd.AddCommand(rsaCmd)
 	cmd.AddCommand(leaderElectionCmd())
 	cmd.AddCommand(getLeaderCmd())
+	cmd.AddCommand(haltHeightCmd())
+	cmd.AddCommand(sentryCmd())
 	cmd.AddCommand(stateCmd())
 	cmd.AddCommand(versionCmd())
 
@@ -47,6 +54,9 @@ horcrux create-ecies-shards
 		"",
 		"Directory for config and data (default is $HOME/.horcrux)",
 	)
+	addPassphraseFlag(cmd)
+
+	signer.SetKeyPassphraseProvider(keyPassphrase)
 
 	return cmd
 }
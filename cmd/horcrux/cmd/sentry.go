@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/v3/signer/proto"
+)
+
+func sentryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sentry",
+		Short: "Commands to manage sentries quarantined for conflicting votes",
+		Long: `Commands to manage sentries quarantined for conflicting votes.
+
+With quarantineConflictingSentries, a sentry that requests a vote for a different block than the majority
+of the sentries, or for two different blocks, at the same height, round and step is quarantined,
+and its sign requests are rejected until it is cleared.
+In threshold mode the quarantine is replicated to all cosigners through raft. In single signer mode
+sentries are quarantined until horcrux restarts.`,
+	}
+
+	cmd.AddCommand(clearSentryCmd())
+
+	return cmd
+}
+
+func clearSentryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear sentry",
+		Short: "Resume serving a quarantined sentry",
+		Long: `Resume serving a quarantined sentry once it is confirmed to be safe.
+
+The sentry is the pinned nodeID of the chain node, or its privValAddr if it has no nodeID,
+as logged when it was quarantined.`,
+		Example: `horcrux sentry clear tcp://10.168.0.1:1234
+horcrux sentry clear 3f6e9b7c58d1c2a4e0f1b2c3d4e5f60718293a4b`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := dialLeader()
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			ctx, cancelFunc := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancelFunc()
+
+			_, err = proto.NewCosignerClient(conn).ClearSentryQuarantine(
+				ctx,
+				&proto.ClearSentryQuarantineRequest{Sentry: args[0]},
+			)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Sentry %s is no longer quarantined\n", args[0])
+			return nil
+		},
+	}
+}
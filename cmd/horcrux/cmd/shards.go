@@ -50,6 +50,24 @@ const (
 	flagChainID   = "chain-id"
 )
 
+// shardsCmd is a cobra command for managing existing cosigner shards.
+func shardsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shards",
+		Short: "Commands to manage existing cosigner shards",
+	}
+
+	cmd.AddCommand(reshareCmd())
+	cmd.AddCommand(recoverCmd())
+	cmd.AddCommand(combineCmd())
+	cmd.AddCommand(encryptCmd())
+	cmd.AddCommand(decryptCmd())
+	cmd.AddCommand(importPKCS11Cmd())
+	cmd.AddCommand(exportTmkmsKeyCmd())
+
+	return cmd
+}
+
 func addOutputDirFlag(cmd *cobra.Command) {
 	cmd.Flags().StringP(flagOutputDir, "", "", "output directory")
 }
@@ -62,10 +80,55 @@ func addTotalShardsFlag(cmd *cobra.Command) {
 // createCosignerEd25519ShardsCmd is a cobra command for creating
 // cosigner shards from a full priv validator key.
 func createCosignerEd25519ShardsCmd() *cobra.Command {
+	return createCosignerShardsCmd(
+		"create-ed25519-shards",
+		"Create cosigner Ed25519 shards",
+		"Ed25519",
+		func(keyFile string, threshold, shards uint8) ([]signer.CosignerKey, error) {
+			keys, err := signer.CreateCosignerEd25519ShardsFromFile(keyFile, threshold, shards)
+			if err != nil {
+				return nil, err
+			}
+			out := make([]signer.CosignerKey, len(keys))
+			for i := range keys {
+				out[i] = &keys[i]
+			}
+			return out, nil
+		},
+	)
+}
+
+// createCosignerBLS12381ShardsCmd is a cobra command for creating
+// cosigner shards from a full priv validator key with a BLS12-381 key.
+func createCosignerBLS12381ShardsCmd() *cobra.Command {
+	return createCosignerShardsCmd(
+		"create-bls-shards",
+		"Create cosigner BLS12-381 shards",
+		"BLS12-381",
+		func(keyFile string, threshold, shards uint8) ([]signer.CosignerKey, error) {
+			keys, err := signer.CreateCosignerBLS12381ShardsFromFile(keyFile, threshold, shards)
+			if err != nil {
+				return nil, err
+			}
+			out := make([]signer.CosignerKey, len(keys))
+			for i := range keys {
+				out[i] = &keys[i]
+			}
+			return out, nil
+		},
+	)
+}
+
+// createCosignerShardsCmd is a cobra command for creating cosigner shards of the key type
+// from a full priv validator key.
+func createCosignerShardsCmd(
+	use, short, keyType string,
+	createShards func(keyFile string, threshold, shards uint8) ([]signer.CosignerKey, error),
+) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "create-ed25519-shards",
+		Use:   use,
 		Args:  cobra.NoArgs,
-		Short: "Create cosigner Ed25519 shards",
+		Short: short,
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			flags := cmd.Flags()
 
@@ -112,7 +175,7 @@ func createCosignerEd25519ShardsCmd() *cobra.Command {
 				return nil
 			}
 
-			csKeys, err := signer.CreateCosignerEd25519ShardsFromFile(keyFile, threshold, shards)
+			csKeys, err := createShards(keyFile, threshold, shards)
 			if err != nil {
 				return err
 			}
@@ -127,28 +190,33 @@ func createCosignerEd25519ShardsCmd() *cobra.Command {
 			// silence usage after all input has been validated
 			cmd.SilenceUsage = true
 
+			if err := setNewKeyFilePassphrase(cmd); err != nil {
+				return err
+			}
+
 			for _, c := range csKeys {
-				dir, err := createCosignerDirectoryIfNecessary(out, c.ID)
+				dir, err := createCosignerDirectoryIfNecessary(out, c.ShardID())
 				if err != nil {
 					return err
 				}
 				filename := filepath.Join(dir, fmt.Sprintf("%s_shard.json", chainID))
-				if err = signer.WriteCosignerEd25519ShardFile(c, filename); err != nil {
+				if err = signer.WriteCosignerShardFile(c, filename); err != nil {
 					return err
 				}
-				fmt.Fprintf(cmd.OutOrStdout(), "Created Ed25519 Shard %s\n", filename)
+				fmt.Fprintf(cmd.OutOrStdout(), "Created %s Shard %s\n", keyType, filename)
 			}
 			return nil
 		},
 	}
 
 	addOutputDirFlag(cmd)
+	addEncryptFlag(cmd)
 	addTotalShardsFlag(cmd)
 
 	f := cmd.Flags()
 	f.Uint8(flagThreshold, 0, "threshold number of shards required to successfully sign")
 	_ = cmd.MarkFlagRequired(flagThreshold)
-	f.String(flagKeyFile, "", "priv_validator_key.json file to shard")
+	f.String(flagKeyFile, "", "priv_validator_key.json or tmkms softsign key file to shard")
 	_ = cmd.MarkFlagRequired(flagKeyFile)
 	f.String(flagChainID, "", "key shards will sign for this chain ID")
 	_ = cmd.MarkFlagRequired(flagChainID)
@@ -185,6 +253,10 @@ func createCosignerECIESShardsCmd() *cobra.Command {
 			// silence usage after all input has been validated
 			cmd.SilenceUsage = true
 
+			if err := setNewKeyFilePassphrase(cmd); err != nil {
+				return err
+			}
+
 			for _, c := range csKeys {
 				dir, err := createCosignerDirectoryIfNecessary(out, c.ID)
 				if err != nil {
@@ -201,6 +273,63 @@ func createCosignerECIESShardsCmd() *cobra.Command {
 	}
 	addTotalShardsFlag(cmd)
 	addOutputDirFlag(cmd)
+	addEncryptFlag(cmd)
+	return cmd
+}
+
+// createCosignerX25519ShardsCmd is a cobra command for creating cosigner-to-cosigner encryption X25519 keys.
+func createCosignerX25519ShardsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-x25519-shards",
+		Args:  cobra.NoArgs,
+		Short: "Create cosigner X25519 shards",
+		Long: `Create X25519 and Ed25519 keys for cosigner-to-cosigner encryption and authentication.
+Nonces are encrypted with X25519 and ChaCha20-Poly1305 instead of ECIES, which uses less CPU.
+All cosigners of a cluster must use the same kind of keys.`,
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			shards, _ := cmd.Flags().GetUint8(flagShards)
+
+			if shards <= 0 {
+				return fmt.Errorf("shards must be greater than zero (%d): %w", shards, err)
+			}
+
+			csKeys, err := signer.CreateCosignerX25519Shards(int(shards))
+			if err != nil {
+				return err
+			}
+
+			out, _ := cmd.Flags().GetString(flagOutputDir)
+			if out != "" {
+				if err := os.MkdirAll(out, 0700); err != nil {
+					return err
+				}
+			}
+
+			// silence usage after all input has been validated
+			cmd.SilenceUsage = true
+
+			if err := setNewKeyFilePassphrase(cmd); err != nil {
+				return err
+			}
+
+			for _, c := range csKeys {
+				dir, err := createCosignerDirectoryIfNecessary(out, c.ID)
+				if err != nil {
+					return err
+				}
+				filename := filepath.Join(dir, "x25519_keys.json")
+				if err = signer.WriteCosignerX25519ShardFile(c, filename); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Created X25519 Shard %s\n", filename)
+			}
+			return nil
+		},
+	}
+	addTotalShardsFlag(cmd)
+	addOutputDirFlag(cmd)
+	addEncryptFlag(cmd)
 	return cmd
 }
 
@@ -233,6 +362,10 @@ func createCosignerRSAShardsCmd() *cobra.Command {
 			// silence usage after all input has been validated
 			cmd.SilenceUsage = true
 
+			if err := setNewKeyFilePassphrase(cmd); err != nil {
+				return err
+			}
+
 			for _, c := range csKeys {
 				dir, err := createCosignerDirectoryIfNecessary(out, c.ID)
 				if err != nil {
@@ -249,5 +382,61 @@ func createCosignerRSAShardsCmd() *cobra.Command {
 	}
 	addTotalShardsFlag(cmd)
 	addOutputDirFlag(cmd)
+	addEncryptFlag(cmd)
+	return cmd
+}
+
+// createCosignerTLSCertsCmd is a cobra command for creating the certificates of mutual TLS between cosigners.
+func createCosignerTLSCertsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-tls-certs",
+		Args:  cobra.NoArgs,
+		Short: "Create cosigner TLS certificates",
+		Long: `Create a CA and a TLS certificate for each cosigner, for mutual TLS on the p2p port.
+The private key of the CA is not kept, so the certificates of all cosigners must be
+created again to add a cosigner.`,
+
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			shards, _ := cmd.Flags().GetUint8(flagShards)
+
+			if shards <= 0 {
+				return fmt.Errorf("shards must be greater than zero (%d): %w", shards, err)
+			}
+
+			tlsKeys, err := signer.CreateCosignerTLSCerts(int(shards))
+			if err != nil {
+				return err
+			}
+
+			out, _ := cmd.Flags().GetString(flagOutputDir)
+			if out != "" {
+				if err := os.MkdirAll(out, 0700); err != nil {
+					return err
+				}
+			}
+
+			// silence usage after all input has been validated
+			cmd.SilenceUsage = true
+
+			if err := setNewKeyFilePassphrase(cmd); err != nil {
+				return err
+			}
+
+			for _, c := range tlsKeys {
+				dir, err := createCosignerDirectoryIfNecessary(out, c.ID)
+				if err != nil {
+					return err
+				}
+				if err = signer.WriteCosignerTLSFiles(c, dir); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Created TLS certificate %s\n", filepath.Join(dir, signer.CosignerTLSCertFile))
+			}
+			return nil
+		},
+	}
+	addTotalShardsFlag(cmd)
+	addOutputDirFlag(cmd)
+	addEncryptFlag(cmd)
 	return cmd
 }
@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"fmt"
 	"io"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/cometbft/cometbft/crypto/ed25519"
+	cometjson "github.com/cometbft/cometbft/libs/json"
 	"github.com/cometbft/cometbft/privval"
+	"github.com/strangelove-ventures/horcrux/v3/signer"
 	"github.com/stretchr/testify/require"
 )
 
@@ -104,6 +109,83 @@ func TestEd25519Shards(t *testing.T) {
 	}
 }
 
+func TestBLS12381Shards(t *testing.T) {
+	tmp := t.TempDir()
+
+	privKey, err := signer.GenPrivKeyBLS12381()
+	require.NoError(t, err)
+	blsKeyFile := filepath.Join(tmp, "priv_validator_key.json")
+	bz, err := cometjson.Marshal(privval.FilePVKey{
+		Address: privKey.PubKey().Address(),
+		PubKey:  privKey.PubKey(),
+		PrivKey: privKey,
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(blsKeyFile, bz, 0600))
+
+	ed25519KeyFile := filepath.Join(tmp, "ed25519_priv_validator_key.json")
+	pv := privval.NewFilePV(ed25519.GenPrivKey(), ed25519KeyFile, filepath.Join(tmp, "priv_validator_state.json"))
+	pv.Save()
+
+	tcs := []struct {
+		name      string
+		cmd       string
+		keyFile   string
+		expectErr bool
+	}{
+		{
+			name:      "bls key",
+			cmd:       "create-bls-shards",
+			keyFile:   blsKeyFile,
+			expectErr: false,
+		},
+		{
+			name:      "ed25519 key",
+			cmd:       "create-bls-shards",
+			keyFile:   ed25519KeyFile,
+			expectErr: true,
+		},
+		{
+			name:      "bls key as ed25519 shards",
+			cmd:       "create-ed25519-shards",
+			keyFile:   blsKeyFile,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := rootCmd()
+			cmd.SetOutput(io.Discard)
+			cmd.SetArgs([]string{
+				tc.cmd, "--home", tmp, "--out", tmp,
+				"--chain-id", testChainID,
+				"--key-file", tc.keyFile,
+				"--threshold", "2",
+				"--shards", "3",
+			})
+			err := cmd.Execute()
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	for id := 1; id <= 3; id++ {
+		shardFile := filepath.Join(tmp, fmt.Sprintf("cosigner_%d", id), testChainID+"_shard.json")
+		key, err := signer.LoadCosignerBLS12381Key(shardFile)
+		require.NoError(t, err)
+		require.Equal(t, id, key.ID)
+		require.True(t, privKey.PubKey().Equals(key.PubKey))
+
+		// BLS12-381 shards can not be loaded as Ed25519 shards
+		_, err = signer.LoadCosignerEd25519Key(shardFile)
+		require.ErrorContains(t, err, "bls12_381 key shard is not an ed25519 key shard")
+	}
+}
+
 func TestRSAShards(t *testing.T) {
 	tmp := t.TempDir()
 
@@ -139,3 +221,210 @@ func TestRSAShards(t *testing.T) {
 		})
 	}
 }
+
+func TestX25519Shards(t *testing.T) {
+	tmp := t.TempDir()
+
+	tcs := []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{
+			name:      "valid shards",
+			args:      []string{"--shards", "3"},
+			expectErr: false,
+		},
+		{
+			name:      "invalid shards",
+			args:      []string{"--shards", "0"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := rootCmd()
+			cmd.SetOutput(io.Discard)
+			args := append([]string{"create-x25519-shards", "--home", tmp, "--out", tmp}, tc.args...)
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestEncryptNewShards(t *testing.T) {
+	tmp := t.TempDir()
+
+	t.Setenv(envPassphrase, "passphrase")
+	t.Cleanup(func() { signer.SetNewKeyFilePassphrase(nil) })
+
+	cmd := rootCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{"create-ecies-shards", "--home", tmp, "--out", tmp, "--shards", "3", "--encrypt"})
+	require.NoError(t, cmd.Execute())
+
+	for id := 1; id <= 3; id++ {
+		bz, err := os.ReadFile(filepath.Join(tmp, fmt.Sprintf("cosigner_%d", id), "ecies_keys.json"))
+		require.NoError(t, err)
+		require.True(t, signer.IsEncryptedKeyFile(bz))
+	}
+}
+
+func TestTLSCerts(t *testing.T) {
+	tmp := t.TempDir()
+
+	tcs := []struct {
+		name      string
+		args      []string
+		expectErr bool
+	}{
+		{
+			name:      "valid shards",
+			args:      []string{"--shards", "3"},
+			expectErr: false,
+		},
+		{
+			name:      "invalid shards",
+			args:      []string{"--shards", "0"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := rootCmd()
+			cmd.SetOutput(io.Discard)
+			args := append([]string{"create-tls-certs", "--home", tmp, "--out", tmp}, tc.args...)
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	for id := 1; id <= 3; id++ {
+		dir := filepath.Join(tmp, fmt.Sprintf("cosigner_%d", id))
+		for _, file := range []string{signer.CosignerTLSCertFile, signer.CosignerTLSKeyFile, signer.CosignerTLSCAFile} {
+			require.FileExists(t, filepath.Join(dir, file))
+		}
+	}
+}
+
+func TestCombineShards(t *testing.T) {
+	tmp := t.TempDir()
+
+	privValidatorKeyFile := filepath.Join(tmp, "priv_validator_key.json")
+	privValidatorStateFile := filepath.Join(tmp, "priv_validator_state.json")
+	pv := privval.NewFilePV(ed25519.GenPrivKey(), privValidatorKeyFile, privValidatorStateFile)
+	pv.Save()
+
+	cmd := rootCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{
+		"create-ed25519-shards", "--home", tmp, "--out", tmp,
+		"--chain-id", testChainID,
+		"--key-file", privValidatorKeyFile,
+		"--threshold", "2",
+		"--shards", "3",
+	})
+	require.NoError(t, cmd.Execute())
+
+	shardFile := func(id int) string {
+		return filepath.Join(tmp, fmt.Sprintf("cosigner_%d", id), testChainID+"_shard.json")
+	}
+
+	combinedDir := filepath.Join(tmp, "combined")
+
+	tcs := []struct {
+		name      string
+		args      []string
+		stdin     string
+		expectErr bool
+	}{
+		{
+			name:      "below threshold",
+			args:      []string{"--yes", shardFile(1)},
+			expectErr: true,
+		},
+		{
+			name:      "not confirmed",
+			args:      []string{shardFile(1), shardFile(2)},
+			stdin:     "no\n",
+			expectErr: true,
+		},
+		{
+			name:      "confirmed",
+			args:      []string{shardFile(1), shardFile(3)},
+			stdin:     "COMBINE\n",
+			expectErr: false,
+		},
+		{
+			name:      "key file exists",
+			args:      []string{"--yes", shardFile(2), shardFile(3)},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := rootCmd()
+			cmd.SetOutput(io.Discard)
+			cmd.SetIn(strings.NewReader(tc.stdin))
+			args := append([]string{
+				"shards", "combine", "--home", tmp, "--out", combinedDir, "--chain-id", testChainID,
+			}, tc.args...)
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			if tc.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	combined, err := signer.ReadPrivValidatorFile(filepath.Join(combinedDir, testChainID+"_priv_validator_key.json"))
+	require.NoError(t, err)
+	require.Equal(t, pv.Key.PubKey, combined.PubKey)
+	require.Equal(t, pv.Key.Address, combined.Address)
+
+	msg := []byte("test")
+	sig, err := combined.PrivKey.Sign(msg)
+	require.NoError(t, err)
+	require.True(t, pv.Key.PubKey.VerifySignature(msg, sig))
+
+	// the single signer loads the combined key like any other priv-validator key
+	filePV, err := signer.LoadFilePV(filepath.Join(combinedDir, testChainID+"_priv_validator_key.json"), "", false)
+	require.NoError(t, err)
+	require.Equal(t, pv.Key.PubKey, filePV.Key.PubKey)
+
+	// the combined key is encrypted when it is written with --encrypt
+	t.Setenv(envPassphrase, "passphrase")
+	t.Cleanup(func() { signer.SetNewKeyFilePassphrase(nil) })
+
+	encryptedDir := filepath.Join(tmp, "encrypted")
+	combineEncrypted := func(args ...string) error {
+		cmd := rootCmd()
+		cmd.SetOutput(io.Discard)
+		cmd.SetArgs(append([]string{
+			"shards", "combine", "--home", tmp, "--chain-id", testChainID, "--yes", "--encrypt",
+			shardFile(1), shardFile(2),
+		}, args...))
+		return cmd.Execute()
+	}
+	require.Error(t, combineEncrypted("--stdout"))
+	require.NoError(t, combineEncrypted("--out", encryptedDir))
+
+	bz, err := os.ReadFile(filepath.Join(encryptedDir, testChainID+"_priv_validator_key.json"))
+	require.NoError(t, err)
+	require.True(t, signer.IsEncryptedKeyFile(bz))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 
+	cometlog "github.com/cometbft/cometbft/libs/log"
 	"github.com/strangelove-ventures/horcrux/v3/signer"
 )
 
@@ -22,6 +23,7 @@ as it is not officially supported by Strangelove.`
 
 func NewSingleSignerValidator(
 	out io.Writer,
+	logger cometlog.Logger,
 	acceptRisk bool,
 ) (*signer.SingleSignerValidator, error) {
 	fmt.Fprintln(out, singleSignerWarning)
@@ -34,5 +36,15 @@ func NewSingleSignerValidator(
 		return nil, err
 	}
 
-	return signer.NewSingleSignerValidator(&config), nil
+	for _, chain := range config.Config.Chains {
+		if chain.SentryQuorum != nil {
+			return nil, fmt.Errorf("sentryQuorum for chain id (%s) is only supported in threshold mode", chain.ChainID)
+		}
+	}
+
+	val := signer.NewSingleSignerValidator(&config)
+	val.SetSentryMonitor(signer.NewSentryMonitor(
+		logger, signer.NewLocalSentryQuarantine(), config.Config.QuarantineConflictingSentries))
+
+	return val, nil
 }
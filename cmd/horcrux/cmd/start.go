@@ -29,6 +29,10 @@ func startCmd() *cobra.Command {
 				return fmt.Errorf("this is a legacy config. run `horcrux config migrate` to migrate to the latest format")
 			}
 
+			if err := requireKeyPassphrase(); err != nil {
+				return fmt.Errorf("failed to unlock encrypted key files: %w", err)
+			}
+
 			// create all directories up to the state directory
 			if err = os.MkdirAll(config.StateDir, 0700); err != nil {
 				return err
@@ -52,7 +56,7 @@ func startCmd() *cobra.Command {
 					return err
 				}
 			case signer.SignModeSingle:
-				val, err = NewSingleSignerValidator(out, acceptRisk)
+				val, err = NewSingleSignerValidator(out, logger, acceptRisk)
 				if err != nil {
 					return err
 				}
@@ -60,8 +64,10 @@ func startCmd() *cobra.Command {
 				panic(fmt.Errorf("unexpected sign mode: %s", config.Config.SignMode))
 			}
 
+			chains := signer.NewChainAllowlist(config.Config.Chains)
+
 			if config.Config.GRPCAddr != "" {
-				grpcServer := signer.NewRemoteSignerGRPCServer(logger, val, config.Config.GRPCAddr)
+				grpcServer := signer.NewRemoteSignerGRPCServer(logger, val, chains, config.Config.GRPCAddr)
 				services = append(services, grpcServer)
 
 				if err := grpcServer.Start(); err != nil {
@@ -71,11 +77,24 @@ func startCmd() *cobra.Command {
 
 			go EnableDebugAndMetrics(cmd.Context(), out)
 
-			services, err = signer.StartRemoteSigners(services, logger, val, config.Config.Nodes(), config.Config.MaxReadSize)
+			connKey, err := signer.LoadOrGenConnectionKey(config.KeyFilePathConnection())
+			if err != nil {
+				return fmt.Errorf("failed to load connection key: %w", err)
+			}
+			logger.Info("Connecting to chain nodes", "node_id", connKey.ID())
+
+			services, err = signer.StartRemoteSigners(
+				services, logger, val, chains, connKey.PrivKey, config.Config.DialNodes(), config.Config.MaxReadSize)
 			if err != nil {
 				return fmt.Errorf("failed to start remote signer(s): %w", err)
 			}
 
+			services, err = signer.StartRemoteSignerListeners(
+				services, logger, val, chains, connKey.PrivKey, config.Config.PrivValListeners, config.Config.MaxReadSize)
+			if err != nil {
+				return fmt.Errorf("failed to start privval listener(s): %w", err)
+			}
+
 			signer.WaitAndTerminate(logger, services, config.PidFile)
 
 			return nil
@@ -3,6 +3,7 @@ package cmd
 import (
 	"bufio"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -15,15 +16,20 @@ import (
 
 	cometjson "github.com/cometbft/cometbft/libs/json"
 	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/privval"
 )
 
-// Snippet Taken from https://raw.githubusercontent.com/cometbft/cometbft/main/privval/file.go
-// FilePVLastSignState stores the mutable part of PrivValidator.
-type FilePVLastSignState struct {
-	Height int64 `json:"height"`
-	Round  int32 `json:"round"`
-	Step   int8  `json:"step"`
-}
+const (
+	flagInterchange = "interchange"
+	flagFormat      = "format"
+)
+
+// state export formats
+const (
+	stateFormatInterchange = "interchange"
+	stateFormatCometBFT    = "cometbft"
+	stateFormatTmkms       = "tmkms"
+)
 
 func stateCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -34,6 +40,7 @@ func stateCmd() *cobra.Command {
 	cmd.AddCommand(showStateCmd())
 	cmd.AddCommand(setStateCmd())
 	cmd.AddCommand(importStateCmd())
+	cmd.AddCommand(exportStateCmd())
 
 	return cmd
 }
@@ -139,16 +146,24 @@ func setStateCmd() *cobra.Command {
 }
 
 func importStateCmd() *cobra.Command {
-	return &cobra.Command{
-		Use:     "import chain-id",
+	cmd := &cobra.Command{
+		Use:     "import [chain-id]",
 		Aliases: []string{"i"},
 		Short: "Read the old priv_validator_state.json and set the height, round and step" +
 			"(good for migrations but NOT shared state update)",
-		Args:         cobra.ExactArgs(1),
+		Long: "Read the old priv_validator_state.json and set the height, round and step " +
+			"(good for migrations but NOT shared state update). " +
+			"A tmkms consensus state file, state/{chain-id}_consensus.json, is also accepted.\n\n" +
+			"With --interchange, set the high watermarks of all chains in a sign state interchange file " +
+			"instead, as written by horcrux state export. No high watermark is lowered.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if interchange, _ := cmd.Flags().GetString(flagInterchange); interchange != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			chainID := args[0]
-
 			if _, err := os.Stat(config.HomeDir); os.IsNotExist(err) {
 				cmd.SilenceUsage = false
 				return fmt.Errorf("%s does not exist, initialize config with horcrux config init and try again", config.HomeDir)
@@ -163,6 +178,12 @@ func importStateCmd() *cobra.Command {
 				return err
 			}
 
+			if interchange, _ := cmd.Flags().GetString(flagInterchange); interchange != "" {
+				return importStateInterchange(out, interchange)
+			}
+
+			chainID := args[0]
+
 			// Recreate privValStateFile if necessary
 			pv, err := signer.LoadOrCreateSignState(config.PrivValStateFile(chainID))
 			if err != nil {
@@ -180,7 +201,8 @@ func importStateCmd() *cobra.Command {
 			fmt.Fprintln(out, "IMPORTANT: Your validator should already be STOPPED.  You must copy the latest state..")
 			<-time.After(2 * time.Second)
 			fmt.Fprintln(out, "")
-			fmt.Fprintln(out, "Paste your old priv_validator_state.json.  Input a blank line after the pasted JSON to continue.")
+			fmt.Fprintln(out, "Paste your old priv_validator_state.json or tmkms consensus state.  "+
+				"Input a blank line after the pasted JSON to continue.")
 			fmt.Fprintln(out, "")
 
 			var textBuffer strings.Builder
@@ -194,9 +216,7 @@ func importStateCmd() *cobra.Command {
 			}
 			finalJSON := textBuffer.String()
 
-			pvState := &FilePVLastSignState{}
-
-			err = cometjson.Unmarshal([]byte(finalJSON), &pvState)
+			hrs, err := signer.ParseConsensusState([]byte(finalJSON))
 			if err != nil {
 				fmt.Println("Error parsing priv_validator_state.json")
 				return err
@@ -204,9 +224,9 @@ func importStateCmd() *cobra.Command {
 
 			pv.NoncePublic = nil
 			signState := signer.SignStateConsensus{
-				Height:    pvState.Height,
-				Round:     int64(pvState.Round),
-				Step:      pvState.Step,
+				Height:    hrs.Height,
+				Round:     hrs.Round,
+				Step:      hrs.Step,
 				Signature: nil,
 				SignBytes: nil,
 			}
@@ -230,6 +250,216 @@ func importStateCmd() *cobra.Command {
 			return nil
 		},
 	}
+
+	cmd.Flags().String(flagInterchange, "", "sign state interchange file to import the high watermarks of all chains from")
+
+	return cmd
+}
+
+func exportStateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "export [chain-id]",
+		Aliases: []string{"e"},
+		Short:   "Print the high watermarks of all chains as a sign state interchange file",
+		Long: "Print the high watermarks of all chains as a sign state interchange file, " +
+			"to be imported with horcrux state import --interchange. " +
+			"The sign bytes and signature of the last signed block are included when known.\n\n" +
+			"With --format cometbft or --format tmkms, print the high watermark of a single chain as a " +
+			"priv_validator_state.json or as a tmkms consensus state file instead, for leaving horcrux.",
+		Example: `horcrux state export > interchange.json
+horcrux state export cosmoshub-4 --format cometbft > priv_validator_state.json
+horcrux state export cosmoshub-4 --format tmkms > cosmoshub-4_consensus.json`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if format, _ := cmd.Flags().GetString(flagFormat); format == stateFormatInterchange {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.ExactArgs(1)(cmd, args)
+		},
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stat(config.HomeDir); os.IsNotExist(err) {
+				cmd.SilenceUsage = false
+				return fmt.Errorf("%s does not exist, initialize config with horcrux config init and try again", config.HomeDir)
+			}
+
+			format, _ := cmd.Flags().GetString(flagFormat)
+
+			chainIDs := args
+			switch format {
+			case stateFormatInterchange:
+				var err error
+				if chainIDs, err = config.StateChainIDs(); err != nil {
+					return err
+				}
+			case stateFormatCometBFT, stateFormatTmkms:
+				if _, err := os.Stat(config.PrivValStateFile(chainIDs[0])); err != nil {
+					return fmt.Errorf("no sign state for chain id %s: %w", chainIDs[0], err)
+				}
+			default:
+				cmd.SilenceUsage = false
+				return fmt.Errorf("invalid format (%s), must be %s, %s or %s",
+					format, stateFormatInterchange, stateFormatCometBFT, stateFormatTmkms)
+			}
+
+			// export is read-only, a missing sign state file is exported as empty and is not created
+			chains := make([]signer.ChainSignStateInterchange, 0, len(chainIDs))
+			for _, chainID := range chainIDs {
+				pv, err := signer.LoadSignStateIfExists(config.PrivValStateFile(chainID))
+				if err != nil {
+					return err
+				}
+				cs, err := signer.LoadSignStateIfExists(config.CosignerStateFile(chainID))
+				if err != nil {
+					return err
+				}
+				chains = append(chains, signer.NewChainSignStateInterchange(chainID, pv, cs))
+			}
+
+			var (
+				bz  []byte
+				err error
+			)
+			switch format {
+			case stateFormatInterchange:
+				bz, err = cometjson.MarshalIndent(signer.NewSignStateInterchange(chains...), "", "  ")
+			case stateFormatCometBFT:
+				bz, err = cometjson.MarshalIndent(privval.FilePVLastSignState{
+					Height:    chains[0].Height,
+					Round:     int32(chains[0].Round),
+					Step:      chains[0].Step,
+					Signature: chains[0].Signature,
+					SignBytes: chains[0].SignBytes,
+				}, "", "  ")
+			case stateFormatTmkms:
+				bz, err = json.MarshalIndent(signer.NewTmkmsConsensusState(chains[0].HRSKey()), "", "  ")
+			}
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), string(bz))
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagFormat, stateFormatInterchange, fmt.Sprintf("output format (%s, %s or %s)",
+		stateFormatInterchange, stateFormatCometBFT, stateFormatTmkms))
+
+	return cmd
+}
+
+// importStateInterchange sets the high watermarks of all chains in the sign state interchange file.
+// Nothing is written if any chain is not in the chains allowlist, or if any high watermark would be lowered,
+// including the high watermark of the slashing protection database.
+func importStateInterchange(out io.Writer, file string) error {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	ic, err := signer.LoadSignStateInterchange(bz)
+	if err != nil {
+		return err
+	}
+
+	slashingProtection, err := openSlashingProtectionDBIfExists()
+	if err != nil {
+		return err
+	}
+	if slashingProtection != nil {
+		defer slashingProtection.Close()
+	}
+
+	type chainSignStates struct {
+		chain  signer.ChainSignStateInterchange
+		pv, cs *signer.SignState
+	}
+
+	// the sign state files are only created once all chains were validated
+	var updates []chainSignStates
+	for _, chain := range ic.Chains {
+		if len(config.Config.Chains) > 0 {
+			if _, ok := config.Config.Chains.Get(chain.ChainID); !ok {
+				return fmt.Errorf("[%s] chain id is not in the chains allowlist", chain.ChainID)
+			}
+		}
+
+		pv, err := signer.LoadSignStateIfExists(config.PrivValStateFile(chain.ChainID))
+		if err != nil {
+			return err
+		}
+		cs, err := signer.LoadSignStateIfExists(config.CosignerStateFile(chain.ChainID))
+		if err != nil {
+			return err
+		}
+
+		updatePV, err := chain.CheckImport(pv)
+		if err != nil {
+			return err
+		}
+		updateCS, err := chain.CheckImport(cs)
+		if err != nil {
+			return err
+		}
+		if slashingProtection != nil {
+			if err := chain.CheckSlashingProtection(slashingProtection); err != nil {
+				return err
+			}
+		}
+		if !updatePV && !updateCS {
+			fmt.Fprintf(out, "[%s] Already at height %d, round %d, step %d\n",
+				chain.ChainID, chain.Height, chain.Round, chain.Step)
+			continue
+		}
+
+		updates = append(updates, chainSignStates{chain: chain, pv: pv, cs: cs})
+	}
+
+	for _, u := range updates {
+		fmt.Fprintf(out, "[%s] Saving height %d, round %d, step %d\n",
+			u.chain.ChainID, u.chain.Height, u.chain.Round, u.chain.Step)
+
+		u.pv.NoncePublic, u.cs.NoncePublic = nil, nil
+		if err := u.pv.Save(u.chain.SignStateConsensus(), nil); err != nil && !isSameHRSError(err) {
+			return fmt.Errorf("[%s] error saving privval sign state: %w", u.chain.ChainID, err)
+		}
+
+		// the share sign state holds partial signatures, so only the height, round and step are set
+		shareSignState := signer.NewSignStateConsensus(u.chain.Height, u.chain.Round, u.chain.Step)
+		if err := u.cs.Save(shareSignState, nil); err != nil && !isSameHRSError(err) {
+			return fmt.Errorf("[%s] error saving share sign state: %w", u.chain.ChainID, err)
+		}
+	}
+
+	fmt.Fprintln(out, "Update Successful")
+	return nil
+}
+
+// openSlashingProtectionDBIfExists opens the slashing protection database of the threshold signer,
+// or returns nil if it is disabled or does not exist, so that it is not created.
+func openSlashingProtectionDBIfExists() (*signer.SlashingProtectionDB, error) {
+	thresholdCfg := config.Config.ThresholdModeConfig
+	if config.Config.SignMode != signer.SignModeThreshold || thresholdCfg == nil {
+		return nil, nil
+	}
+	if spCfg := thresholdCfg.SlashingProtection; spCfg != nil && spCfg.Disabled {
+		return nil, nil
+	}
+
+	file := config.SlashingProtectionDBFile()
+	if _, err := os.Stat(file); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unexpected error checking file existence (%s): %w", file, err)
+	}
+
+	return signer.OpenSlashingProtectionDB(file, 0)
+}
+
+func isSameHRSError(err error) bool {
+	_, ok := err.(*signer.SameHRSError)
+	return ok
 }
 
 func printSignState(out io.Writer, ss *signer.SignState) {
@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"bytes"
 	"io"
+	"os"
 	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
 
+	cometjson "github.com/cometbft/cometbft/libs/json"
 	"github.com/strangelove-ventures/horcrux/v3/signer"
 	"github.com/stretchr/testify/require"
 )
@@ -85,3 +88,182 @@ func TestStateSetCmd(t *testing.T) {
 		})
 	}
 }
+
+func TestStateInterchangeCmd(t *testing.T) {
+	tmpHome := t.TempDir()
+	tmpConfig := filepath.Join(tmpHome, ".horcrux")
+	stateDir := filepath.Join(tmpHome, ".horcrux", "state")
+
+	cmd := rootCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{
+		"--home", tmpConfig,
+		"config", "init",
+		"-n", "tcp://10.168.0.1:1234",
+		"-t", "2",
+		"-c", "tcp://10.168.1.1:2222,tcp://10.168.1.2:2222,tcp://10.168.1.3:2222",
+	})
+	require.NoError(t, cmd.Execute())
+
+	for chainID, height := range map[string]string{"horcrux-1": "100", "horcrux-2": "200"} {
+		cmd := setStateCmd()
+		cmd.SetOutput(io.Discard)
+		cmd.SetArgs([]string{chainID, height})
+		require.NoError(t, cmd.Execute())
+	}
+
+	var out bytes.Buffer
+	cmd = exportStateCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs(nil)
+	require.NoError(t, cmd.Execute())
+
+	ic, err := signer.LoadSignStateInterchange(out.Bytes())
+	require.NoError(t, err)
+	require.Len(t, ic.Chains, 2)
+	require.Equal(t, "horcrux-1", ic.Chains[0].ChainID)
+	require.Equal(t, int64(100), ic.Chains[0].Height)
+	require.Equal(t, "horcrux-2", ic.Chains[1].ChainID)
+	require.Equal(t, int64(200), ic.Chains[1].Height)
+
+	importInterchange := func(ic *signer.SignStateInterchange) error {
+		bz, err := cometjson.Marshal(ic)
+		require.NoError(t, err)
+		file := filepath.Join(tmpHome, "interchange.json")
+		require.NoError(t, os.WriteFile(file, bz, 0600))
+
+		cmd := importStateCmd()
+		cmd.SetOutput(io.Discard)
+		cmd.SetArgs([]string{"--" + flagInterchange, file})
+		return cmd.Execute()
+	}
+
+	requireHeight := func(chainID string, height int64) {
+		for _, file := range []string{"_priv_validator_state.json", "_share_sign_state.json"} {
+			ss, err := signer.LoadSignState(filepath.Join(stateDir, chainID+file))
+			require.NoError(t, err)
+			require.Equal(t, height, ss.Height)
+		}
+	}
+
+	// raise the high watermark of one chain, and add a new chain
+	ic.Chains[0].Height = 150
+	ic.Chains = append(ic.Chains, signer.ChainSignStateInterchange{ChainID: "horcrux-3", Height: 300, Step: 3})
+	require.NoError(t, importInterchange(ic))
+	requireHeight("horcrux-1", 150)
+	requireHeight("horcrux-2", 200)
+	requireHeight("horcrux-3", 300)
+
+	// nothing is imported if any high watermark would be lowered
+	ic.Chains[0].Height = 400
+	ic.Chains[1].Height = 50
+	require.Error(t, importInterchange(ic))
+	requireHeight("horcrux-1", 150)
+	requireHeight("horcrux-2", 200)
+
+	// no sign state files are created for a new chain if a later chain is rejected
+	ic.Chains = append([]signer.ChainSignStateInterchange{{ChainID: "horcrux-4", Height: 400}}, ic.Chains...)
+	require.Error(t, importInterchange(ic))
+	require.NoFileExists(t, filepath.Join(stateDir, "horcrux-4_priv_validator_state.json"))
+	require.NoFileExists(t, filepath.Join(stateDir, "horcrux-4_share_sign_state.json"))
+	ic.Chains = ic.Chains[1:]
+
+	// nothing is imported if the slashing protection database holds a greater HRS
+	ic.Chains[1].Height = 200
+	db, err := signer.OpenSlashingProtectionDB(config.SlashingProtectionDBFile(), 0)
+	require.NoError(t, err)
+	require.NoError(t, db.Record("cosigner", "horcrux-2", signer.NewSlashingProtectionRecord(signer.SignStateConsensus{
+		Height:    250,
+		Step:      3,
+		SignBytes: []byte("sign bytes"),
+		Signature: []byte("signature"),
+	})))
+	require.NoError(t, db.Close())
+	require.ErrorContains(t, importInterchange(ic), "slashing protection database")
+	requireHeight("horcrux-1", 150)
+	requireHeight("horcrux-2", 200)
+
+	ic.Chains[1].Height = 250
+	ic.Chains[1].Step = 3
+	require.NoError(t, importInterchange(ic))
+	requireHeight("horcrux-1", 400)
+	requireHeight("horcrux-2", 250)
+
+	// chains that are not in the chains allowlist are rejected
+	config.Config.Chains = signer.ChainsConfig{{ChainID: "horcrux-1"}, {ChainID: "horcrux-2"}}
+	require.NoError(t, config.WriteConfigFile())
+	ic.Chains[0].Height = 500
+	require.ErrorContains(t, importInterchange(ic), "not in the chains allowlist")
+	requireHeight("horcrux-1", 400)
+
+	// an unsupported version is rejected
+	ic.Chains = ic.Chains[:2]
+	require.NoError(t, importInterchange(ic))
+	requireHeight("horcrux-1", 500)
+	ic.Chains[0].Height = 600
+	ic.Version = signer.SignStateInterchangeVersion + 1
+	require.Error(t, importInterchange(ic))
+	requireHeight("horcrux-1", 500)
+}
+
+func TestStateExportFormats(t *testing.T) {
+	tmpHome := t.TempDir()
+	tmpConfig := filepath.Join(tmpHome, ".horcrux")
+
+	chainID := "horcrux-1"
+
+	cmd := rootCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{
+		"--home", tmpConfig,
+		"config", "init",
+		"-n", "tcp://10.168.0.1:1234",
+		"-t", "2",
+		"-c", "tcp://10.168.1.1:2222,tcp://10.168.1.2:2222,tcp://10.168.1.3:2222",
+	})
+	require.NoError(t, cmd.Execute())
+
+	cmd = setStateCmd()
+	cmd.SetOutput(io.Discard)
+	cmd.SetArgs([]string{chainID, "100"})
+	require.NoError(t, cmd.Execute())
+
+	export := func(args ...string) ([]byte, error) {
+		var out bytes.Buffer
+		cmd := exportStateCmd()
+		cmd.SetOut(&out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		return out.Bytes(), err
+	}
+
+	for _, format := range []string{stateFormatCometBFT, stateFormatTmkms} {
+		bz, err := export(chainID, "--"+flagFormat, format)
+		require.NoError(t, err)
+
+		hrs, err := signer.ParseConsensusState(bz)
+		require.NoError(t, err)
+		require.Equal(t, int64(100), hrs.Height)
+	}
+
+	_, err := export("--"+flagFormat, stateFormatTmkms)
+	require.Error(t, err)
+
+	_, err = export("horcrux-2", "--"+flagFormat, stateFormatCometBFT)
+	require.Error(t, err)
+
+	_, err = export(chainID, "--"+flagFormat, "unknown")
+	require.Error(t, err)
+
+	// export is read-only, a missing sign state file is not created
+	shareStateFile := filepath.Join(tmpConfig, "state", chainID+"_share_sign_state.json")
+	require.NoError(t, os.Remove(shareStateFile))
+	bz, err := export()
+	require.NoError(t, err)
+	ic, err := signer.LoadSignStateInterchange(bz)
+	require.NoError(t, err)
+	require.Len(t, ic.Chains, 1)
+	require.Equal(t, int64(100), ic.Chains[0].Height)
+	require.NoFileExists(t, shareStateFile)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -28,20 +29,19 @@ func NewThresholdValidator(
 
 	var p2pListen string
 
-	var security signer.CosignerSecurity
-	var eciesErr error
-	security, eciesErr = config.CosignerSecurityECIES()
-	if eciesErr != nil {
-		var rsaErr error
-		security, rsaErr = config.CosignerSecurityRSA()
-		if rsaErr != nil {
-			return nil, nil, fmt.Errorf("failed to initialize cosigner ECIES / RSA security : %w / %w", eciesErr, rsaErr)
-		}
+	security, err := newCosignerSecurity()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	creds, err := config.CosignerTransportCredentials()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize cosigner transport credentials: %w", err)
 	}
 
 	for _, c := range thresholdCfg.Cosigners {
 		if c.ShardID != security.GetID() {
-			rc, err := signer.NewRemoteCosigner(c.ShardID, c.P2PAddr)
+			rc, err := signer.NewRemoteCosigner(c.ShardID, c.P2PAddr, creds, security)
 			if err != nil {
 				return nil, nil, fmt.Errorf("failed to initialize remote cosigner: %w", err)
 			}
@@ -65,6 +65,19 @@ func NewThresholdValidator(
 		p2pListen,
 	)
 
+	var slashingProtection *signer.SlashingProtectionDB
+	if spCfg := thresholdCfg.SlashingProtection; spCfg == nil || !spCfg.Disabled {
+		var retainHeights int64
+		if spCfg != nil {
+			retainHeights = spCfg.RetainHeights
+		}
+		slashingProtection, err = signer.OpenSlashingProtectionDB(config.SlashingProtectionDBFile(), retainHeights)
+		if err != nil {
+			return nil, nil, err
+		}
+		localCosigner.SetSlashingProtection(slashingProtection)
+	}
+
 	// Validated prior in ValidateThresholdModeConfig
 	grpcTimeout, _ := time.ParseDuration(thresholdCfg.GRPCTimeout)
 	raftTimeout, _ := time.ParseDuration(thresholdCfg.RaftTimeout)
@@ -80,11 +93,22 @@ func NewThresholdValidator(
 	// Start RAFT store listener
 	raftStore := signer.NewRaftStore(nodeID,
 		raftDir, p2pListen, raftTimeout, logger, localCosigner, remoteCosigners)
+	raftStore.SetTransportCredentials(creds)
+	localCosigner.SetHaltHeights(raftStore)
 	if err := raftStore.Start(); err != nil {
 		return nil, nil, fmt.Errorf("error starting raft store: %w", err)
 	}
 	services := []cometservice.Service{raftStore}
 
+	if slashingProtection != nil {
+		// close the slashing protection database on shutdown, which releases its file lock
+		closer := newCloserService(logger, "SlashingProtectionDB", slashingProtection)
+		if err := closer.Start(); err != nil {
+			return nil, nil, err
+		}
+		services = append(services, closer)
+	}
+
 	val := signer.NewThresholdValidator(
 		logger,
 		&config,
@@ -97,6 +121,12 @@ func NewThresholdValidator(
 	)
 
 	raftStore.SetThresholdValidator(val)
+	val.SetHaltHeights(raftStore)
+	val.SetSentryMonitor(signer.NewSentryMonitor(logger, raftStore, config.Config.QuarantineConflictingSentries))
+
+	if slashingProtection != nil {
+		val.SetSlashingProtection(slashingProtection)
+	}
 
 	if err := val.Start(ctx); err != nil {
 		return nil, nil, fmt.Errorf("failed to start threshold validator: %w", err)
@@ -104,3 +134,62 @@ func NewThresholdValidator(
 
 	return services, val, nil
 }
+
+// closerService is a service that closes its closer when it stops.
+type closerService struct {
+	cometservice.BaseService
+
+	closer io.Closer
+}
+
+func newCloserService(logger cometlog.Logger, name string, closer io.Closer) *closerService {
+	s := &closerService{closer: closer}
+	s.BaseService = *cometservice.NewBaseService(logger, name, s)
+	return s
+}
+
+// OnStop implements cmn.Service.
+func (s *closerService) OnStop() {
+	if err := s.closer.Close(); err != nil {
+		s.Logger.Error("Failed to close", "service", s.String(), "err", err)
+	}
+}
+
+// newCosignerSecurity returns the security of our cosigner with its X25519, ECIES and RSA keys,
+// in this order of preference. A cosigner that holds the keys of several schemes negotiates
+// the scheme with each peer, so that a cluster can migrate between schemes one cosigner at a time.
+func newCosignerSecurity() (signer.CosignerSecurity, error) {
+	var securities []signer.CosignerSecurity
+
+	if _, err := config.KeyFileExistsCosignerX25519(); err == nil {
+		security, err := config.CosignerSecurityX25519()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize cosigner X25519 security: %w", err)
+		}
+		securities = append(securities, security)
+	}
+	if _, err := config.KeyFileExistsCosignerECIES(); err == nil {
+		security, err := config.CosignerSecurityECIES()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize cosigner ECIES security: %w", err)
+		}
+		securities = append(securities, security)
+	}
+	if _, err := config.KeyFileExistsCosignerRSA(); err == nil {
+		security, err := config.CosignerSecurityRSA()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize cosigner RSA security: %w", err)
+		}
+		securities = append(securities, security)
+	}
+
+	switch len(securities) {
+	case 0:
+		return nil, fmt.Errorf("failed to initialize cosigner security, no key file at %s, %s or %s",
+			config.KeyFilePathCosignerX25519(), config.KeyFilePathCosignerECIES(), config.KeyFilePathCosignerRSA())
+	case 1:
+		return securities[0], nil
+	default:
+		return signer.NewCosignerSecurityMulti(securities...)
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/strangelove-ventures/horcrux/v3/signer"
+)
+
+func exportTmkmsKeyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export-tmkms-key key-file",
+		Short: "Print a priv-validator key as a tmkms softsign key file",
+		Long: `Print an Ed25519 priv_validator_key.json as a tmkms softsign key file in the base64 key format,
+for leaving horcrux for tmkms.
+
+Only keys with the Ed25519 seed can be exported. Keys combined from shards with horcrux shards combine
+only hold the secret scalar, which tmkms can not load.`,
+		Example: `horcrux shards export-tmkms-key ~/.horcrux/cosmoshub-4_priv_validator_key.json > cosmoshub-4-consensus.key`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// silence usage after all input has been validated
+			cmd.SilenceUsage = true
+
+			pv, err := signer.ReadPrivValidatorFile(args[0])
+			if err != nil {
+				return fmt.Errorf("error reading priv-validator key %s: %w", args[0], err)
+			}
+
+			bz, err := signer.TmkmsSoftsignKey(pv)
+			if err != nil {
+				return err
+			}
+
+			_, err = cmd.OutOrStdout().Write(bz)
+			return err
+		},
+	}
+}
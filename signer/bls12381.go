@@ -0,0 +1,338 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	cometcrypto "github.com/cometbft/cometbft/crypto"
+	cometjson "github.com/cometbft/cometbft/libs/json"
+)
+
+const (
+	PubKeyBLS12381Name  = "cometbft/PubKeyBls12_381"
+	PrivKeyBLS12381Name = "cometbft/PrivKeyBls12_381"
+	KeyTypeBLS12381     = "bls12_381"
+
+	BLS12381PubKeySize    = bls12381.G1SizeCompressed
+	BLS12381PrivKeySize   = bls12381.ScalarSize
+	BLS12381SignatureSize = bls12381.G2SizeCompressed
+
+	// blsMaxMsgLen is the maximum length of a message that is signed as is,
+	// longer messages are hashed with SHA-256 first, as CometBFT does.
+	blsMaxMsgLen = 32
+
+	// publicKeyFieldBLS12381 is the field number of BLS12-381 keys in the CometBFT PublicKey oneof.
+	publicKeyFieldBLS12381 = 3
+)
+
+// blsDST is the domain separation tag of the proof of possession scheme of the IETF BLS signature draft,
+// with public keys in G1 and signatures in G2.
+var blsDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+func init() {
+	cometjson.RegisterType(PubKeyBLS12381{}, PubKeyBLS12381Name)
+	cometjson.RegisterType(PrivKeyBLS12381{}, PrivKeyBLS12381Name)
+}
+
+// PubKeyBLS12381 is a compressed BLS12-381 public key in G1, compatible with the BLS12-381
+// consensus keys of CometBFT v1.
+type PubKeyBLS12381 []byte
+
+var _ cometcrypto.PubKey = PubKeyBLS12381{}
+
+// Address is the SHA-256 hash of the public key, truncated to 20 bytes.
+func (pubKey PubKeyBLS12381) Address() cometcrypto.Address {
+	return cometcrypto.AddressHash(pubKey)
+}
+
+// Bytes returns the compressed public key.
+func (pubKey PubKeyBLS12381) Bytes() []byte {
+	return []byte(pubKey)
+}
+
+// VerifySignature verifies a compressed BLS12-381 signature of msg in G2.
+func (pubKey PubKeyBLS12381) VerifySignature(msg []byte, sig []byte) bool {
+	return blsVerify(pubKey, msg, sig) == nil
+}
+
+// Equals compares the public keys.
+func (pubKey PubKeyBLS12381) Equals(other cometcrypto.PubKey) bool {
+	if otherKey, ok := other.(PubKeyBLS12381); ok {
+		return bytes.Equal(pubKey, otherKey)
+	}
+	return false
+}
+
+// Type returns the key type.
+func (pubKey PubKeyBLS12381) Type() string {
+	return KeyTypeBLS12381
+}
+
+func (pubKey PubKeyBLS12381) String() string {
+	return fmt.Sprintf("PubKeyBLS12381{%X}", []byte(pubKey))
+}
+
+// PrivKeyBLS12381 is a BLS12-381 secret key, the big-endian encoding of a scalar.
+type PrivKeyBLS12381 []byte
+
+var _ cometcrypto.PrivKey = PrivKeyBLS12381{}
+
+// GenPrivKeyBLS12381 generates a random BLS12-381 private key.
+func GenPrivKeyBLS12381() (PrivKeyBLS12381, error) {
+	s, err := blsRandomScalar()
+	if err != nil {
+		return nil, err
+	}
+	bz, err := s.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return PrivKeyBLS12381(bz), nil
+}
+
+func (privKey PrivKeyBLS12381) scalar() (*bls12381.Scalar, error) {
+	return blsScalarFromBytes(privKey)
+}
+
+// Bytes returns the big-endian encoding of the secret scalar.
+func (privKey PrivKeyBLS12381) Bytes() []byte {
+	return []byte(privKey)
+}
+
+// Sign produces a compressed BLS12-381 signature of msg in G2.
+func (privKey PrivKeyBLS12381) Sign(msg []byte) ([]byte, error) {
+	s, err := privKey.scalar()
+	if err != nil {
+		return nil, err
+	}
+	return blsSign(s, msg), nil
+}
+
+// PubKey returns the BLS12-381 public key of the secret scalar.
+func (privKey PrivKeyBLS12381) PubKey() cometcrypto.PubKey {
+	s, err := privKey.scalar()
+	if err != nil {
+		panic(err)
+	}
+	return PubKeyBLS12381(blsPublicKey(s))
+}
+
+// Equals compares the private keys in constant time.
+func (privKey PrivKeyBLS12381) Equals(other cometcrypto.PrivKey) bool {
+	if otherKey, ok := other.(PrivKeyBLS12381); ok {
+		return subtle.ConstantTimeCompare(privKey, otherKey) == 1
+	}
+	return false
+}
+
+// Type returns the key type.
+func (privKey PrivKeyBLS12381) Type() string {
+	return KeyTypeBLS12381
+}
+
+// blsScalarFromBytes decodes a non-zero, canonically encoded scalar.
+func blsScalarFromBytes(bz []byte) (*bls12381.Scalar, error) {
+	if len(bz) != BLS12381PrivKeySize {
+		return nil, fmt.Errorf("invalid bls12-381 scalar length: %d", len(bz))
+	}
+	s := new(bls12381.Scalar)
+	if err := s.UnmarshalBinary(bz); err != nil {
+		return nil, fmt.Errorf("invalid bls12-381 scalar: %w", err)
+	}
+	if s.IsZero() == 1 {
+		return nil, errors.New("invalid bls12-381 scalar: zero")
+	}
+	return s, nil
+}
+
+// blsPointFromBytes decodes a compressed public key, or public key shard, in G1.
+func blsPointFromBytes(bz []byte) (*bls12381.G1, error) {
+	if len(bz) != BLS12381PubKeySize {
+		return nil, fmt.Errorf("invalid bls12-381 public key length: %d", len(bz))
+	}
+	p := new(bls12381.G1)
+	if err := p.SetBytes(bz); err != nil {
+		return nil, fmt.Errorf("invalid bls12-381 public key: %w", err)
+	}
+	if p.IsIdentity() {
+		return nil, errors.New("invalid bls12-381 public key: identity")
+	}
+	return p, nil
+}
+
+// blsSignatureFromBytes decodes a compressed signature, or signature share, in G2.
+func blsSignatureFromBytes(bz []byte) (*bls12381.G2, error) {
+	if len(bz) != BLS12381SignatureSize {
+		return nil, fmt.Errorf("invalid bls12-381 signature length: %d", len(bz))
+	}
+	sig := new(bls12381.G2)
+	if err := sig.SetBytes(bz); err != nil {
+		return nil, fmt.Errorf("invalid bls12-381 signature: %w", err)
+	}
+	return sig, nil
+}
+
+func blsRandomScalar() (*bls12381.Scalar, error) {
+	s := new(bls12381.Scalar)
+	for s.IsZero() == 1 {
+		if err := s.Random(rand.Reader); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func blsScalarFromInt(i int) *bls12381.Scalar {
+	s := new(bls12381.Scalar)
+	s.SetUint64(uint64(i))
+	return s
+}
+
+// blsPublicKey returns the compressed public key, or public key shard, of the scalar.
+func blsPublicKey(s *bls12381.Scalar) []byte {
+	p := new(bls12381.G1)
+	p.ScalarMult(s, bls12381.G1Generator())
+	return p.BytesCompressed()
+}
+
+// blsHashToPoint hashes the message to G2. Messages longer than blsMaxMsgLen are hashed
+// with SHA-256 first, as CometBFT does for sign bytes.
+func blsHashToPoint(msg []byte) *bls12381.G2 {
+	if len(msg) > blsMaxMsgLen {
+		hash := sha256.Sum256(msg)
+		msg = hash[:]
+	}
+	p := new(bls12381.G2)
+	p.Hash(msg, blsDST)
+	return p
+}
+
+// blsSign returns the compressed signature of msg with the scalar, which may be a key shard.
+func blsSign(s *bls12381.Scalar, msg []byte) []byte {
+	sig := new(bls12381.G2)
+	sig.ScalarMult(s, blsHashToPoint(msg))
+	return sig.BytesCompressed()
+}
+
+// blsVerify verifies the signature of msg against the public key, which may be a public key shard,
+// by checking that e(pubKey, H(msg)) == e(G1, sig).
+func blsVerify(pubKey, msg, sig []byte) error {
+	p, err := blsPointFromBytes(pubKey)
+	if err != nil {
+		return err
+	}
+	s, err := blsSignatureFromBytes(sig)
+	if err != nil {
+		return err
+	}
+	if !bls12381.ProdPairFrac(
+		[]*bls12381.G1{p, bls12381.G1Generator()},
+		[]*bls12381.G2{blsHashToPoint(msg), s},
+		[]int{1, -1},
+	).IsIdentity() {
+		return errors.New("invalid bls12-381 signature")
+	}
+	return nil
+}
+
+// blsLagrangeCoefficient returns the lagrange coefficient at zero for the shamir index id
+// among the shamir indexes ids, over the BLS12-381 scalar field.
+func blsLagrangeCoefficient(id int, ids []int) (*bls12381.Scalar, error) {
+	num := blsScalarFromInt(1)
+	den := blsScalarFromInt(1)
+	found := false
+
+	is := blsScalarFromInt(id)
+
+	for _, j := range ids {
+		if j == id {
+			found = true
+			continue
+		}
+		js := blsScalarFromInt(j)
+		num.Mul(num, js)
+		diff := new(bls12381.Scalar)
+		diff.Sub(js, is)
+		den.Mul(den, diff)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("shamir index %d is not in %v", id, ids)
+	}
+	if den.IsZero() == 1 {
+		return nil, fmt.Errorf("duplicate shamir index in %v", ids)
+	}
+
+	den.Inv(den)
+	num.Mul(num, den)
+	return num, nil
+}
+
+// blsDealShares splits the secret into shamir shares for the shamir indexes 1 to shards,
+// any threshold of which can reconstruct the secret.
+func blsDealShares(secret *bls12381.Scalar, threshold, shards uint8) ([]*bls12381.Scalar, error) {
+	if threshold == 0 || threshold > shards {
+		return nil, fmt.Errorf("invalid threshold %d for %d shards", threshold, shards)
+	}
+
+	coefficients := make([]*bls12381.Scalar, threshold)
+	coefficients[0] = secret
+	for i := 1; i < len(coefficients); i++ {
+		c, err := blsRandomScalar()
+		if err != nil {
+			return nil, err
+		}
+		coefficients[i] = c
+	}
+
+	out := make([]*bls12381.Scalar, shards)
+	for i := range out {
+		x := blsScalarFromInt(i + 1)
+
+		// horner's method
+		share := new(bls12381.Scalar)
+		for j := len(coefficients) - 1; j >= 0; j-- {
+			share.Mul(share, x)
+			share.Add(share, coefficients[j])
+		}
+		out[i] = share
+	}
+
+	return out, nil
+}
+
+// marshalPubKeyProtoBLS12381 encodes the public key as a CometBFT PublicKey protobuf message.
+// The CometBFT v0.38 protos predate BLS12-381 consensus keys, so the message is encoded by hand.
+func marshalPubKeyProtoBLS12381(pubKey PubKeyBLS12381) []byte {
+	return appendProtoBytes(nil, publicKeyFieldBLS12381, pubKey)
+}
+
+// unmarshalPubKeyProtoBLS12381 decodes a CometBFT PublicKey protobuf message holding a BLS12-381 key.
+// It returns false if the message holds a different key type.
+func unmarshalPubKeyProtoBLS12381(bz []byte) (PubKeyBLS12381, bool) {
+	tag, n := binary.Uvarint(bz)
+	if n <= 0 || tag != publicKeyFieldBLS12381<<3|protoWireBytes {
+		return nil, false
+	}
+	length, m := binary.Uvarint(bz[n:])
+	if m <= 0 || length != BLS12381PubKeySize || len(bz) != n+m+BLS12381PubKeySize {
+		return nil, false
+	}
+	return PubKeyBLS12381(bz[n+m:]), true
+}
+
+// protoWireBytes is the protobuf wire type of length delimited fields.
+const protoWireBytes = 2
+
+// appendProtoBytes appends a length delimited protobuf field to b.
+func appendProtoBytes(b []byte, field uint64, value []byte) []byte {
+	b = binary.AppendUvarint(b, field<<3|protoWireBytes)
+	b = binary.AppendUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
@@ -0,0 +1,82 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	cometjson "github.com/cometbft/cometbft/libs/json"
+	cometprivval "github.com/cometbft/cometbft/privval"
+	cometprotoprivval "github.com/cometbft/cometbft/proto/tendermint/privval"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivKeyBLS12381Sign(t *testing.T) {
+	// test vector of the consensus specs of Ethereum, which uses the same BLS signature scheme
+	sk, err := hex.DecodeString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3")
+	require.NoError(t, err)
+	expected, err := hex.DecodeString("b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6" +
+		"076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e" +
+		"850ce1f98458c0cfc9ab380b55285a55")
+	require.NoError(t, err)
+
+	privKey := PrivKeyBLS12381(sk)
+	msg := make([]byte, 32)
+
+	sig, err := privKey.Sign(msg)
+	require.NoError(t, err)
+	require.Equal(t, expected, sig)
+
+	pubKey := privKey.PubKey()
+	require.Len(t, pubKey.Bytes(), BLS12381PubKeySize)
+	require.True(t, pubKey.VerifySignature(msg, sig))
+	require.False(t, pubKey.VerifySignature([]byte("other"), sig))
+
+	// messages longer than 32 bytes are hashed before signing
+	long := bytes.Repeat([]byte{1}, 100)
+	sig, err = privKey.Sign(long)
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature(long, sig))
+}
+
+func TestPrivKeyBLS12381JSON(t *testing.T) {
+	privKey, err := GenPrivKeyBLS12381()
+	require.NoError(t, err)
+
+	bz, err := cometjson.Marshal(cometprivval.FilePVKey{
+		Address: privKey.PubKey().Address(),
+		PubKey:  privKey.PubKey(),
+		PrivKey: privKey,
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(bz), PrivKeyBLS12381Name)
+
+	var decoded cometprivval.FilePVKey
+	require.NoError(t, cometjson.Unmarshal(bz, &decoded))
+	require.True(t, privKey.Equals(decoded.PrivKey))
+	require.True(t, privKey.PubKey().Equals(decoded.PubKey))
+}
+
+func TestPubKeyResponseBLS12381(t *testing.T) {
+	privKey, err := GenPrivKeyBLS12381()
+	require.NoError(t, err)
+	pubKey := privKey.PubKey().(PubKeyBLS12381)
+
+	bz, err := (&pubKeyResponseBLS12381{pubKey: pubKey}).Marshal()
+	require.NoError(t, err)
+
+	// the CometBFT v0.38 protos decode the message as a PubKeyResponse with a public key of an unknown type
+	var msg cometprotoprivval.Message
+	require.NoError(t, msg.Unmarshal(bz))
+	res, ok := msg.Sum.(*cometprotoprivval.Message_PubKeyResponse)
+	require.True(t, ok)
+	require.Nil(t, res.PubKeyResponse.Error)
+	require.Nil(t, res.PubKeyResponse.PubKey.Sum)
+
+	// and the public key is encoded as the bls12381 field of the PublicKey of CometBFT v1
+	publicKey := marshalPubKeyProtoBLS12381(pubKey)
+	require.True(t, bytes.HasSuffix(bz, publicKey))
+	decoded, ok := unmarshalPubKeyProtoBLS12381(publicKey)
+	require.True(t, ok)
+	require.Equal(t, pubKey, decoded)
+}
@@ -0,0 +1,241 @@
+package signer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChainConfig is the on disk config format for a chain the signer signs for.
+type ChainConfig struct {
+	ChainID string `yaml:"chainID"`
+	// Disabled rejects all requests for the chain, without removing its configuration.
+	Disabled bool `yaml:"disabled,omitempty"`
+	// HaltHeight is the last height that is signed for the chain, e.g. the height before a chain upgrade.
+	// If zero, the chain does not halt. In threshold mode it can be lifted at runtime with horcrux halt lift.
+	HaltHeight int64 `yaml:"haltHeight,omitempty"`
+	// KeyFile overrides the key file of the chain, {chainID}_priv_validator_key.json in single signer mode
+	// or {chainID}_shard.json in threshold mode. A relative path is relative to the key directory.
+	KeyFile string `yaml:"keyFile,omitempty"`
+	// KeyRotation schedules the rotation of the consensus key of the chain at an activation height.
+	KeyRotation *KeyRotation `yaml:"keyRotation,omitempty"`
+	// Sentries are the chain nodes that may request signatures for the chain, and are dialed
+	// in addition to chainNodes. If empty, all chain nodes may request signatures for the chain.
+	// Chain nodes that connect to a privValListener match a sentry by its nodeID, so a sentry
+	// with a nodeID and no privValAddr is only served over a listener, and not dialed.
+	Sentries ChainNodes `yaml:"sentries,omitempty"`
+	// SentryQuorum requires votes to be requested by multiple sentries before they are signed.
+	SentryQuorum *SentryQuorum `yaml:"sentryQuorum,omitempty"`
+}
+
+// SentryQuorum is the number of distinct chain nodes that must request the same vote before it is signed,
+// so that a single compromised sentry can not make the signer sign a vote on its own.
+// Proposals are not subject to the quorum, as each sentry builds its own block.
+type SentryQuorum struct {
+	// Threshold is the number of distinct chain nodes that must request the same vote.
+	Threshold int `yaml:"threshold"`
+	// Timeout is how long a vote waits for the threshold number of chain nodes, one second if empty.
+	Timeout string `yaml:"timeout,omitempty"`
+	// AllowWithoutSentry signs votes that were not requested by a chain node, e.g. through the gRPC API,
+	// without the quorum. By default they are rejected, as they would bypass the quorum.
+	AllowWithoutSentry bool `yaml:"allowWithoutSentry,omitempty"`
+}
+
+func (q *SentryQuorum) Validate() error {
+	if q.Threshold <= 0 {
+		return fmt.Errorf("invalid threshold (%d), must be positive", q.Threshold)
+	}
+	if q.Timeout != "" {
+		if _, err := time.ParseDuration(q.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateSentryQuorumSentries checks that the sentries of a chain with a sentry quorum are identified by
+// their node IDs. A sentry without a node ID is identified by its privValAddr, so a single chain node
+// at several addresses would count more than once towards the quorum.
+func (chain ChainConfig) validateSentryQuorumSentries() error {
+	if len(chain.Sentries) == 0 {
+		return fmt.Errorf("sentries must be set")
+	}
+	nodeIDs := make(map[string]struct{}, len(chain.Sentries))
+	for _, sentry := range chain.Sentries {
+		if sentry.NodeID == "" {
+			return fmt.Errorf("sentry (%s) must have a nodeID", sentry.PrivValAddr)
+		}
+		nodeIDs[strings.ToLower(sentry.NodeID)] = struct{}{}
+	}
+	if chain.SentryQuorum.Threshold > len(nodeIDs) {
+		return fmt.Errorf("threshold (%d) is greater than the number of sentries (%d)",
+			chain.SentryQuorum.Threshold, len(nodeIDs))
+	}
+	return nil
+}
+
+// TimeoutDuration returns how long a vote waits for the threshold number of chain nodes.
+func (q *SentryQuorum) TimeoutDuration() time.Duration {
+	timeout, err := time.ParseDuration(q.Timeout)
+	if err != nil {
+		return defaultSentryQuorumTimeout
+	}
+	return timeout
+}
+
+// KeyRotation is a scheduled rotation of the consensus key of a chain, e.g. with MsgRotateConsPubKey.
+// Heights below the activation height are signed with the key of the chain, and heights
+// from the activation height on with the new key.
+type KeyRotation struct {
+	// ActivationHeight is the first height that is signed with the new key.
+	ActivationHeight int64 `yaml:"activationHeight"`
+	// KeyFile is the key file of the new key, a priv validator key in single signer mode
+	// or a key shard in threshold mode. A relative path is relative to the key directory.
+	KeyFile string `yaml:"keyFile"`
+}
+
+func (r *KeyRotation) Validate() error {
+	if r.ActivationHeight <= 0 {
+		return fmt.Errorf("invalid activationHeight (%d), must be positive", r.ActivationHeight)
+	}
+	if r.KeyFile == "" {
+		return fmt.Errorf("keyFile cannot be empty")
+	}
+	return nil
+}
+
+// Active returns true if the height is signed with the new key.
+func (r *KeyRotation) Active(height int64) bool {
+	return r != nil && height >= r.ActivationHeight
+}
+
+// ChainsConfig is the allowlist of chains. If empty, requests for any chain are allowed.
+type ChainsConfig []ChainConfig
+
+func (chains ChainsConfig) Validate() error {
+	seen := make(map[string]struct{}, len(chains))
+	for _, chain := range chains {
+		if chain.ChainID == "" {
+			return fmt.Errorf("chain id cannot be empty")
+		}
+		if _, ok := seen[chain.ChainID]; ok {
+			return fmt.Errorf("duplicate chain id (%s)", chain.ChainID)
+		}
+		seen[chain.ChainID] = struct{}{}
+
+		if chain.HaltHeight < 0 {
+			return fmt.Errorf("invalid haltHeight (%d) for chain id (%s), must not be negative",
+				chain.HaltHeight, chain.ChainID)
+		}
+
+		if chain.KeyRotation != nil {
+			if err := chain.KeyRotation.Validate(); err != nil {
+				return fmt.Errorf("invalid keyRotation for chain id (%s): %w", chain.ChainID, err)
+			}
+		}
+
+		if err := chain.Sentries.Validate(); err != nil {
+			return fmt.Errorf("invalid sentries for chain id (%s): %w", chain.ChainID, err)
+		}
+		for _, sentry := range chain.Sentries {
+			if sentry.PrivValAddr == "" && sentry.NodeID == "" {
+				return fmt.Errorf("invalid sentry for chain id (%s), must have a privValAddr or a nodeID",
+					chain.ChainID)
+			}
+		}
+
+		if chain.SentryQuorum != nil {
+			if err := chain.SentryQuorum.Validate(); err != nil {
+				return fmt.Errorf("invalid sentryQuorum for chain id (%s): %w", chain.ChainID, err)
+			}
+			if err := chain.validateSentryQuorumSentries(); err != nil {
+				return fmt.Errorf("invalid sentryQuorum for chain id (%s): %w", chain.ChainID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Get returns the config of the chain, and false if the chain is not in the allowlist.
+func (chains ChainsConfig) Get(chainID string) (ChainConfig, bool) {
+	for _, chain := range chains {
+		if chain.ChainID == chainID {
+			return chain, true
+		}
+	}
+	return ChainConfig{}, false
+}
+
+// ChainNotAllowedError is returned for a request for a chain that is not in the allowlist, is disabled,
+// or is not allowed from the sentry that sent the request.
+type ChainNotAllowedError struct {
+	chainID string
+	msg     string
+}
+
+func (e *ChainNotAllowedError) Error() string {
+	return fmt.Sprintf("chain id (%s) %s", e.chainID, e.msg)
+}
+
+// unknownChainID is the chain_id metric label of requests for chains that are not in the allowlist,
+// so that requests for arbitrary chain IDs can not create arbitrary metric series.
+const unknownChainID = "unknown"
+
+// ChainAllowlist decides which chains requests are accepted for. A nil ChainAllowlist allows all chains.
+type ChainAllowlist struct {
+	chains ChainsConfig
+}
+
+// NewChainAllowlist returns the allowlist of the chains config, or nil if the config allows all chains.
+func NewChainAllowlist(chains ChainsConfig) *ChainAllowlist {
+	if len(chains) == 0 {
+		return nil
+	}
+	return &ChainAllowlist{chains: chains}
+}
+
+// metricChainID returns the chain ID if the chain is in the allowlist, or unknownChainID otherwise.
+func (a *ChainAllowlist) metricChainID(chainID string) string {
+	if a == nil {
+		return chainID
+	}
+	if _, ok := a.chains.Get(chainID); ok {
+		return chainID
+	}
+	return unknownChainID
+}
+
+// Allow returns a ChainNotAllowedError if requests for the chain are not allowed from the sentry.
+// The sentry is the chain node the request was received from, which matches a sentry of the chain
+// by its privValAddr or by its node ID, e.g. a chain node that connected to a listener.
+// It is empty if the request was not received from a chain node.
+func (a *ChainAllowlist) Allow(chainID string, sentry ChainNode) error {
+	if a == nil {
+		return nil
+	}
+
+	chain, ok := a.chains.Get(chainID)
+	if !ok {
+		return &ChainNotAllowedError{chainID: chainID, msg: "is not in the chains allowlist"}
+	}
+	if chain.Disabled {
+		return &ChainNotAllowedError{chainID: chainID, msg: "is disabled"}
+	}
+	if sentry == (ChainNode{}) || len(chain.Sentries) == 0 {
+		return nil
+	}
+	for _, s := range chain.Sentries {
+		if sentry.PrivValAddr != "" && s.PrivValAddr == sentry.PrivValAddr {
+			return nil
+		}
+		if sentry.NodeID != "" && strings.EqualFold(s.NodeID, sentry.NodeID) {
+			return nil
+		}
+	}
+
+	name := sentry.PrivValAddr
+	if sentry.NodeID != "" {
+		name = fmt.Sprintf("%s (%s)", name, sentry.NodeID)
+	}
+	return &ChainNotAllowedError{chainID: chainID, msg: fmt.Sprintf("is not allowed from sentry %s", name)}
+}
@@ -0,0 +1,133 @@
+package signer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	cometcrypto "github.com/cometbft/cometbft/crypto"
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometprotoprivval "github.com/cometbft/cometbft/proto/tendermint/privval"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainAllowlist(t *testing.T) {
+	// an empty chains config allows all chains
+	allowAll := NewChainAllowlist(nil)
+	require.Nil(t, allowAll)
+	require.NoError(t, allowAll.Allow("any-chain", ChainNode{PrivValAddr: "tcp://127.0.0.1:1234"}))
+
+	allowlist := NewChainAllowlist(ChainsConfig{
+		{ChainID: "chain-1"},
+		{ChainID: "chain-2", Disabled: true},
+		{ChainID: "chain-3", Sentries: ChainNodes{
+			{PrivValAddr: "tcp://127.0.0.1:1234"},
+			{NodeID: "3F1A4B0E5C9D2A7F6B8E1C0D4A5F9E2B7C6D8A1F"},
+		}},
+	})
+
+	listener := "tcp://0.0.0.0:1234"
+
+	tcs := []struct {
+		name      string
+		chainID   string
+		sentry    ChainNode
+		expectErr bool
+	}{
+		{name: "allowed", chainID: "chain-1", sentry: ChainNode{PrivValAddr: "tcp://127.0.0.1:5678"}},
+		{name: "unknown", chainID: "chain-4", sentry: ChainNode{PrivValAddr: "tcp://127.0.0.1:1234"}, expectErr: true},
+		{name: "disabled", chainID: "chain-2", sentry: ChainNode{PrivValAddr: "tcp://127.0.0.1:1234"}, expectErr: true},
+		{name: "disabled grpc", chainID: "chain-2", expectErr: true},
+		{name: "allowed sentry", chainID: "chain-3", sentry: ChainNode{PrivValAddr: "tcp://127.0.0.1:1234"}},
+		{name: "other sentry", chainID: "chain-3", sentry: ChainNode{PrivValAddr: "tcp://127.0.0.1:5678"}, expectErr: true},
+		{
+			name:    "listener sentry",
+			chainID: "chain-3",
+			sentry:  ChainNode{PrivValAddr: listener, NodeID: "3f1a4b0e5c9d2a7f6b8e1c0d4a5f9e2b7c6d8a1f"},
+		},
+		{
+			name:      "other listener sentry",
+			chainID:   "chain-3",
+			sentry:    ChainNode{PrivValAddr: listener, NodeID: "4f1a4b0e5c9d2a7f6b8e1c0d4a5f9e2b7c6d8a1f"},
+			expectErr: true,
+		},
+		{
+			name:      "unix listener",
+			chainID:   "chain-3",
+			sentry:    ChainNode{PrivValAddr: "unix:///tmp/privval.sock"},
+			expectErr: true,
+		},
+		{name: "grpc", chainID: "chain-3"},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := allowlist.Allow(tc.chainID, tc.sentry)
+			if tc.expectErr {
+				var notAllowed *ChainNotAllowedError
+				require.ErrorAs(t, err, &notAllowed)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	// requests for chains that are not in the allowlist are counted as unknown
+	require.Equal(t, "chain-2", allowlist.metricChainID("chain-2"))
+	require.Equal(t, unknownChainID, allowlist.metricChainID("chain-4"))
+}
+
+type countingPrivValidator struct {
+	requests int
+}
+
+func (pv *countingPrivValidator) Sign(context.Context, string, Block) ([]byte, []byte, time.Time, error) {
+	pv.requests++
+	return []byte("signature"), nil, time.Now(), nil
+}
+
+func (pv *countingPrivValidator) GetPubKey(context.Context, string) (cometcrypto.PubKey, error) {
+	pv.requests++
+	return cometcryptoed25519.GenPrivKey().PubKey(), nil
+}
+
+func (pv *countingPrivValidator) Stop() {}
+
+func TestReconnRemoteSignerRejectsChains(t *testing.T) {
+	privVal := new(countingPrivValidator)
+	rs := NewReconnRemoteSigner(
+		ChainNode{PrivValAddr: "tcp://127.0.0.1:1234"},
+		cometlog.NewNopLogger(),
+		privVal,
+		NewChainAllowlist(ChainsConfig{{ChainID: testChainID}}),
+		cometcryptoed25519.GenPrivKey(),
+		net.Dialer{},
+		1024*1024,
+	)
+
+	vote := func(chainID string) cometprotoprivval.Message {
+		return cometprotoprivval.Message{Sum: &cometprotoprivval.Message_SignVoteRequest{
+			SignVoteRequest: &cometprotoprivval.SignVoteRequest{
+				ChainId: chainID,
+				Vote:    &cometproto.Vote{Type: cometproto.PrevoteType, Height: 1},
+			},
+		}}
+	}
+
+	res := rs.handleRequest(vote("unknown")).(*cometprotoprivval.Message)
+	require.NotNil(t, res.GetSignedVoteResponse().Error)
+	require.Zero(t, privVal.requests)
+
+	res = rs.handleRequest(cometprotoprivval.Message{Sum: &cometprotoprivval.Message_PubKeyRequest{
+		PubKeyRequest: &cometprotoprivval.PubKeyRequest{ChainId: "unknown"},
+	}}).(*cometprotoprivval.Message)
+	require.NotNil(t, res.GetPubKeyResponse().Error)
+	require.Zero(t, privVal.requests)
+
+	res = rs.handleRequest(vote(testChainID)).(*cometprotoprivval.Message)
+	require.Nil(t, res.GetSignedVoteResponse().Error)
+	require.Equal(t, 1, privVal.requests)
+}
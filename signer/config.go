@@ -1,22 +1,29 @@
 package signer
 
 import (
+	"encoding/hex"
 	"fmt"
 	"net"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/cometbft/cometbft/crypto"
+	cometp2p "github.com/cometbft/cometbft/p2p"
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/cosmos/cosmos-sdk/codec/legacy"
 	"github.com/cosmos/cosmos-sdk/codec/types"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	"github.com/cosmos/cosmos-sdk/types/bech32"
 	"github.com/strangelove-ventures/horcrux/v3/client"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 	"gopkg.in/yaml.v2"
 )
 
@@ -33,18 +40,82 @@ type Config struct {
 	SignMode            SignMode             `yaml:"signMode"`
 	ThresholdModeConfig *ThresholdModeConfig `yaml:"thresholdMode,omitempty"`
 	ChainNodes          ChainNodes           `yaml:"chainNodes"`
+	PrivValListeners    PrivValListeners     `yaml:"privValListeners,omitempty"`
+	Chains              ChainsConfig         `yaml:"chains,omitempty"`
 	DebugAddr           string               `yaml:"debugAddr"`
 	GRPCAddr            string               `yaml:"grpcAddr"`
 	MaxReadSize         int                  `yaml:"maxReadSize"`
+
+	// QuarantineConflictingSentries stops serving a sentry that requests a vote for a different block than
+	// the majority of the sentries, or for two different blocks, at the same height, round and step,
+	// until it is cleared with horcrux sentry clear.
+	QuarantineConflictingSentries bool `yaml:"quarantineConflictingSentries,omitempty"`
 }
 
+// Nodes returns the privValAddr of the chain nodes and of the sentries of the enabled chains.
 func (c *Config) Nodes() (out []string) {
-	for _, n := range c.ChainNodes {
+	for _, n := range c.DialNodes() {
 		out = append(out, n.PrivValAddr)
 	}
 	return out
 }
 
+// DialNodes returns the chain nodes and the sentries of the enabled chains, once per privValAddr.
+// Sentries without a privValAddr connect to a listener, so they are not dialed.
+func (c *Config) DialNodes() (out ChainNodes) {
+	seen := make(map[string]struct{})
+	add := func(n ChainNode) {
+		if n.PrivValAddr == "" {
+			return
+		}
+		if _, ok := seen[n.PrivValAddr]; !ok {
+			seen[n.PrivValAddr] = struct{}{}
+			out = append(out, n)
+		}
+	}
+	for _, n := range c.ChainNodes {
+		add(n)
+	}
+	for _, chain := range c.Chains {
+		if chain.Disabled {
+			continue
+		}
+		for _, n := range chain.Sentries {
+			add(n)
+		}
+	}
+	return out
+}
+
+// validateNodeIDs returns an error if a privValAddr is listed more than once, in chainNodes or the sentries
+// of the chains, with different nodeIDs.
+func (c *Config) validateNodeIDs() error {
+	nodeIDs := make(map[string]string)
+	check := func(n ChainNode) error {
+		if n.PrivValAddr == "" {
+			return nil
+		}
+		if nodeID, ok := nodeIDs[n.PrivValAddr]; ok && !strings.EqualFold(nodeID, n.NodeID) {
+			return fmt.Errorf("conflicting nodeIDs (%s, %s) for chain node (%s)", nodeID, n.NodeID, n.PrivValAddr)
+		}
+		nodeIDs[n.PrivValAddr] = n.NodeID
+		return nil
+	}
+	for _, n := range c.ChainNodes {
+		if err := check(n); err != nil {
+			return err
+		}
+	}
+	for _, chain := range c.Chains {
+		for _, n := range chain.Sentries {
+			if err := check(n); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (c *Config) MustMarshalYaml() []byte {
 	out, err := yaml.Marshal(c)
 	if err != nil {
@@ -54,7 +125,16 @@ func (c *Config) MustMarshalYaml() []byte {
 }
 
 func (c *Config) ValidateSingleSignerConfig() error {
-	return c.ChainNodes.Validate()
+	if err := c.ChainNodes.Validate(); err != nil {
+		return err
+	}
+	if err := c.PrivValListeners.Validate(); err != nil {
+		return err
+	}
+	if err := c.Chains.Validate(); err != nil {
+		return err
+	}
+	return c.validateNodeIDs()
 }
 
 func (c *Config) ValidateThresholdModeConfig() error {
@@ -87,6 +167,58 @@ func (c *Config) ValidateThresholdModeConfig() error {
 		return fmt.Errorf("invalid grpcTimeout: %w", err)
 	}
 
+	switch c.ThresholdModeConfig.SignScheme {
+	case "", SignSchemeTSEd25519, SignSchemeFROST:
+	default:
+		return fmt.Errorf("invalid signScheme (%s), must be %s or %s",
+			c.ThresholdModeConfig.SignScheme, SignSchemeTSEd25519, SignSchemeFROST)
+	}
+
+	if c.ThresholdModeConfig.PKCS11ShardStorage != nil && c.ThresholdModeConfig.PKCS11ShardStorage.ModulePath == "" {
+		return fmt.Errorf("pkcs11ShardStorage modulePath must not be empty")
+	}
+
+	for _, chain := range c.Chains {
+		if chain.SentryQuorum == nil {
+			continue
+		}
+		sentries := len(chain.Sentries)
+		if sentries == 0 {
+			sentries = len(c.DialNodes()) + c.PrivValListeners.numChainNodes()
+		}
+		if chain.SentryQuorum.Threshold > sentries {
+			return fmt.Errorf("sentryQuorum threshold (%d) for chain id (%s) must not be greater than "+
+				"the number of chain nodes (%d) that may request signatures for the chain",
+				chain.SentryQuorum.Threshold, chain.ChainID, sentries)
+		}
+	}
+
+	if c.ThresholdModeConfig.PKCS11ShardStorage != nil {
+		for _, chain := range c.Chains {
+			if chain.KeyRotation != nil {
+				return fmt.Errorf("keyRotation for chain id (%s) is not supported with pkcs11ShardStorage", chain.ChainID)
+			}
+		}
+	}
+
+	switch c.ThresholdModeConfig.TLSMode {
+	case "", TLSModeDisabled, TLSModePermissive, TLSModeEnabled, TLSModeStrict:
+	default:
+		return fmt.Errorf("invalid tlsMode (%s), must be %s, %s, %s or %s", c.ThresholdModeConfig.TLSMode,
+			TLSModeDisabled, TLSModePermissive, TLSModeEnabled, TLSModeStrict)
+	}
+
+	switch c.ThresholdModeConfig.AuthMode {
+	case "", CosignerAuthModeStrict, CosignerAuthModePermissive:
+	default:
+		return fmt.Errorf("invalid authMode (%s), must be %s or %s", c.ThresholdModeConfig.AuthMode,
+			CosignerAuthModeStrict, CosignerAuthModePermissive)
+	}
+
+	if sp := c.ThresholdModeConfig.SlashingProtection; sp != nil && sp.RetainHeights < 0 {
+		return fmt.Errorf("invalid slashingProtection retainHeights (%d), must not be negative", sp.RetainHeights)
+	}
+
 	if err := c.ThresholdModeConfig.Cosigners.Validate(); err != nil {
 		return err
 	}
@@ -116,6 +248,20 @@ func (c RuntimeConfig) CosignerSecurityECIES() (*CosignerSecurityECIES, error) {
 	return NewCosignerSecurityECIES(key), nil
 }
 
+func (c RuntimeConfig) CosignerSecurityX25519() (*CosignerSecurityX25519, error) {
+	keyFile, err := c.KeyFileExistsCosignerX25519()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := LoadCosignerX25519Key(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cosigner key (%s): %w", keyFile, err)
+	}
+
+	return NewCosignerSecurityX25519(key), nil
+}
+
 func (c RuntimeConfig) CosignerSecurityRSA() (*CosignerSecurityRSA, error) {
 	keyFile, err := c.KeyFileExistsCosignerRSA()
 	if err != nil {
@@ -130,6 +276,30 @@ func (c RuntimeConfig) CosignerSecurityRSA() (*CosignerSecurityRSA, error) {
 	return NewCosignerSecurityRSA(key), nil
 }
 
+// CosignerTransportCredentials returns the gRPC transport credentials of the p2p connections between cosigners
+// for the TLS mode of the threshold mode config.
+func (c RuntimeConfig) CosignerTransportCredentials() (credentials.TransportCredentials, error) {
+	mode := c.Config.ThresholdModeConfig.TLSMode
+	if mode == "" || mode == TLSModeDisabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	certPEM, err := os.ReadFile(c.KeyFilePathCosignerTLS(CosignerTLSCertFile))
+	if err != nil {
+		return nil, fmt.Errorf("error reading cosigner TLS certificate: %w", err)
+	}
+	keyPEM, err := readKeyFile(c.KeyFilePathCosignerTLS(CosignerTLSKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("error reading cosigner TLS key: %w", err)
+	}
+	caPEM, err := os.ReadFile(c.KeyFilePathCosignerTLS(CosignerTLSCAFile))
+	if err != nil {
+		return nil, fmt.Errorf("error reading cosigner TLS CA certificate: %w", err)
+	}
+
+	return NewCosignerTLSCredentials(mode, certPEM, keyPEM, caPEM, c.Config.ThresholdModeConfig.Cosigners)
+}
+
 func (c RuntimeConfig) cachedKeyDirectory() string {
 	if c.Config.PrivValKeyDir != nil {
 		return *c.Config.PrivValKeyDir
@@ -142,6 +312,9 @@ func (c RuntimeConfig) KeyFilePathSingleSigner(chainID string) string {
 	if kd := c.cachedKeyDirectory(); kd != "" {
 		keyDir = kd
 	}
+	if keyFile := c.chainKeyFile(chainID); keyFile != "" {
+		return resolvePath(keyDir, keyFile)
+	}
 	return filepath.Join(keyDir, fmt.Sprintf("%s_priv_validator_key.json", chainID))
 }
 
@@ -150,9 +323,45 @@ func (c RuntimeConfig) KeyFilePathCosigner(chainID string) string {
 	if kd := c.cachedKeyDirectory(); kd != "" {
 		keyDir = kd
 	}
+	if keyFile := c.chainKeyFile(chainID); keyFile != "" {
+		return resolvePath(keyDir, keyFile)
+	}
 	return filepath.Join(keyDir, fmt.Sprintf("%s_shard.json", chainID))
 }
 
+// chainKeyFile returns the key file override of the chain in the chains config, if any.
+func (c RuntimeConfig) chainKeyFile(chainID string) string {
+	chain, _ := c.Config.Chains.Get(chainID)
+	return chain.KeyFile
+}
+
+// KeyFilePathRotation returns the key file of the new key of the scheduled key rotation of the chain,
+// or an empty string if the chain has no key rotation.
+func (c RuntimeConfig) KeyFilePathRotation(chainID string) string {
+	chain, _ := c.Config.Chains.Get(chainID)
+	if chain.KeyRotation == nil {
+		return ""
+	}
+	keyDir := c.HomeDir
+	if kd := c.cachedKeyDirectory(); kd != "" {
+		keyDir = kd
+	}
+	return resolvePath(keyDir, chain.KeyRotation.KeyFile)
+}
+
+// keyRotation returns the scheduled key rotation of the chain, or nil if the chain has no key rotation.
+func (c RuntimeConfig) keyRotation(chainID string) *KeyRotation {
+	chain, _ := c.Config.Chains.Get(chainID)
+	return chain.KeyRotation
+}
+
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
 func (c RuntimeConfig) KeyFilePathCosignerRSA() string {
 	keyDir := c.HomeDir
 	if kd := c.cachedKeyDirectory(); kd != "" {
@@ -169,6 +378,89 @@ func (c RuntimeConfig) KeyFilePathCosignerECIES() string {
 	return filepath.Join(keyDir, "ecies_keys.json")
 }
 
+func (c RuntimeConfig) KeyFilePathCosignerX25519() string {
+	keyDir := c.HomeDir
+	if kd := c.cachedKeyDirectory(); kd != "" {
+		keyDir = kd
+	}
+	return filepath.Join(keyDir, "x25519_keys.json")
+}
+
+// KeyFilePathCosignerTLS returns the path of one of the cosigner TLS files in the key directory.
+func (c RuntimeConfig) KeyFilePathCosignerTLS(file string) string {
+	keyDir := c.HomeDir
+	if kd := c.cachedKeyDirectory(); kd != "" {
+		keyDir = kd
+	}
+	return filepath.Join(keyDir, file)
+}
+
+// KeyFilePathConnection returns the path of the key that identifies horcrux on the connections to the chain nodes.
+func (c RuntimeConfig) KeyFilePathConnection() string {
+	keyDir := c.HomeDir
+	if kd := c.cachedKeyDirectory(); kd != "" {
+		keyDir = kd
+	}
+	return filepath.Join(keyDir, ConnectionKeyFile)
+}
+
+// KeyFiles returns the shard, priv-validator key and cosigner key files in the key directory,
+// and the existing key files of the chains that override their key file or schedule a key rotation.
+func (c RuntimeConfig) KeyFiles() ([]string, error) {
+	keyDir := c.HomeDir
+	if kd := c.cachedKeyDirectory(); kd != "" {
+		keyDir = kd
+	}
+	var files []string
+	patterns := []string{
+		"*_shard.json", "*_priv_validator_key.json", "ecies_keys.json", "x25519_keys.json", "rsa_keys.json",
+		CosignerTLSKeyFile, ConnectionKeyFile,
+	}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(keyDir, pattern))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	for _, chain := range c.Config.Chains {
+		if chain.KeyFile != "" {
+			files = appendKeyFile(files, resolvePath(keyDir, chain.KeyFile))
+		}
+		if chain.KeyRotation != nil {
+			files = appendKeyFile(files, c.KeyFilePathRotation(chain.ChainID))
+		}
+	}
+	return files, nil
+}
+
+// appendKeyFile appends the key file if it exists and is not already in files.
+func appendKeyFile(files []string, file string) []string {
+	if fileExists(file) != nil || slices.Contains(files, file) {
+		return files
+	}
+	return append(files, file)
+}
+
+// EncryptedKeyFiles returns the passphrase encrypted key files in the key directory.
+func (c RuntimeConfig) EncryptedKeyFiles() ([]string, error) {
+	files, err := c.KeyFiles()
+	if err != nil {
+		return nil, err
+	}
+	var encrypted []string
+	for _, file := range files {
+		bz, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		if IsEncryptedKeyFile(bz) {
+			encrypted = append(encrypted, file)
+		}
+	}
+	return encrypted, nil
+}
+
 func (c RuntimeConfig) PrivValStateFile(chainID string) string {
 	return filepath.Join(c.StateDir, fmt.Sprintf("%s_priv_validator_state.json", chainID))
 }
@@ -177,6 +469,31 @@ func (c RuntimeConfig) CosignerStateFile(chainID string) string {
 	return filepath.Join(c.StateDir, fmt.Sprintf("%s_share_sign_state.json", chainID))
 }
 
+// StateChainIDs returns the IDs of the chains with sign state files in the state directory.
+func (c RuntimeConfig) StateChainIDs() ([]string, error) {
+	seen := make(map[string]struct{})
+	var chainIDs []string
+	for _, suffix := range []string{"_priv_validator_state.json", "_share_sign_state.json"} {
+		matches, err := filepath.Glob(filepath.Join(c.StateDir, "*"+suffix))
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			chainID := strings.TrimSuffix(filepath.Base(match), suffix)
+			if _, ok := seen[chainID]; !ok {
+				seen[chainID] = struct{}{}
+				chainIDs = append(chainIDs, chainID)
+			}
+		}
+	}
+	return chainIDs, nil
+}
+
+// SlashingProtectionDBFile returns the path of the slashing protection database in the state directory.
+func (c RuntimeConfig) SlashingProtectionDBFile() string {
+	return filepath.Join(c.StateDir, "slashing_protection.db")
+}
+
 func (c RuntimeConfig) WriteConfigFile() error {
 	return os.WriteFile(c.ConfigFile, c.Config.MustMarshalYaml(), 0600)
 }
@@ -216,12 +533,94 @@ func (c RuntimeConfig) KeyFileExistsCosignerECIES() (string, error) {
 	return keyFile, fileExists(keyFile)
 }
 
+func (c RuntimeConfig) KeyFileExistsCosignerX25519() (string, error) {
+	keyFile := c.KeyFilePathCosignerX25519()
+	return keyFile, fileExists(keyFile)
+}
+
 // ThresholdModeConfig is the on disk config format for threshold sign mode.
 type ThresholdModeConfig struct {
-	Threshold   int             `yaml:"threshold"`
-	Cosigners   CosignersConfig `yaml:"cosigners"`
-	GRPCTimeout string          `yaml:"grpcTimeout"`
-	RaftTimeout string          `yaml:"raftTimeout"`
+	Threshold          int                       `yaml:"threshold"`
+	Cosigners          CosignersConfig           `yaml:"cosigners"`
+	GRPCTimeout        string                    `yaml:"grpcTimeout"`
+	RaftTimeout        string                    `yaml:"raftTimeout"`
+	SignScheme         string                    `yaml:"signScheme,omitempty"`
+	PKCS11ShardStorage *PKCS11ShardStorageConfig `yaml:"pkcs11ShardStorage,omitempty"`
+	TLSMode            string                    `yaml:"tlsMode,omitempty"`
+	AuthMode           string                    `yaml:"authMode,omitempty"`
+
+	// AllowMissingNonceCommitments accepts the nonces of cosigners that do not send nonce commitments, and
+	// existing partial signatures that are returned without their nonces, as cosigners of previous versions do.
+	// Their partial signatures can then only be verified as part of the combined signature.
+	// It is only meant for a rolling upgrade of the cosigners.
+	AllowMissingNonceCommitments bool `yaml:"allowMissingNonceCommitments,omitempty"`
+
+	SlashingProtection *SlashingProtectionConfig `yaml:"slashingProtection,omitempty"`
+}
+
+const (
+	// SignSchemeTSEd25519 deals encrypted nonce shares between all cosigners. This is the default.
+	SignSchemeTSEd25519 = "tsed25519"
+
+	// SignSchemeFROST signs with FROST(Ed25519, SHA-512) as specified in RFC 9591,
+	// where cosigners only publish nonce commitments.
+	SignSchemeFROST = "frost"
+)
+
+// PKCS11ShardStorageConfig is the on disk config format for storing the key shards of this cosigner in a PKCS#11
+// token, instead of the {chainID}_shard.json files in the key directory. The key shards are read from the token
+// at startup and sign in memory, like shard files.
+type PKCS11ShardStorageConfig struct {
+	// ModulePath is the path of the PKCS#11 module of the token, e.g. /usr/lib/softhsm/libsofthsm2.so
+	ModulePath string `yaml:"modulePath"`
+	// Slot is the slot ID of the token.
+	Slot uint `yaml:"slot"`
+	// KeyLabel is the label of the key shard objects. {chainID} is replaced with the chain ID.
+	KeyLabel string `yaml:"keyLabel,omitempty"`
+	// PINFile is the file that contains the user PIN of the token.
+	// If empty, the PIN is read from the HORCRUX_PKCS11_PIN environment variable.
+	PINFile string `yaml:"pinFile,omitempty"`
+}
+
+// SlashingProtectionConfig is the on disk config format for the slashing protection database,
+// the history of every signature in the state directory. It is enabled by default.
+type SlashingProtectionConfig struct {
+	// Disabled disables the slashing protection database. Only the sign state files are consulted.
+	Disabled bool `yaml:"disabled,omitempty"`
+	// RetainHeights is the number of latest heights of each chain whose records are kept.
+	// If zero, the full history is kept.
+	RetainHeights int64 `yaml:"retainHeights,omitempty"`
+}
+
+const (
+	defaultPKCS11KeyLabel = "{chainID}_shard"
+	envPKCS11PIN          = "HORCRUX_PKCS11_PIN"
+)
+
+// KeyLabelFor returns the label of the key shard object for the chain.
+func (cfg *PKCS11ShardStorageConfig) KeyLabelFor(chainID string) string {
+	label := cfg.KeyLabel
+	if label == "" {
+		label = defaultPKCS11KeyLabel
+	}
+	return strings.ReplaceAll(label, "{chainID}", chainID)
+}
+
+// PIN returns the user PIN of the token.
+func (cfg *PKCS11ShardStorageConfig) PIN() (string, error) {
+	if cfg.PINFile != "" {
+		bz, err := os.ReadFile(cfg.PINFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read PKCS#11 PIN file: %w", err)
+		}
+		return strings.TrimSpace(string(bz)), nil
+	}
+
+	if pin, ok := os.LookupEnv(envPKCS11PIN); ok {
+		return pin, nil
+	}
+
+	return "", fmt.Errorf("no PKCS#11 PIN, set pinFile or the %s environment variable", envPKCS11PIN)
 }
 
 func (cfg *ThresholdModeConfig) LeaderElectMultiAddress() (string, error) {
@@ -315,11 +714,35 @@ func CosignersFromFlag(cosigners []string) (out []CosignerConfig, err error) {
 
 type ChainNode struct {
 	PrivValAddr string `json:"privValAddr" yaml:"privValAddr"`
+	// NodeID pins the identity of the chain node: connections are dropped if the node ID of the key the chain node
+	// authenticates the connection with does not match. If empty, any chain node at the privValAddr is accepted.
+	// Only chain nodes with a persistent privval key can be pinned, stock CometBFT generates a new key on every start.
+	NodeID string `json:"nodeID,omitempty" yaml:"nodeID,omitempty"`
+	// PersistentPrivValKey confirms that the privval listener of the chain node authenticates with a persistent key,
+	// which is required to pin the NodeID of a chain node that is dialed.
+	PersistentPrivValKey bool `json:"persistentPrivValKey,omitempty" yaml:"persistentPrivValKey,omitempty"`
 }
 
 func (cn ChainNode) Validate() error {
-	_, err := url.Parse(cn.PrivValAddr)
-	return err
+	if _, err := url.Parse(cn.PrivValAddr); err != nil {
+		return err
+	}
+	if cn.NodeID != "" && !isNodeID(cn.NodeID) {
+		return fmt.Errorf("invalid nodeID (%s) for chain node (%s), must be %d hex encoded bytes",
+			cn.NodeID, cn.PrivValAddr, cometp2p.IDByteLength)
+	}
+	// a stock CometBFT chain node that is dialed would be rejected after every restart
+	if cn.NodeID != "" && cn.PrivValAddr != "" && !cn.PersistentPrivValKey {
+		return fmt.Errorf("nodeID of chain node (%s) can only be pinned with persistentPrivValKey: true, "+
+			"stock CometBFT generates a new privval key on every start", cn.PrivValAddr)
+	}
+	return nil
+}
+
+// isNodeID returns true if the node ID is the hex encoded address of a node key.
+func isNodeID(nodeID string) bool {
+	bz, err := hex.DecodeString(nodeID)
+	return err == nil && len(bz) == cometp2p.IDByteLength
 }
 
 type ChainNodes []ChainNode
@@ -336,6 +759,71 @@ func (cns ChainNodes) Validate() error {
 	return nil
 }
 
+// PrivValListener is an address that horcrux listens on for privval connections from chain nodes that dial horcrux,
+// for chain nodes that horcrux can not dial, e.g. behind NAT.
+type PrivValListener struct {
+	// ListenAddr is a tcp:// address, whose connections are authenticated with the connection key,
+	// or a unix:// socket, whose access is controlled by its file permissions.
+	ListenAddr string `json:"listenAddr" yaml:"listenAddr"`
+	// NodeIDs are the node IDs of the chain nodes that may connect to a tcp:// address.
+	NodeIDs []string `json:"nodeIDs,omitempty" yaml:"nodeIDs,omitempty"`
+}
+
+func (l PrivValListener) Validate() error {
+	u, err := url.Parse(l.ListenAddr)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "tcp":
+		if len(l.NodeIDs) == 0 {
+			return fmt.Errorf("nodeIDs for privval listener (%s) cannot be empty", l.ListenAddr)
+		}
+		for _, nodeID := range l.NodeIDs {
+			if !isNodeID(nodeID) {
+				return fmt.Errorf("invalid nodeID (%s) for privval listener (%s), must be %d hex encoded bytes",
+					nodeID, l.ListenAddr, cometp2p.IDByteLength)
+			}
+		}
+	case "unix":
+		if len(l.NodeIDs) > 0 {
+			return fmt.Errorf("nodeIDs are not supported for unix socket privval listener (%s)", l.ListenAddr)
+		}
+	default:
+		return fmt.Errorf("invalid listenAddr (%s), must be a tcp:// or unix:// address", l.ListenAddr)
+	}
+	return nil
+}
+
+type PrivValListeners []PrivValListener
+
+func (ls PrivValListeners) Validate() error {
+	seen := make(map[string]struct{}, len(ls))
+	for _, l := range ls {
+		if err := l.Validate(); err != nil {
+			return err
+		}
+		if _, ok := seen[l.ListenAddr]; ok {
+			return fmt.Errorf("duplicate privval listener (%s)", l.ListenAddr)
+		}
+		seen[l.ListenAddr] = struct{}{}
+	}
+	return nil
+}
+
+// numChainNodes returns the number of chain nodes that may connect to the listeners,
+// counting a unix socket as one chain node.
+func (ls PrivValListeners) numChainNodes() (n int) {
+	for _, l := range ls {
+		if len(l.NodeIDs) > 0 {
+			n += len(l.NodeIDs)
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
 func ChainNodesFromFlag(nodes []string) (ChainNodes, error) {
 	out := make(ChainNodes, len(nodes))
 	for i, n := range nodes {
@@ -366,7 +854,7 @@ func PubKey(bech32BasePrefix string, pubKey crypto.PubKey) (string, error) {
 	marshaler := codec.NewProtoCodec(registry)
 	var pk *cryptotypes.PubKey
 	registry.RegisterInterface("cosmos.crypto.PubKey", pk)
-	registry.RegisterImplementations(pk, &ed25519.PubKey{})
+	registry.RegisterImplementations(pk, &ed25519.PubKey{}, &secp256k1.PubKey{})
 	sdkPK, err := cryptocodec.FromCmtPubKeyInterface(pubKey)
 	if err != nil {
 		return "", err
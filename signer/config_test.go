@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	cometcryptosecp256k1 "github.com/cometbft/cometbft/crypto/secp256k1"
 	"github.com/strangelove-ventures/horcrux/v3/signer"
 	"github.com/stretchr/testify/require"
 )
@@ -27,6 +28,25 @@ func TestNodes(t *testing.T) {
 	}
 
 	require.Equal(t, []string{"tcp://0.0.0.0:1234", "tcp://0.0.0.0:5678"}, c.Nodes())
+
+	// the sentries of enabled chains are dialed too
+	c.Chains = signer.ChainsConfig{
+		{
+			ChainID: "chain-1",
+			Sentries: signer.ChainNodes{
+				{PrivValAddr: "tcp://0.0.0.0:5678"},
+				{PrivValAddr: "tcp://0.0.0.0:9012"},
+				// a sentry that connects to a listener is not dialed
+				{NodeID: "0123456789abcdef0123456789abcdef01234567"},
+			},
+		},
+		{
+			ChainID:  "chain-2",
+			Disabled: true,
+			Sentries: signer.ChainNodes{{PrivValAddr: "tcp://0.0.0.0:3456"}},
+		},
+	}
+	require.Equal(t, []string{"tcp://0.0.0.0:1234", "tcp://0.0.0.0:5678", "tcp://0.0.0.0:9012"}, c.Nodes())
 }
 
 func TestValidateSingleSignerConfig(t *testing.T) {
@@ -59,6 +79,165 @@ func TestValidateSingleSignerConfig(t *testing.T) {
 			},
 			expectErr: &url.Error{Op: "parse", URL: "abc://\\invalid_addr", Err: url.InvalidHostError("\\")},
 		},
+		{
+			name: "valid chains",
+			config: signer.Config{
+				Chains: signer.ChainsConfig{
+					{ChainID: "chain-1"},
+					{ChainID: "chain-2", Sentries: signer.ChainNodes{{PrivValAddr: "tcp://127.0.0.1:1234"}}},
+				},
+			},
+			expectErr: nil,
+		},
+		{
+			name: "empty chain id",
+			config: signer.Config{
+				Chains: signer.ChainsConfig{{KeyFile: "chain-1_shard.json"}},
+			},
+			expectErr: fmt.Errorf("chain id cannot be empty"),
+		},
+		{
+			name: "duplicate chain id",
+			config: signer.Config{
+				Chains: signer.ChainsConfig{{ChainID: "chain-1"}, {ChainID: "chain-1", Disabled: true}},
+			},
+			expectErr: fmt.Errorf("duplicate chain id (chain-1)"),
+		},
+		{
+			name: "negative halt height",
+			config: signer.Config{
+				Chains: signer.ChainsConfig{{ChainID: "chain-1", HaltHeight: -1}},
+			},
+			expectErr: fmt.Errorf("invalid haltHeight (-1) for chain id (chain-1), must not be negative"),
+		},
+		{
+			name: "invalid node id",
+			config: signer.Config{
+				ChainNodes: signer.ChainNodes{{PrivValAddr: "tcp://127.0.0.1:1234", NodeID: "abc"}},
+			},
+			expectErr: fmt.Errorf("invalid nodeID (abc) for chain node (tcp://127.0.0.1:1234), must be 20 hex encoded bytes"),
+		},
+		{
+			name: "pinned node id of dialed chain node without persistent privval key",
+			config: signer.Config{
+				ChainNodes: signer.ChainNodes{
+					{PrivValAddr: "tcp://127.0.0.1:1234", NodeID: "0123456789abcdef0123456789abcdef01234567"},
+				},
+			},
+			expectErr: fmt.Errorf("nodeID of chain node (tcp://127.0.0.1:1234) can only be pinned with " +
+				"persistentPrivValKey: true, stock CometBFT generates a new privval key on every start"),
+		},
+		{
+			name: "conflicting node ids",
+			config: signer.Config{
+				ChainNodes: signer.ChainNodes{
+					{
+						PrivValAddr:          "tcp://127.0.0.1:1234",
+						NodeID:               "0123456789abcdef0123456789abcdef01234567",
+						PersistentPrivValKey: true,
+					},
+				},
+				Chains: signer.ChainsConfig{
+					{ChainID: "chain-1", Sentries: signer.ChainNodes{{PrivValAddr: "tcp://127.0.0.1:1234"}}},
+				},
+			},
+			expectErr: fmt.Errorf("conflicting nodeIDs (0123456789abcdef0123456789abcdef01234567, ) " +
+				"for chain node (tcp://127.0.0.1:1234)"),
+		},
+		{
+			name: "empty sentry",
+			config: signer.Config{
+				Chains: signer.ChainsConfig{{ChainID: "chain-1", Sentries: signer.ChainNodes{{}}}},
+			},
+			expectErr: fmt.Errorf("invalid sentry for chain id (chain-1), must have a privValAddr or a nodeID"),
+		},
+		{
+			name: "sentry quorum without threshold",
+			config: signer.Config{
+				Chains: signer.ChainsConfig{{ChainID: "chain-1", SentryQuorum: &signer.SentryQuorum{Timeout: "1s"}}},
+			},
+			expectErr: fmt.Errorf("invalid sentryQuorum for chain id (chain-1): invalid threshold (0), must be positive"),
+		},
+		{
+			name: "sentry quorum without sentries",
+			config: signer.Config{
+				Chains: signer.ChainsConfig{{ChainID: "chain-1", SentryQuorum: &signer.SentryQuorum{Threshold: 2}}},
+			},
+			expectErr: fmt.Errorf("invalid sentryQuorum for chain id (chain-1): sentries must be set"),
+		},
+		{
+			name: "sentry quorum with sentry without node id",
+			config: signer.Config{
+				Chains: signer.ChainsConfig{{
+					ChainID: "chain-1",
+					Sentries: signer.ChainNodes{
+						{
+							PrivValAddr:          "tcp://127.0.0.1:1234",
+							NodeID:               "0123456789abcdef0123456789abcdef01234567",
+							PersistentPrivValKey: true,
+						},
+						{PrivValAddr: "tcp://127.0.0.1:1235"},
+					},
+					SentryQuorum: &signer.SentryQuorum{Threshold: 2},
+				}},
+			},
+			expectErr: fmt.Errorf("invalid sentryQuorum for chain id (chain-1): " +
+				"sentry (tcp://127.0.0.1:1235) must have a nodeID"),
+		},
+		{
+			name: "sentry quorum above the number of sentries",
+			config: signer.Config{
+				Chains: signer.ChainsConfig{{
+					ChainID: "chain-1",
+					Sentries: signer.ChainNodes{
+						{
+							PrivValAddr:          "tcp://127.0.0.1:1234",
+							NodeID:               "0123456789abcdef0123456789abcdef01234567",
+							PersistentPrivValKey: true,
+						},
+						{
+							PrivValAddr:          "tcp://127.0.0.2:1234",
+							NodeID:               "0123456789ABCDEF0123456789ABCDEF01234567",
+							PersistentPrivValKey: true,
+						},
+					},
+					SentryQuorum: &signer.SentryQuorum{Threshold: 2},
+				}},
+			},
+			expectErr: fmt.Errorf("invalid sentryQuorum for chain id (chain-1): " +
+				"threshold (2) is greater than the number of sentries (1)"),
+		},
+		{
+			name: "key rotation without activation height",
+			config: signer.Config{
+				Chains: signer.ChainsConfig{{ChainID: "chain-1", KeyRotation: &signer.KeyRotation{KeyFile: "new.json"}}},
+			},
+			expectErr: fmt.Errorf("invalid keyRotation for chain id (chain-1): invalid activationHeight (0), must be positive"),
+		},
+		{
+			name: "valid privval listeners",
+			config: signer.Config{
+				PrivValListeners: signer.PrivValListeners{
+					{ListenAddr: "tcp://0.0.0.0:1234", NodeIDs: []string{"0123456789abcdef0123456789abcdef01234567"}},
+					{ListenAddr: "unix:///var/run/horcrux.sock"},
+				},
+			},
+			expectErr: nil,
+		},
+		{
+			name: "privval listener without node ids",
+			config: signer.Config{
+				PrivValListeners: signer.PrivValListeners{{ListenAddr: "tcp://0.0.0.0:1234"}},
+			},
+			expectErr: fmt.Errorf("nodeIDs for privval listener (tcp://0.0.0.0:1234) cannot be empty"),
+		},
+		{
+			name: "privval listener with invalid address",
+			config: signer.Config{
+				PrivValListeners: signer.PrivValListeners{{ListenAddr: "udp://0.0.0.0:1234"}},
+			},
+			expectErr: fmt.Errorf("invalid listenAddr (udp://0.0.0.0:1234), must be a tcp:// or unix:// address"),
+		},
 	}
 
 	for _, tc := range testCases {
@@ -277,6 +456,156 @@ func TestValidateThresholdModeConfig(t *testing.T) {
 			},
 			expectErr: fmt.Errorf("invalid grpcTimeout: %w", fmt.Errorf("time: missing unit in duration \"1000\"")),
 		},
+		{
+			name: "invalid sign scheme",
+			config: signer.Config{
+				ThresholdModeConfig: &signer.ThresholdModeConfig{
+					Threshold:   2,
+					GRPCTimeout: "1000ms",
+					RaftTimeout: "1000ms",
+					SignScheme:  "musig",
+					Cosigners: signer.CosignersConfig{
+						{
+							ShardID: 1,
+							P2PAddr: "tcp://127.0.0.1:2222",
+						},
+						{
+							ShardID: 2,
+							P2PAddr: "tcp://127.0.0.1:2223",
+						},
+						{
+							ShardID: 3,
+							P2PAddr: "tcp://127.0.0.1:2224",
+						},
+					},
+				},
+				ChainNodes: []signer.ChainNode{
+					{
+						PrivValAddr: "tcp://127.0.0.1:1234",
+					},
+					{
+						PrivValAddr: "tcp://127.0.0.1:2345",
+					},
+					{
+						PrivValAddr: "tcp://127.0.0.1:3456",
+					},
+				},
+			},
+			expectErr: fmt.Errorf("invalid signScheme (musig), must be tsed25519 or frost"),
+		},
+		{
+			name: "invalid tls mode",
+			config: signer.Config{
+				ThresholdModeConfig: &signer.ThresholdModeConfig{
+					Threshold:   2,
+					GRPCTimeout: "1000ms",
+					RaftTimeout: "1000ms",
+					TLSMode:     "on",
+					Cosigners: signer.CosignersConfig{
+						{
+							ShardID: 1,
+							P2PAddr: "tcp://127.0.0.1:2222",
+						},
+						{
+							ShardID: 2,
+							P2PAddr: "tcp://127.0.0.1:2223",
+						},
+						{
+							ShardID: 3,
+							P2PAddr: "tcp://127.0.0.1:2224",
+						},
+					},
+				},
+				ChainNodes: []signer.ChainNode{
+					{
+						PrivValAddr: "tcp://127.0.0.1:1234",
+					},
+					{
+						PrivValAddr: "tcp://127.0.0.1:2345",
+					},
+					{
+						PrivValAddr: "tcp://127.0.0.1:3456",
+					},
+				},
+			},
+			expectErr: fmt.Errorf("invalid tlsMode (on), must be disabled, permissive, enabled or strict"),
+		},
+		{
+			name: "invalid auth mode",
+			config: signer.Config{
+				ThresholdModeConfig: &signer.ThresholdModeConfig{
+					Threshold:   2,
+					GRPCTimeout: "1000ms",
+					RaftTimeout: "1000ms",
+					AuthMode:    "off",
+					Cosigners: signer.CosignersConfig{
+						{
+							ShardID: 1,
+							P2PAddr: "tcp://127.0.0.1:2222",
+						},
+						{
+							ShardID: 2,
+							P2PAddr: "tcp://127.0.0.1:2223",
+						},
+						{
+							ShardID: 3,
+							P2PAddr: "tcp://127.0.0.1:2224",
+						},
+					},
+				},
+				ChainNodes: []signer.ChainNode{
+					{
+						PrivValAddr: "tcp://127.0.0.1:1234",
+					},
+					{
+						PrivValAddr: "tcp://127.0.0.1:2345",
+					},
+					{
+						PrivValAddr: "tcp://127.0.0.1:3456",
+					},
+				},
+			},
+			expectErr: fmt.Errorf("invalid authMode (off), must be strict or permissive"),
+		},
+		{
+			name: "negative slashing protection retain heights",
+			config: signer.Config{
+				ThresholdModeConfig: &signer.ThresholdModeConfig{
+					Threshold:   2,
+					GRPCTimeout: "1000ms",
+					RaftTimeout: "1000ms",
+					SlashingProtection: &signer.SlashingProtectionConfig{
+						RetainHeights: -1,
+					},
+					Cosigners: signer.CosignersConfig{
+						{
+							ShardID: 1,
+							P2PAddr: "tcp://127.0.0.1:2222",
+						},
+						{
+							ShardID: 2,
+							P2PAddr: "tcp://127.0.0.1:2223",
+						},
+						{
+							ShardID: 3,
+							P2PAddr: "tcp://127.0.0.1:2224",
+						},
+					},
+				},
+				ChainNodes: []signer.ChainNode{
+					{
+						PrivValAddr: "tcp://127.0.0.1:1234",
+					},
+					{
+						PrivValAddr: "tcp://127.0.0.1:2345",
+					},
+					{
+						PrivValAddr: "tcp://127.0.0.1:3456",
+					},
+				},
+			},
+			expectErr: fmt.Errorf("invalid slashingProtection retainHeights (-1), must not be negative"),
+		},
 		{
 			name: "invalid node address",
 			config: signer.Config{
@@ -332,6 +661,51 @@ func TestRuntimeConfigKeyFilePath(t *testing.T) {
 		filepath.Join(dir, fmt.Sprintf("%s_priv_validator_key.json", testChainID)),
 		c.KeyFilePathSingleSigner(testChainID),
 	)
+
+	// the key file of a chain can be overridden, relative to the key directory or absolute
+	c.Config.Chains = signer.ChainsConfig{
+		{ChainID: testChainID, KeyFile: "keys/test.json"},
+		{ChainID: "chain-2", KeyFile: "/keys/chain-2.json"},
+	}
+	require.Equal(t, filepath.Join(dir, "keys", "test.json"), c.KeyFilePathCosigner(testChainID))
+	require.Equal(t, filepath.Join(dir, "keys", "test.json"), c.KeyFilePathSingleSigner(testChainID))
+	require.Equal(t, "/keys/chain-2.json", c.KeyFilePathCosigner("chain-2"))
+
+	// the key file of a key rotation is resolved the same way
+	require.Empty(t, c.KeyFilePathRotation(testChainID))
+	c.Config.Chains[0].KeyRotation = &signer.KeyRotation{ActivationHeight: 100, KeyFile: "keys/test_rotated.json"}
+	require.Equal(t, filepath.Join(dir, "keys", "test_rotated.json"), c.KeyFilePathRotation(testChainID))
+}
+
+func TestRuntimeConfigKeyFiles(t *testing.T) {
+	dir := t.TempDir()
+	c := signer.RuntimeConfig{
+		HomeDir: dir,
+	}
+
+	shardFile := filepath.Join(dir, testChainID+"_shard.json")
+	require.NoError(t, os.WriteFile(shardFile, []byte{}, 0600))
+	overrideFile := filepath.Join(dir, "keys", "chain-2.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(overrideFile), 0700))
+	require.NoError(t, os.WriteFile(overrideFile, []byte{}, 0600))
+
+	// the key files of chains that override their key file are included once, if they exist
+	c.Config.Chains = signer.ChainsConfig{
+		{ChainID: testChainID, KeyFile: testChainID + "_shard.json"},
+		{ChainID: "chain-2", KeyFile: "keys/chain-2.json"},
+		{ChainID: "chain-3", KeyFile: "keys/chain-3.json"},
+	}
+	files, err := c.KeyFiles()
+	require.NoError(t, err)
+	require.Equal(t, []string{shardFile, overrideFile}, files)
+
+	// so are the key files of key rotations
+	rotationFile := filepath.Join(dir, "keys", "chain-2_rotated.json")
+	require.NoError(t, os.WriteFile(rotationFile, []byte{}, 0600))
+	c.Config.Chains[1].KeyRotation = &signer.KeyRotation{ActivationHeight: 100, KeyFile: "keys/chain-2_rotated.json"}
+	files, err = c.KeyFiles()
+	require.NoError(t, err)
+	require.Equal(t, []string{shardFile, overrideFile, rotationFile}, files)
 }
 
 func TestRuntimeConfigPrivValStateFile(t *testing.T) {
@@ -575,3 +949,15 @@ func TestCosignersFromFlag(t *testing.T) {
 		}
 	}
 }
+
+func TestPubKeySecp256k1(t *testing.T) {
+	pubKey := cometcryptosecp256k1.GenPrivKey().PubKey()
+
+	pubKeyJSON, err := signer.PubKey("", pubKey)
+	require.NoError(t, err)
+	require.Contains(t, pubKeyJSON, "/cosmos.crypto.secp256k1.PubKey")
+
+	pubKeyBech32, err := signer.PubKey("cosmos", pubKey)
+	require.NoError(t, err)
+	require.Contains(t, pubKeyBech32, "cosmosvalconspub1")
+}
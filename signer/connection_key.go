@@ -0,0 +1,71 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	cometcrypto "github.com/cometbft/cometbft/crypto"
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometjson "github.com/cometbft/cometbft/libs/json"
+	cometp2p "github.com/cometbft/cometbft/p2p"
+)
+
+// ConnectionKeyFile is the file name of the key that identifies horcrux on the connections to the chain nodes.
+const ConnectionKeyFile = "connection_key.json"
+
+// LoadOrGenConnectionKey loads the key that identifies horcrux on the connections to the chain nodes,
+// or generates and saves a new key if the key file does not exist yet, so that the identity of horcrux
+// persists across restarts. The privval listener of stock CometBFT does not check this key, so it only
+// identifies horcrux to chain nodes that verify it. The key file has the format of a CometBFT node key,
+// and a new key file is encrypted with the new key file passphrase if it is set.
+func LoadOrGenConnectionKey(keyFile string) (*cometp2p.NodeKey, error) {
+	bz, err := readKeyFile(keyFile)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		nodeKey := &cometp2p.NodeKey{PrivKey: cometcryptoed25519.GenPrivKey()}
+		bz, err := cometjson.Marshal(nodeKey)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeKeyFile(keyFile, bz); err != nil {
+			return nil, fmt.Errorf("failed to save connection key (%s): %w", keyFile, err)
+		}
+		return nodeKey, nil
+	}
+
+	nodeKey := new(cometp2p.NodeKey)
+	if err := cometjson.Unmarshal(bz, nodeKey); err != nil {
+		return nil, fmt.Errorf("error reading connection key (%s): %w", keyFile, err)
+	}
+	return nodeKey, nil
+}
+
+// SentryIdentityError is returned for a connection to a chain node that authenticated with a key
+// that does not match the pinned node ID of the chain node.
+type SentryIdentityError struct {
+	address string
+	nodeID  cometp2p.ID
+	pinned  string
+}
+
+func (e *SentryIdentityError) Error() string {
+	return fmt.Sprintf("chain node (%s) authenticated as node ID (%s), expected pinned node ID (%s)",
+		e.address, e.nodeID, e.pinned)
+}
+
+// verifySentryIdentity returns a SentryIdentityError if the remote key of the connection to the chain node
+// does not match its pinned node ID. Any remote key is accepted if the chain node has no pinned node ID.
+func verifySentryIdentity(node ChainNode, remotePubKey cometcrypto.PubKey) error {
+	if node.NodeID == "" {
+		return nil
+	}
+	nodeID := cometp2p.PubKeyToID(remotePubKey)
+	if !strings.EqualFold(string(nodeID), node.NodeID) {
+		return &SentryIdentityError{address: node.PrivValAddr, nodeID: nodeID, pinned: node.NodeID}
+	}
+	return nil
+}
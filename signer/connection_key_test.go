@@ -0,0 +1,44 @@
+package signer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometp2p "github.com/cometbft/cometbft/p2p"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadOrGenConnectionKey(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), ConnectionKeyFile)
+
+	nodeKey, err := LoadOrGenConnectionKey(keyFile)
+	require.NoError(t, err)
+
+	// the identity persists across restarts
+	loaded, err := LoadOrGenConnectionKey(keyFile)
+	require.NoError(t, err)
+	require.Equal(t, nodeKey.ID(), loaded.ID())
+
+	// the key file is a CometBFT node key
+	cometNodeKey, err := cometp2p.LoadNodeKey(keyFile)
+	require.NoError(t, err)
+	require.Equal(t, nodeKey.ID(), cometNodeKey.ID())
+}
+
+func TestVerifySentryIdentity(t *testing.T) {
+	sentryKey := cometcryptoed25519.GenPrivKey().PubKey()
+	nodeID := string(cometp2p.PubKeyToID(sentryKey))
+
+	require.NoError(t, verifySentryIdentity(ChainNode{PrivValAddr: "tcp://127.0.0.1:1234"}, sentryKey))
+	require.NoError(t, verifySentryIdentity(ChainNode{PrivValAddr: "tcp://127.0.0.1:1234", NodeID: nodeID}, sentryKey))
+	require.NoError(t, verifySentryIdentity(
+		ChainNode{PrivValAddr: "tcp://127.0.0.1:1234", NodeID: strings.ToUpper(nodeID)}, sentryKey,
+	))
+
+	otherKey := cometcryptoed25519.GenPrivKey().PubKey()
+	err := verifySentryIdentity(ChainNode{PrivValAddr: "tcp://127.0.0.1:1234", NodeID: nodeID}, otherKey)
+	var identityErr *SentryIdentityError
+	require.ErrorAs(t, err, &identityErr)
+}
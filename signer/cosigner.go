@@ -1,12 +1,15 @@
 package signer
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	cometcrypto "github.com/cometbft/cometbft/crypto"
+	cometjson "github.com/cometbft/cometbft/libs/json"
 	"github.com/cometbft/cometbft/libs/protoio"
 	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	"github.com/google/uuid"
@@ -54,6 +57,9 @@ type CosignerSignRequest struct {
 	UUID                   uuid.UUID
 	VoteExtensionSignBytes []byte
 	VoteExtUUID            uuid.UUID
+	// Nonces and VoteExtensionNonces are the nonces of the request, which are persisted with the signatures.
+	Nonces              CosignerNonces
+	VoteExtensionNonces CosignerNonces
 }
 
 type CosignerSignResponse struct {
@@ -62,14 +68,46 @@ type CosignerSignResponse struct {
 	Signature                []byte
 	VoteExtensionNoncePublic []byte
 	VoteExtensionSignature   []byte
+	// Existing is true if the signatures are existing signatures for the same HRS,
+	// which were produced with the nonces of an earlier sign request.
+	Existing bool
+	// Nonces and VoteExtensionNonces are the nonces, as signed by their source cosigners,
+	// that existing signatures were produced with, so that the leader can verify them.
+	Nonces              CosignerNonces
+	VoteExtensionNonces CosignerNonces
 }
 
 type CosignerNonce struct {
-	SourceID      int
+	SourceID int
+	// DestinationID is zero for public nonces that are sent to all cosigners, e.g. FROST nonce commitments.
 	DestinationID int
 	PubKey        []byte
 	Share         []byte
 	Signature     []byte
+
+	// Commitments are the Feldman commitments of the source's nonce polynomial,
+	// which are the same for every destination. They are part of the signed digest if they are sent,
+	// so the digest of nonces without commitments is unchanged. The destination verifies its
+	// decrypted share against them, and the leader verifies that every destination got the same.
+	Commitments [][]byte `json:",omitempty"`
+}
+
+// nonceDigestMessage returns the message of an encrypted nonce that the source cosigner signs.
+func nonceDigestMessage(
+	sourceID int,
+	encryptedNoncePub []byte,
+	encryptedNonceShare []byte,
+	commitments [][]byte,
+) ([]byte, error) {
+	msg := CosignerNonce{
+		SourceID: sourceID,
+		PubKey:   encryptedNoncePub,
+		Share:    encryptedNonceShare,
+	}
+	if len(commitments) > 0 {
+		msg.Commitments = commitments
+	}
+	return cometjson.Marshal(msg)
 }
 
 func (secretPart *CosignerNonce) toProto() *proto.Nonce {
@@ -79,6 +117,7 @@ func (secretPart *CosignerNonce) toProto() *proto.Nonce {
 		PubKey:        secretPart.PubKey,
 		Share:         secretPart.Share,
 		Signature:     secretPart.Signature,
+		Commitments:   secretPart.Commitments,
 	}
 }
 
@@ -98,6 +137,7 @@ func CosignerNonceFromProto(secretPart *proto.Nonce) CosignerNonce {
 		PubKey:        secretPart.PubKey,
 		Share:         secretPart.Share,
 		Signature:     secretPart.Signature,
+		Commitments:   secretPart.Commitments,
 	}
 }
 
@@ -112,6 +152,8 @@ func CosignerNoncesFromProto(secretParts []*proto.Nonce) []CosignerNonce {
 type CosignerSignBlockRequest struct {
 	ChainID string
 	Block   *Block
+	// Sentry is the privValAddr of the chain node the request was received from, if any.
+	Sentry string
 }
 
 type CosignerSignBlockResponse struct {
@@ -123,16 +165,69 @@ type CosignerUUIDNonces struct {
 	Nonces CosignerNonces
 }
 
+// For returns the nonces destined for the cosigner with the shard ID id,
+// including the nonces without a destination that are sent to all cosigners.
 func (n *CosignerUUIDNonces) For(id int) *CosignerUUIDNonces {
 	res := &CosignerUUIDNonces{UUID: n.UUID}
 	for _, nonce := range n.Nonces {
-		if nonce.DestinationID == id {
+		if nonce.DestinationID == id || nonce.DestinationID == 0 {
 			res.Nonces = append(res.Nonces, nonce)
 		}
 	}
 	return res
 }
 
+func (n *CosignerUUIDNonces) hasSource(id int) bool {
+	for _, nonce := range n.Nonces {
+		if nonce.SourceID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// errNoNonceCommitments is returned when the public nonce share of a cosigner can not be computed
+// because a source cosigner did not send commitments with its nonces.
+var errNoNonceCommitments = errors.New("no nonce commitments")
+
+// NoncePublicShare returns the combined public nonce and the public nonce share of the cosigner
+// with the shard ID id, computed from the nonce commitments of all sources that it signs with.
+func (n *CosignerUUIDNonces) NoncePublicShare(id int) ([]byte, []byte, error) {
+	commitments := make(map[int][][]byte)
+	sources := make(map[int]struct{})
+	for _, nonce := range n.Nonces {
+		if len(nonce.Commitments) > 0 {
+			commitments[nonce.SourceID] = nonce.Commitments
+		}
+		if nonce.DestinationID == id || nonce.SourceID == id {
+			sources[nonce.SourceID] = struct{}{}
+		}
+	}
+
+	polynomials := make([][][]byte, 0, len(sources))
+	noncePubs := make([][]byte, 0, len(sources))
+	for source := range sources {
+		c, ok := commitments[source]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w from cosigner %d", errNoNonceCommitments, source)
+		}
+		polynomials = append(polynomials, c)
+		noncePubs = append(noncePubs, c[0])
+	}
+
+	noncePub, err := addPoints(noncePubs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publicShares, err := vssPublicShards([]int{id}, polynomials)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return noncePub, publicShares[id-1], nil
+}
+
 type CosignerUUIDNoncesMultiple []*CosignerUUIDNonces
 
 func (n CosignerUUIDNoncesMultiple) toProto() []*proto.UUIDNonce {
@@ -146,6 +241,30 @@ func (n CosignerUUIDNoncesMultiple) toProto() []*proto.UUIDNonce {
 	return out
 }
 
+// verifyCommitments verifies that the nonces were all sent by the source cosigner, and that every
+// destination got the same nonce commitments. The leader verifies partial signatures against the
+// public nonce shares computed from the commitments, so they must be the ones each cosigner signs with.
+// Encrypted nonces without commitments are refused, unless allowMissing is set.
+func (n CosignerUUIDNoncesMultiple) verifyCommitments(source int, allowMissing bool) error {
+	for _, nonces := range n {
+		for _, nonce := range nonces.Nonces {
+			if nonce.SourceID != source {
+				return fmt.Errorf("cosigner %d sent a nonce of cosigner %d", source, nonce.SourceID)
+			}
+			if nonce.DestinationID != 0 && len(nonce.Commitments) == 0 && !allowMissing {
+				return fmt.Errorf("%w from cosigner %d", errNoNonceCommitments, source)
+			}
+			if !slices.EqualFunc(nonces.Nonces[0].Commitments, nonce.Commitments, bytes.Equal) {
+				return fmt.Errorf(
+					"cosigner %d sent different nonce commitments to cosigners %d and %d",
+					source, nonces.Nonces[0].DestinationID, nonce.DestinationID,
+				)
+			}
+		}
+	}
+	return nil
+}
+
 type CosignerSetNoncesAndSignRequest struct {
 	ChainID string
 	HRST    HRSTKey
@@ -0,0 +1,512 @@
+package signer
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	grpcproto "google.golang.org/grpc/encoding/proto"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// CosignerAuthModeStrict rejects Cosigner gRPC requests that are not authenticated and authorized.
+	// This is the default.
+	CosignerAuthModeStrict = "strict"
+
+	// CosignerAuthModePermissive accepts Cosigner gRPC requests that are not authenticated and authorized,
+	// but still meters and logs them. It is only meant for rolling upgrades of a cluster.
+	CosignerAuthModePermissive = "permissive"
+)
+
+const (
+	cosignerServicePrefix      = "/strangelove.horcrux.Cosigner/"
+	raftTransportServicePrefix = "/RaftTransport/"
+	raftAdminServicePrefix     = "/RaftAdmin/"
+
+	// cosignerAuthMaxClockSkew is the maximum difference between the timestamp of a request and our clock.
+	// Nonces of requests are remembered for twice as long, so a request can not be replayed.
+	cosignerAuthMaxClockSkew = 30 * time.Second
+
+	cosignerAuthNonceSize = 16
+
+	// cosignerAuthMaxSignatures is the maximum number of signatures of a request, one for each security scheme.
+	cosignerAuthMaxSignatures = 3
+
+	cosignerAuthHeaderShardID     = "x-horcrux-shard-id"
+	cosignerAuthHeaderDestination = "x-horcrux-destination-id"
+	cosignerAuthHeaderTimestamp   = "x-horcrux-timestamp"
+	cosignerAuthHeaderNonce       = "x-horcrux-nonce-bin"
+	cosignerAuthHeaderSignature   = "x-horcrux-signature-bin"
+)
+
+// cosignerAuthPolicy is who may invoke a Cosigner gRPC method.
+type cosignerAuthPolicy struct {
+	// admin methods are invoked by the horcrux CLI on any cosigner, with the key of the cosigner it runs on.
+	// All other methods may only be invoked by the other cosigners, on the cosigner they address.
+	admin bool
+}
+
+// cosignerAuthPolicies are the policies of the Cosigner gRPC methods, requests for other methods are rejected.
+var cosignerAuthPolicies = map[string]cosignerAuthPolicy{
+	cosignerServicePrefix + "SignBlock":             {},
+	cosignerServicePrefix + "SetNoncesAndSign":      {},
+	cosignerServicePrefix + "GetNonces":             {},
+	cosignerServicePrefix + "Ping":                  {},
+	cosignerServicePrefix + "TransferLeadership":    {admin: true},
+	cosignerServicePrefix + "GetLeader":             {admin: true},
+	cosignerServicePrefix + "SetHaltHeight":         {admin: true},
+	cosignerServicePrefix + "ClearSentryQuarantine": {admin: true},
+}
+
+// cosignerAuthServicePolicies are the policies of all methods of the other services on the p2p port.
+var cosignerAuthServicePolicies = map[string]cosignerAuthPolicy{
+	raftTransportServicePrefix: {},
+	raftAdminServicePrefix:     {admin: true},
+}
+
+// cosignerAuthPublicServices are the services on the p2p port that are not authenticated, as they are read-only:
+// the leader health checks of the horcrux CLI, and gRPC reflection.
+var cosignerAuthPublicServices = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.v1.ServerReflection/",
+	"/grpc.reflection.v1alpha.ServerReflection/",
+}
+
+// cosignerAuthPolicyFor returns the policy of the gRPC method, and false if the method is not allowed.
+func cosignerAuthPolicyFor(method string) (cosignerAuthPolicy, bool) {
+	if policy, ok := cosignerAuthPolicies[method]; ok {
+		return policy, true
+	}
+	for prefix, policy := range cosignerAuthServicePolicies {
+		if strings.HasPrefix(method, prefix) {
+			return policy, true
+		}
+	}
+	return cosignerAuthPolicy{}, false
+}
+
+func isCosignerAuthPublicMethod(method string) bool {
+	for _, prefix := range cosignerAuthPublicServices {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// CosignerAuthUnaryClientInterceptor signs every Cosigner gRPC request with the security of our cosigner.
+// The destination ID is the shard ID of the cosigner that is dialed.
+func CosignerAuthUnaryClientInterceptor(security CosignerSecurity, destinationID int) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		payloadHash, err := cosignerAuthPayloadHash(req)
+		if err != nil {
+			return err
+		}
+		ctx, err = cosignerAuthContext(ctx, security, destinationID, method, payloadHash)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// raftAuthDialOptions sign every raft transport request with the security of our cosigner.
+// The destination is the cosigner with the raft address that is dialed, in peers.
+// Streams are signed when they are opened, their messages are only protected by the transport credentials.
+func raftAuthDialOptions(security CosignerSecurity, peers map[string]int) []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(
+			ctx context.Context,
+			method string,
+			req, reply any,
+			cc *grpc.ClientConn,
+			invoker grpc.UnaryInvoker,
+			opts ...grpc.CallOption,
+		) error {
+			payloadHash, err := cosignerAuthPayloadHash(req)
+			if err != nil {
+				return err
+			}
+			ctx, err = cosignerAuthContext(ctx, security, peers[cc.Target()], method, payloadHash)
+			if err != nil {
+				return err
+			}
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(
+			ctx context.Context,
+			desc *grpc.StreamDesc,
+			cc *grpc.ClientConn,
+			method string,
+			streamer grpc.Streamer,
+			opts ...grpc.CallOption,
+		) (grpc.ClientStream, error) {
+			ctx, err := cosignerAuthContext(ctx, security, peers[cc.Target()], method, cosignerAuthStreamHash[:])
+			if err != nil {
+				return nil, err
+			}
+			return streamer(ctx, desc, cc, method, opts...)
+		}),
+	}
+}
+
+// cosignerAuthStreamHash is the payload hash of a stream, which is authenticated when it is opened.
+var cosignerAuthStreamHash = sha256.Sum256(nil)
+
+// cosignerAuthPayloadHash returns the hash of the request as it is encoded on the wire.
+func cosignerAuthPayloadHash(req any) ([]byte, error) {
+	if req == nil {
+		return cosignerAuthStreamHash[:], nil
+	}
+	bz, err := encoding.GetCodec(grpcproto.Name).Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.Sum256(bz)
+	return hash[:], nil
+}
+
+// cosignerAuthContext returns the context with the authentication metadata of the request for the destination.
+func cosignerAuthContext(
+	ctx context.Context,
+	security CosignerSecurity,
+	destinationID int,
+	method string,
+	payloadHash []byte,
+) (context.Context, error) {
+	nonce := make([]byte, cosignerAuthNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	timestamp := time.Now().UnixNano()
+
+	msg := cosignerAuthMessage(method, security.GetID(), destinationID, timestamp, nonce, payloadHash)
+	signatures, err := cosignerAuthSign(security, destinationID, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	kv := []string{
+		cosignerAuthHeaderShardID, strconv.Itoa(security.GetID()),
+		cosignerAuthHeaderDestination, strconv.Itoa(destinationID),
+		cosignerAuthHeaderTimestamp, strconv.FormatInt(timestamp, 10),
+		cosignerAuthHeaderNonce, string(nonce),
+	}
+	for _, signature := range signatures {
+		kv = append(kv, cosignerAuthHeaderSignature, string(signature))
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...), nil
+}
+
+// cosignerAuthSign signs the message of a request for the destination cosigner. A cosigner with the keys of
+// several security schemes signs with every scheme the destination may support.
+func cosignerAuthSign(security CosignerSecurity, destinationID int, msg []byte) ([][]byte, error) {
+	if multi, ok := security.(*CosignerSecurityMulti); ok {
+		return multi.SignFor(destinationID, msg)
+	}
+	signature, err := security.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{signature}, nil
+}
+
+// cosignerAuthMessage is the message that is signed to authenticate a request,
+// with the hash of the request as it is encoded on the wire.
+func cosignerAuthMessage(
+	method string,
+	shardID, destinationID int,
+	timestamp int64,
+	nonce []byte,
+	payloadHash []byte,
+) []byte {
+	msg := fmt.Appendf(nil, "%s\n%d\n%d\n%d\n", method, shardID, destinationID, timestamp)
+	msg = append(msg, nonce...)
+	return append(msg, payloadHash...)
+}
+
+// cosignerAuthCodec is the gRPC codec of the p2p server. It keeps the hash of the raw bytes of each request
+// until the request is authenticated, so that the signature is verified against the bytes on the wire.
+// A re-marshal of the decoded request would drop the fields that this version does not know,
+// so a request of a newer cosigner would never be authentic.
+type cosignerAuthCodec struct {
+	encoding.Codec
+
+	payloadHashes sync.Map
+}
+
+func newCosignerAuthCodec() *cosignerAuthCodec {
+	return &cosignerAuthCodec{Codec: encoding.GetCodec(grpcproto.Name)}
+}
+
+func (c *cosignerAuthCodec) Unmarshal(data []byte, v any) error {
+	if err := c.Codec.Unmarshal(data, v); err != nil {
+		return err
+	}
+	hash := sha256.Sum256(data)
+	c.payloadHashes.Store(v, hash[:])
+	return nil
+}
+
+// payloadHash returns the hash of the raw bytes of the request, and forgets it.
+func (c *cosignerAuthCodec) payloadHash(req any) ([]byte, bool) {
+	hash, ok := c.payloadHashes.LoadAndDelete(req)
+	if !ok {
+		return nil, false
+	}
+	return hash.([]byte), true
+}
+
+// cosignerAuthServerStream forgets the hashes of the messages of a stream, which is only authenticated
+// when it is opened.
+type cosignerAuthServerStream struct {
+	grpc.ServerStream
+	codec *cosignerAuthCodec
+}
+
+func (s cosignerAuthServerStream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	s.codec.payloadHashes.Delete(m)
+	return err
+}
+
+type cosignerAuthReplayKey struct {
+	shardID int
+	nonce   string
+}
+
+// cosignerAuthenticator authenticates and authorizes the Cosigner gRPC requests to our cosigner.
+// Requests must be signed by a configured cosigner, must be recent and must not be replayed.
+type cosignerAuthenticator struct {
+	logger   cometlog.Logger
+	security CosignerSecurity
+	peers    map[int]struct{}
+	mode     string
+	codec    *cosignerAuthCodec
+
+	mu     sync.Mutex
+	seen   map[cosignerAuthReplayKey]time.Time
+	pruned time.Time
+}
+
+func newCosignerAuthenticator(
+	logger cometlog.Logger,
+	security CosignerSecurity,
+	peerIDs []int,
+	mode string,
+) *cosignerAuthenticator {
+	peers := make(map[int]struct{}, len(peerIDs))
+	for _, id := range peerIDs {
+		peers[id] = struct{}{}
+	}
+	return &cosignerAuthenticator{
+		logger:   logger,
+		security: security,
+		peers:    peers,
+		mode:     mode,
+		codec:    newCosignerAuthCodec(),
+		seen:     make(map[cosignerAuthReplayKey]time.Time),
+	}
+}
+
+// ServerOptions are the gRPC server options of the p2p server that the authenticator requires.
+func (a *cosignerAuthenticator) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ForceServerCodec(a.codec),
+		grpc.UnaryInterceptor(a.UnaryServerInterceptor),
+		grpc.StreamInterceptor(a.StreamServerInterceptor),
+	}
+}
+
+// UnaryServerInterceptor rejects gRPC requests on the p2p port that are not authenticated and authorized,
+// i.e. the Cosigner requests, the raft transport and raft admin requests.
+// Only requests for the read-only public services are passed through.
+func (a *cosignerAuthenticator) UnaryServerInterceptor(
+	ctx context.Context,
+	req any,
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (any, error) {
+	payloadHash, ok := a.codec.payloadHash(req)
+	if !ok {
+		// the request was not decoded by the codec of the authenticator
+		var err error
+		if payloadHash, err = cosignerAuthPayloadHash(req); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	if err := a.check(ctx, info.FullMethod, payloadHash); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor rejects gRPC streams on the p2p port, such as the raft transport pipeline,
+// that are not authenticated and authorized when they are opened.
+func (a *cosignerAuthenticator) StreamServerInterceptor(
+	srv any,
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := a.check(ss.Context(), info.FullMethod, cosignerAuthStreamHash[:]); err != nil {
+		return err
+	}
+	return handler(srv, cosignerAuthServerStream{ServerStream: ss, codec: a.codec})
+}
+
+// check returns an error if the request is not authenticated and authorized, unless the mode is permissive.
+func (a *cosignerAuthenticator) check(ctx context.Context, method string, payloadHash []byte) error {
+	if isCosignerAuthPublicMethod(method) {
+		return nil
+	}
+
+	shardID, err := a.authorize(ctx, method, payloadHash, time.Now())
+	if err == nil {
+		return nil
+	}
+
+	totalUnauthorizedCosignerRequests.WithLabelValues(method).Inc()
+	a.logger.Error(
+		"Unauthorized cosigner request",
+		"method", method,
+		"shard_id", shardID,
+		"error", err,
+	)
+	if a.mode != CosignerAuthModePermissive {
+		return err
+	}
+	return nil
+}
+
+// authorize returns the shard ID of the cosigner that signed the request,
+// and an error if the request is not authenticated or not authorized.
+func (a *cosignerAuthenticator) authorize(
+	ctx context.Context,
+	method string,
+	payloadHash []byte,
+	now time.Time,
+) (int, error) {
+	policy, ok := cosignerAuthPolicyFor(method)
+	if !ok {
+		return 0, status.Errorf(codes.PermissionDenied, "method %s is not allowed", method)
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "no authentication metadata")
+	}
+	header := func(key string) string {
+		if values := md.Get(key); len(values) == 1 {
+			return values[0]
+		}
+		return ""
+	}
+
+	shardID, err := strconv.Atoi(header(cosignerAuthHeaderShardID))
+	if err != nil {
+		return 0, status.Error(codes.Unauthenticated, "invalid shard ID")
+	}
+	// over mutual TLS, requests must be signed by the cosigner of the certificate
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(CosignerTLSInfo); ok && info.ShardID != shardID {
+			return shardID, status.Errorf(codes.Unauthenticated,
+				"request signed by cosigner %d over the connection of cosigner %d", shardID, info.ShardID)
+		}
+	}
+
+	destinationID, err := strconv.Atoi(header(cosignerAuthHeaderDestination))
+	if err != nil {
+		return shardID, status.Error(codes.Unauthenticated, "invalid destination ID")
+	}
+	timestamp, err := strconv.ParseInt(header(cosignerAuthHeaderTimestamp), 10, 64)
+	if err != nil {
+		return shardID, status.Error(codes.Unauthenticated, "invalid timestamp")
+	}
+	nonce := header(cosignerAuthHeaderNonce)
+	if len(nonce) != cosignerAuthNonceSize {
+		return shardID, status.Error(codes.Unauthenticated, "invalid nonce")
+	}
+
+	ourID := a.security.GetID()
+	_, isPeer := a.peers[shardID]
+	switch {
+	case policy.admin && (isPeer || shardID == ourID) && destinationID == ourID:
+	case !policy.admin && isPeer && destinationID == ourID:
+	default:
+		return shardID, status.Errorf(codes.PermissionDenied,
+			"cosigner %d is not allowed to call %s on cosigner %d", shardID, method, destinationID)
+	}
+
+	if skew := now.Sub(time.Unix(0, timestamp)); skew > cosignerAuthMaxClockSkew || skew < -cosignerAuthMaxClockSkew {
+		return shardID, status.Errorf(codes.Unauthenticated, "request timestamp is off by %s", skew)
+	}
+
+	msg := cosignerAuthMessage(method, shardID, destinationID, timestamp, []byte(nonce), payloadHash)
+	if err := a.verify(shardID, msg, md.Get(cosignerAuthHeaderSignature)); err != nil {
+		return shardID, status.Errorf(codes.Unauthenticated, "invalid request signature: %v", err)
+	}
+
+	// only remember nonces of authentic requests, so the replay cache can not be flooded
+	if !a.markSeen(cosignerAuthReplayKey{shardID: shardID, nonce: nonce}, now) {
+		return shardID, status.Error(codes.Unauthenticated, "replayed request")
+	}
+
+	return shardID, nil
+}
+
+// verify returns nil if any of the signatures of the message is valid for the cosigner with the ID.
+func (a *cosignerAuthenticator) verify(shardID int, msg []byte, signatures []string) error {
+	if len(signatures) == 0 || len(signatures) > cosignerAuthMaxSignatures {
+		return fmt.Errorf("expected 1 to %d signatures, got %d", cosignerAuthMaxSignatures, len(signatures))
+	}
+
+	var err error
+	for _, signature := range signatures {
+		if err = a.security.Verify(shardID, msg, []byte(signature)); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// markSeen remembers the nonce of a request, and returns false if it was already seen.
+func (a *cosignerAuthenticator) markSeen(key cosignerAuthReplayKey, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// requests with older timestamps are rejected, so their nonces can be forgotten
+	if now.Sub(a.pruned) > cosignerAuthMaxClockSkew {
+		for k, seen := range a.seen {
+			if now.Sub(seen) > 2*cosignerAuthMaxClockSkew {
+				delete(a.seen, k)
+			}
+		}
+		a.pruned = now
+	}
+
+	if _, ok := a.seen[key]; ok {
+		return false
+	}
+	a.seen[key] = now
+	return true
+}
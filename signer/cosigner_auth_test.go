@@ -0,0 +1,338 @@
+package signer
+
+import (
+	"context"
+	"crypto/sha256"
+	"net"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/strangelove-ventures/horcrux/v3/signer/proto"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// signTestRequest signs the request with the client interceptor, and returns the context
+// in which the server receives the request.
+func signTestRequest(
+	t *testing.T,
+	security CosignerSecurity,
+	destinationID int,
+	method string,
+	req any,
+) context.Context {
+	var md metadata.MD
+	interceptor := CosignerAuthUnaryClientInterceptor(security, destinationID)
+	err := interceptor(context.Background(), method, req, nil, nil,
+		func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			md, _ = metadata.FromOutgoingContext(ctx)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func testPayloadHash(t *testing.T, req any) []byte {
+	hash, err := cosignerAuthPayloadHash(req)
+	require.NoError(t, err)
+	return hash
+}
+
+func TestCosignerAuthenticator(t *testing.T) {
+	keys, err := CreateCosignerECIESShards(3)
+	require.NoError(t, err)
+
+	securities := make([]CosignerSecurity, len(keys))
+	for i, key := range keys {
+		securities[i] = NewCosignerSecurityECIES(key)
+	}
+
+	const (
+		signBlock          = cosignerServicePrefix + "SignBlock"
+		transferLeadership = cosignerServicePrefix + "TransferLeadership"
+		deal               = cosignerServicePrefix + "Deal"
+	)
+
+	req := &proto.SignBlockRequest{ChainID: "test", Block: &proto.Block{Height: 1}}
+
+	// we are cosigner 1
+	auth := newCosignerAuthenticator(cometlog.NewNopLogger(), securities[0], []int{2, 3}, "")
+	now := time.Now()
+
+	tcs := []struct {
+		name     string
+		ctx      func() context.Context
+		method   string
+		req      any
+		now      time.Time
+		expected codes.Code
+	}{
+		{
+			name:     "peer",
+			ctx:      func() context.Context { return signTestRequest(t, securities[1], 1, signBlock, req) },
+			method:   signBlock,
+			req:      req,
+			now:      now,
+			expected: codes.OK,
+		},
+		{
+			name:     "no metadata",
+			ctx:      context.Background,
+			method:   signBlock,
+			req:      req,
+			now:      now,
+			expected: codes.Unauthenticated,
+		},
+		{
+			name:     "other destination",
+			ctx:      func() context.Context { return signTestRequest(t, securities[1], 3, signBlock, req) },
+			method:   signBlock,
+			req:      req,
+			now:      now,
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "self",
+			ctx:      func() context.Context { return signTestRequest(t, securities[0], 1, signBlock, req) },
+			method:   signBlock,
+			req:      req,
+			now:      now,
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "admin from self",
+			ctx:      func() context.Context { return signTestRequest(t, securities[0], 1, transferLeadership, req) },
+			method:   transferLeadership,
+			req:      req,
+			now:      now,
+			expected: codes.OK,
+		},
+		{
+			name:     "admin for any cosigner",
+			ctx:      func() context.Context { return signTestRequest(t, securities[0], 0, transferLeadership, req) },
+			method:   transferLeadership,
+			req:      req,
+			now:      now,
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "method without policy",
+			ctx:      func() context.Context { return signTestRequest(t, securities[1], 1, deal, req) },
+			method:   deal,
+			req:      req,
+			now:      now,
+			expected: codes.PermissionDenied,
+		},
+		{
+			name:     "tampered request",
+			ctx:      func() context.Context { return signTestRequest(t, securities[1], 1, signBlock, req) },
+			method:   signBlock,
+			req:      &proto.SignBlockRequest{ChainID: "test", Block: &proto.Block{Height: 2}},
+			now:      now,
+			expected: codes.Unauthenticated,
+		},
+		{
+			name:     "other method",
+			ctx:      func() context.Context { return signTestRequest(t, securities[1], 1, signBlock, req) },
+			method:   cosignerServicePrefix + "GetNonces",
+			req:      req,
+			now:      now,
+			expected: codes.Unauthenticated,
+		},
+		{
+			name:     "stale",
+			ctx:      func() context.Context { return signTestRequest(t, securities[1], 1, signBlock, req) },
+			method:   signBlock,
+			req:      req,
+			now:      now.Add(cosignerAuthMaxClockSkew + time.Second),
+			expected: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := auth.authorize(tc.ctx(), tc.method, testPayloadHash(t, tc.req), tc.now)
+			require.Equal(t, tc.expected, status.Code(err), err)
+		})
+	}
+
+	// a request can not be replayed
+	ctx := signTestRequest(t, securities[2], 1, signBlock, req)
+	shardID, err := auth.authorize(ctx, signBlock, testPayloadHash(t, req), now)
+	require.NoError(t, err)
+	require.Equal(t, 3, shardID)
+	_, err = auth.authorize(ctx, signBlock, testPayloadHash(t, req), now.Add(time.Second))
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestCosignerAuthenticatorMode(t *testing.T) {
+	keys, err := CreateCosignerECIESShards(2)
+	require.NoError(t, err)
+
+	method := cosignerServicePrefix + "Ping"
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	handler := func(context.Context, any) (any, error) {
+		return &proto.PingResponse{}, nil
+	}
+
+	for _, tc := range []struct {
+		mode      string
+		expectErr bool
+	}{
+		{mode: "", expectErr: true},
+		{mode: CosignerAuthModeStrict, expectErr: true},
+		{mode: CosignerAuthModePermissive, expectErr: false},
+	} {
+		auth := newCosignerAuthenticator(cometlog.NewNopLogger(), NewCosignerSecurityECIES(keys[0]), []int{2}, tc.mode)
+
+		// unsigned requests are only handled in permissive mode
+		_, err := auth.UnaryServerInterceptor(context.Background(), &proto.PingRequest{}, info, handler)
+		if tc.expectErr {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+		}
+
+		// signed requests are always handled
+		ctx := signTestRequest(t, NewCosignerSecurityECIES(keys[1]), 1, method, &proto.PingRequest{})
+		_, err = auth.UnaryServerInterceptor(ctx, &proto.PingRequest{}, info, handler)
+		require.NoError(t, err)
+	}
+
+	auth := newCosignerAuthenticator(cometlog.NewNopLogger(), NewCosignerSecurityECIES(keys[0]), []int{2}, "")
+
+	// the raft transport and raft admin are authenticated like the Cosigner service
+	for _, method := range []string{"/RaftTransport/AppendEntries", "/RaftAdmin/RemoveServer"} {
+		_, err = auth.UnaryServerInterceptor(context.Background(), &proto.PingRequest{},
+			&grpc.UnaryServerInfo{FullMethod: method}, handler)
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	}
+
+	streamHandler := func(any, grpc.ServerStream) error { return nil }
+	method = "/RaftTransport/AppendEntriesPipeline"
+	streamInfo := &grpc.StreamServerInfo{FullMethod: method}
+
+	err = auth.StreamServerInterceptor(nil, testServerStream{ctx: context.Background()}, streamInfo, streamHandler)
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := signTestRequest(t, NewCosignerSecurityECIES(keys[1]), 1, method, nil)
+	require.NoError(t, auth.StreamServerInterceptor(nil, testServerStream{ctx: ctx}, streamInfo, streamHandler))
+
+	// the read-only health checks are passed through
+	_, err = auth.UnaryServerInterceptor(context.Background(), nil,
+		&grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}, handler)
+	require.NoError(t, err)
+}
+
+func TestCosignerAuthenticatorUnknownFields(t *testing.T) {
+	keys, err := CreateCosignerECIESShards(2)
+	require.NoError(t, err)
+
+	auth := newCosignerAuthenticator(cometlog.NewNopLogger(), NewCosignerSecurityECIES(keys[0]), []int{2}, "")
+
+	method := cosignerServicePrefix + "Ping"
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+	handler := func(context.Context, any) (any, error) {
+		return &proto.PingResponse{}, nil
+	}
+
+	// a newer cosigner sends a field that this version does not know (field 100, varint 1)
+	raw := []byte{0xa0, 0x06, 0x01}
+	hash := sha256.Sum256(raw)
+	ctx, err := cosignerAuthContext(context.Background(), NewCosignerSecurityECIES(keys[1]), 1, method, hash[:])
+	require.NoError(t, err)
+	md, _ := metadata.FromOutgoingContext(ctx)
+
+	// the request is authenticated against the bytes on the wire, not a re-marshal of the decoded request
+	req := &proto.PingRequest{}
+	require.NoError(t, auth.codec.Unmarshal(raw, req))
+	_, err = auth.UnaryServerInterceptor(metadata.NewIncomingContext(context.Background(), md), req, info, handler)
+	require.NoError(t, err)
+
+	// the hash is only used once
+	_, ok := auth.codec.payloadHash(req)
+	require.False(t, ok)
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestRaftAuthDialOptions(t *testing.T) {
+	keys, err := CreateCosignerECIESShards(2)
+	require.NoError(t, err)
+
+	auth := newCosignerAuthenticator(cometlog.NewNopLogger(), NewCosignerSecurityECIES(keys[0]), []int{2}, "")
+
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer(auth.ServerOptions()...)
+	proto.RegisterCosignerServer(server, NewCosignerGRPCServer(nil, nil, nil))
+	go func() { _ = server.Serve(sock) }()
+	defer server.Stop()
+
+	address := sock.Addr().String()
+
+	ping := func(peers map[string]int) error {
+		conn, err := grpc.Dial(address, append([]grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		}, raftAuthDialOptions(NewCosignerSecurityECIES(keys[1]), peers)...)...)
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = proto.NewCosignerClient(conn).Ping(context.Background(), &proto.PingRequest{})
+		return err
+	}
+
+	// requests are signed for the cosigner with the dialed raft address
+	require.NoError(t, ping(map[string]int{address: 1}))
+	require.Equal(t, codes.PermissionDenied, status.Code(ping(map[string]int{address: 2})))
+}
+
+func TestCosignerAuthenticatorMultiScheme(t *testing.T) {
+	eciesKeys, err := CreateCosignerECIESShards(2)
+	require.NoError(t, err)
+
+	x25519Keys, err := CreateCosignerX25519Shards(2)
+	require.NoError(t, err)
+
+	// cosigner 1 migrates from ECIES to X25519, cosigner 2 did not yet
+	client, err := NewCosignerSecurityMulti(
+		NewCosignerSecurityX25519(x25519Keys[0]),
+		NewCosignerSecurityECIES(eciesKeys[0]),
+	)
+	require.NoError(t, err)
+	auth := newCosignerAuthenticator(cometlog.NewNopLogger(), NewCosignerSecurityECIES(eciesKeys[1]), []int{1}, "")
+
+	method := cosignerServicePrefix + "Ping"
+	req := &proto.PingRequest{}
+
+	// the schemes of cosigner 2 are not known, so the request is signed with all schemes
+	ctx := signTestRequest(t, client, 2, method, req)
+	md, _ := metadata.FromIncomingContext(ctx)
+	require.Len(t, md.Get(cosignerAuthHeaderSignature), 2)
+	_, err = auth.authorize(ctx, method, testPayloadHash(t, req), time.Now())
+	require.NoError(t, err)
+
+	client.SetPeerSchemes(2, []string{CosignerSecuritySchemeECIES})
+	ctx = signTestRequest(t, client, 2, method, req)
+	md, _ = metadata.FromIncomingContext(ctx)
+	require.Len(t, md.Get(cosignerAuthHeaderSignature), 1)
+	_, err = auth.authorize(ctx, method, testPayloadHash(t, req), time.Now())
+	require.NoError(t, err)
+
+	// a stale negotiation is rejected
+	client.SetPeerSchemes(2, []string{CosignerSecuritySchemeX25519})
+	_, err = auth.authorize(signTestRequest(t, client, 2, method, req), method, testPayloadHash(t, req), time.Now())
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,61 @@
+package signer
+
+import (
+	"crypto/sha256"
+
+	cometjson "github.com/cometbft/cometbft/libs/json"
+	"github.com/strangelove-ventures/horcrux/v3/signer/proto"
+)
+
+// CosignerDeal is a message sent from one cosigner to another during a key ceremony.
+// It holds the public commitments of the source cosigner and the key share
+// for the destination cosigner, encrypted to the destination cosigner's key.
+type CosignerDeal struct {
+	Session       string
+	SourceID      int
+	DestinationID int
+	Commitments   [][]byte
+	Share         []byte
+	Signature     []byte
+}
+
+// digest returns the hash of the deal that is signed by the source cosigner.
+func (deal *CosignerDeal) digest() ([]byte, error) {
+	digestMsg := CosignerDeal{
+		Session:       deal.Session,
+		SourceID:      deal.SourceID,
+		DestinationID: deal.DestinationID,
+		Commitments:   deal.Commitments,
+		Share:         deal.Share,
+	}
+
+	jsonBytes, err := cometjson.Marshal(digestMsg)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(jsonBytes)
+	return hash[:], nil
+}
+
+func (deal *CosignerDeal) toProto() *proto.DealRequest {
+	return &proto.DealRequest{
+		Session:       deal.Session,
+		SourceID:      int32(deal.SourceID),
+		DestinationID: int32(deal.DestinationID),
+		Commitments:   deal.Commitments,
+		Share:         deal.Share,
+		Signature:     deal.Signature,
+	}
+}
+
+func CosignerDealFromProto(deal *proto.DealRequest) CosignerDeal {
+	return CosignerDeal{
+		Session:       deal.Session,
+		SourceID:      int(deal.SourceID),
+		DestinationID: int(deal.DestinationID),
+		Commitments:   deal.Commitments,
+		Share:         deal.Share,
+		Signature:     deal.Signature,
+	}
+}
@@ -0,0 +1,139 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+)
+
+// DKG runs a dealerless distributed key generation between all cosigners of the ceremony.
+//
+// Each cosigner deals shares of a random secret to every other cosigner using Feldman
+// verifiable secret sharing. The resulting key shard of each cosigner is the sum of the
+// shares it received, and the combined public key is the sum of the dealt secrets'
+// public keys, so the combined private key is never known by any single party.
+// After dealing, the cosigners confirm that they all received the same commitments.
+func (c *KeyCeremony) DKG(ctx context.Context, chainID string, threshold uint8) (*CosignerEd25519Key, error) {
+	id := c.ID()
+	ids := c.ids()
+	peers := c.peerIDs()
+
+	if threshold == 0 || int(threshold) > len(ids) {
+		return nil, fmt.Errorf("threshold (%d) must be greater than zero and at most the number of cosigners (%d)",
+			threshold, len(ids))
+	}
+
+	dealSession := fmt.Sprintf("dkg/%s/deal", chainID)
+	confirmSession := fmt.Sprintf("dkg/%s/confirm", chainID)
+
+	c.open(dealSession, peers)
+	c.open(confirmSession, peers)
+
+	poly, err := newVSSPolynomial(nil, threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	commitments := poly.commitments()
+	shares := make(map[int][]byte, len(peers))
+	for _, peer := range peers {
+		shares[peer] = poly.evaluate(peer).Bytes()
+	}
+
+	c.logger.Info("Sending DKG deals", "chain_id", chainID, "threshold", threshold, "cosigners", len(ids))
+
+	if err := c.send(ctx, dealSession, peers, commitments, shares); err != nil {
+		return nil, err
+	}
+
+	deals, err := c.wait(ctx, dealSession)
+	if err != nil {
+		return nil, err
+	}
+
+	allCommitments := map[int][][]byte{id: commitments}
+	privateShard := poly.evaluate(id)
+
+	for source, d := range deals {
+		if len(d.deal.Commitments) != int(threshold) {
+			return nil, fmt.Errorf("cosigner %d dealt %d commitments, expected threshold (%d)",
+				source, len(d.deal.Commitments), threshold)
+		}
+		if err := verifyVSSShare(id, d.share, d.deal.Commitments); err != nil {
+			return nil, fmt.Errorf("invalid deal from cosigner %d: %w", source, err)
+		}
+		share, err := edwards25519.NewScalar().SetCanonicalBytes(d.share)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deal from cosigner %d: %w", source, err)
+		}
+		privateShard.Add(privateShard, share)
+		allCommitments[source] = d.deal.Commitments
+	}
+
+	// Confirm that all cosigners received the same commitments from every dealer.
+	// Otherwise a dealer could have handed out shares of different secrets.
+	transcript := dkgTranscript(ids, allCommitments)
+
+	c.logger.Info("Confirming DKG commitments", "chain_id", chainID)
+
+	if err := c.send(ctx, confirmSession, peers, [][]byte{transcript}, nil); err != nil {
+		return nil, err
+	}
+
+	confirms, err := c.wait(ctx, confirmSession)
+	if err != nil {
+		return nil, err
+	}
+
+	for source, d := range confirms {
+		if len(d.deal.Commitments) != 1 || !bytes.Equal(d.deal.Commitments[0], transcript) {
+			return nil, fmt.Errorf("cosigner %d received different DKG commitments", source)
+		}
+	}
+
+	secretCommitments := make([][]byte, 0, len(ids))
+	for _, i := range ids {
+		secretCommitments = append(secretCommitments, allCommitments[i][0])
+	}
+
+	pubKey, err := addPoints(secretCommitments)
+	if err != nil {
+		return nil, err
+	}
+
+	dealtCommitments := make([][][]byte, 0, len(ids))
+	for _, i := range ids {
+		dealtCommitments = append(dealtCommitments, allCommitments[i])
+	}
+
+	publicShards, err := vssPublicShards(ids, dealtCommitments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CosignerEd25519Key{
+		PubKey:       cometcryptoed25519.PubKey(pubKey),
+		PrivateShard: privateShard.Bytes(),
+		ID:           id,
+		PublicShards: publicShards,
+	}, nil
+}
+
+// dkgTranscript returns a hash of the commitments of all dealers, ordered by cosigner ID.
+func dkgTranscript(ids []int, commitments map[int][][]byte) []byte {
+	h := sha256.New()
+	for _, id := range ids {
+		var bz [8]byte
+		binary.BigEndian.PutUint64(bz[:], uint64(id))
+		h.Write(bz[:])
+		for _, c := range commitments[id] {
+			h.Write(c)
+		}
+	}
+	return h.Sum(nil)
+}
@@ -0,0 +1,172 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestDKG2of3(t *testing.T) {
+	testDKG(t, 2, 3)
+}
+
+func TestDKG3of5(t *testing.T) {
+	testDKG(t, 3, 5)
+}
+
+func testDKG(t *testing.T, threshold, total uint8) {
+	eciesKeys, err := CreateCosignerECIESShards(int(total))
+	require.NoError(t, err)
+
+	keys := testRunDKG(t, eciesKeys, threshold)
+
+	for i, key := range keys {
+		require.Equal(t, i+1, key.ID)
+		require.Equal(t, keys[0].PubKey, key.PubKey)
+	}
+
+	msg := []byte("dkg")
+
+	// any threshold subset of the shards must produce a valid signature
+	sig := testThresholdSign(t, threshold, total, keys[:threshold], msg)
+	require.True(t, keys[0].PubKey.VerifySignature(msg, sig))
+
+	sig = testThresholdSign(t, threshold, total, keys[total-threshold:], msg)
+	require.True(t, keys[0].PubKey.VerifySignature(msg, sig))
+
+	// less than threshold shards must not
+	sig = testThresholdSign(t, threshold, total, keys[:threshold-1], msg)
+	require.False(t, keys[0].PubKey.VerifySignature(msg, sig))
+}
+
+// testRunDKG runs a DKG ceremony between cosigners with the provided ECIES keys and returns their key shards.
+func testRunDKG(t *testing.T, eciesKeys []CosignerECIESKey, threshold uint8) []CosignerEd25519Key {
+	total := len(eciesKeys)
+	cosigners := testKeyCeremonyCosigners(t, total)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ceremonies := make([]*KeyCeremony, total)
+	for i := range ceremonies {
+		var err error
+		ceremonies[i], err = NewKeyCeremony(log.NewNopLogger(), NewCosignerSecurityECIES(eciesKeys[i]), cosigners,
+			insecure.NewCredentials())
+		require.NoError(t, err)
+		require.NoError(t, ceremonies[i].Start())
+		defer ceremonies[i].Stop()
+	}
+
+	keys := make([]CosignerEd25519Key, total)
+	testRunCeremonies(t, total, func(i int) error {
+		key, err := ceremonies[i].DKG(ctx, testChainID, threshold)
+		if err != nil {
+			return err
+		}
+		keys[i] = *key
+		return nil
+	})
+
+	return keys
+}
+
+// testRunCeremonies runs fn concurrently for each cosigner index and requires that all succeed.
+func testRunCeremonies(t *testing.T, total int, fn func(i int) error) {
+	var wg sync.WaitGroup
+	errs := make([]error, total)
+	for i := 0; i < total; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(i)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+// testKeyCeremonyCosigners returns a cosigner config with a free local port for each cosigner.
+func testKeyCeremonyCosigners(t *testing.T, total int) CosignersConfig {
+	cosigners := make(CosignersConfig, total)
+	for i := range cosigners {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		cosigners[i] = CosignerConfig{
+			ShardID: i + 1,
+			P2PAddr: fmt.Sprintf("tcp://%s", l.Addr().String()),
+		}
+		require.NoError(t, l.Close())
+	}
+	return cosigners
+}
+
+// testThresholdSign signs msg with the provided key shards and returns the combined signature.
+func testThresholdSign(t *testing.T, threshold, total uint8, keys []CosignerEd25519Key, msg []byte) []byte {
+	allNonces := make([]Nonces, len(keys))
+	for i := range keys {
+		var err error
+		allNonces[i], err = GenerateNonces(threshold, total)
+		require.NoError(t, err)
+	}
+
+	uuidNonces := &CosignerUUIDNonces{}
+	for i, n := range allNonces {
+		// including the nonce of the cosigner for itself, so that a single cosigner has a nonce as well
+		for _, key := range keys {
+			uuidNonces.Nonces = append(uuidNonces.Nonces, CosignerNonce{
+				SourceID:      keys[i].ID,
+				DestinationID: key.ID,
+				PubKey:        n.PubKey,
+				Commitments:   n.Commitments,
+			})
+		}
+	}
+
+	sigs := make([]PartialSignature, len(keys))
+	for i, key := range keys {
+		s := &ThresholdSignerSoft{
+			privateKeyShard: key.PrivateShard,
+			pubKey:          key.PubKey.Bytes(),
+			publicShards:    key.PublicShards,
+			threshold:       threshold,
+			total:           total,
+		}
+
+		nonces := make([]Nonce, len(keys))
+		for j, n := range allNonces {
+			nonces[j] = Nonce{
+				ID:     keys[j].ID,
+				Share:  n.Shares[key.ID-1],
+				PubKey: n.PubKey,
+			}
+		}
+
+		sig, err := s.Sign(nonces, msg)
+		require.NoError(t, err)
+
+		sigs[i] = PartialSignature{
+			ID:        key.ID,
+			Signature: sig,
+		}
+
+		// every partial signature verifies against the public key shard of its cosigner
+		require.NoError(t, s.VerifyPartialSignature(msg, uuidNonces, sigs[i]))
+		require.Error(t, s.VerifyPartialSignature([]byte("other"), uuidNonces, sigs[i]))
+	}
+
+	combined, err := (&ThresholdSignerSoft{threshold: threshold, total: total}).CombineSignatures(sigs)
+	require.NoError(t, err)
+
+	return combined
+}
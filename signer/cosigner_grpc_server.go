@@ -7,6 +7,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/raft"
 	"github.com/strangelove-ventures/horcrux/v3/signer/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var _ proto.CosignerServer = &CosignerGRPCServer{}
@@ -34,6 +36,8 @@ func (rpc *CosignerGRPCServer) SignBlock(
 	ctx context.Context,
 	req *proto.SignBlockRequest,
 ) (*proto.SignBlockResponse, error) {
+	// the sentry of a proxied request counts towards the sentry quorum of the chain on the leader
+	ctx = withSentry(ctx, req.Sentry)
 	sig, voteExtSig, _, err := rpc.thresholdValidator.Sign(ctx, req.ChainID, BlockFromProto(req.Block))
 	if err != nil {
 		return nil, err
@@ -93,6 +97,9 @@ func (rpc *CosignerGRPCServer) SetNoncesAndSign(
 		Signature:          res.Signature,
 		VoteExtNoncePublic: res.VoteExtensionNoncePublic,
 		VoteExtSignature:   res.VoteExtensionSignature,
+		Existing:           res.Existing,
+		Nonces:             res.Nonces.toProto(),
+		VoteExtNonces:      res.VoteExtensionNonces.toProto(),
 	}, nil
 }
 
@@ -149,6 +156,104 @@ func (rpc *CosignerGRPCServer) GetLeader(
 	return &proto.GetLeaderResponse{Leader: int32(leader)}, nil
 }
 
-func (rpc *CosignerGRPCServer) Ping(context.Context, *proto.PingRequest) (*proto.PingResponse, error) {
-	return &proto.PingResponse{}, nil
+// SetHaltHeight sets or lifts the runtime halt height of a chain on all cosigners. Only the leader can set it,
+// so the request is rejected as unavailable by the other cosigners, to be retried on the leader.
+func (rpc *CosignerGRPCServer) SetHaltHeight(
+	_ context.Context,
+	req *proto.SetHaltHeightRequest,
+) (*proto.SetHaltHeightResponse, error) {
+	if !rpc.raftStore.IsLeader() {
+		return nil, status.Error(codes.Unavailable, "not leader")
+	}
+	if req.ChainID == "" {
+		return nil, status.Error(codes.InvalidArgument, "chain id cannot be empty")
+	}
+	if req.HaltHeight < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid halt height (%d), must not be negative", req.HaltHeight)
+	}
+
+	haltHeight, err := rpc.raftStore.HaltHeight(req.ChainID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Lift {
+		effective, err := rpc.thresholdValidator.HaltHeight(req.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		if effective == 0 {
+			return nil, status.Errorf(codes.FailedPrecondition, "chain id (%s) has no halt height to lift", req.ChainID)
+		}
+		haltHeight.LiftedHeight = effective
+	} else {
+		haltHeight.HaltHeight = req.HaltHeight
+		if req.HaltHeight > 0 && haltHeight.LiftedHeight >= req.HaltHeight {
+			haltHeight.LiftedHeight = 0
+		}
+	}
+
+	if err := rpc.raftStore.SetHaltHeight(req.ChainID, haltHeight); err != nil {
+		return nil, err
+	}
+
+	effective, err := rpc.thresholdValidator.HaltHeight(req.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	rpc.raftStore.logger.Info(
+		"Set halt height",
+		"chain_id", req.ChainID,
+		"halt_height", effective,
+	)
+	return &proto.SetHaltHeightResponse{HaltHeight: effective}, nil
+}
+
+// ClearSentryQuarantine lifts the quarantine of a sentry on all cosigners. Only the leader can clear it,
+// so the request is rejected as unavailable by the other cosigners, to be retried on the leader.
+func (rpc *CosignerGRPCServer) ClearSentryQuarantine(
+	_ context.Context,
+	req *proto.ClearSentryQuarantineRequest,
+) (*proto.ClearSentryQuarantineResponse, error) {
+	if !rpc.raftStore.IsLeader() {
+		return nil, status.Error(codes.Unavailable, "not leader")
+	}
+	if req.Sentry == "" {
+		return nil, status.Error(codes.InvalidArgument, "sentry cannot be empty")
+	}
+
+	conflict, err := rpc.raftStore.QuarantinedSentry(req.Sentry)
+	if err != nil {
+		return nil, err
+	}
+	if conflict == nil {
+		return nil, status.Errorf(codes.NotFound, "sentry (%s) is not quarantined", req.Sentry)
+	}
+
+	if err := rpc.raftStore.ClearQuarantinedSentry(req.Sentry); err != nil {
+		return nil, err
+	}
+	rpc.raftStore.logger.Info(
+		"Cleared quarantined sentry",
+		"sentry", req.Sentry,
+		"chain_id", conflict.ChainID,
+		"height", conflict.Height,
+	)
+	return &proto.ClearSentryQuarantineResponse{}, nil
+}
+
+// Ping advertises the security schemes of our cosigner, signed for the nonce of the request,
+// so that peers can negotiate the scheme with us.
+func (rpc *CosignerGRPCServer) Ping(_ context.Context, req *proto.PingRequest) (*proto.PingResponse, error) {
+	if rpc.cosigner == nil {
+		return &proto.PingResponse{}, nil
+	}
+	signatures, err := signPingSchemes(rpc.cosigner.security, req.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	return &proto.PingResponse{
+		SecuritySchemes: rpc.cosigner.security.Schemes(),
+		Signatures:      signatures,
+	}, nil
 }
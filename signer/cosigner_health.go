@@ -7,7 +7,6 @@ import (
 	"time"
 
 	cometlog "github.com/cometbft/cometbft/libs/log"
-	"github.com/strangelove-ventures/horcrux/v3/signer/proto"
 )
 
 const (
@@ -32,16 +31,18 @@ func NewCosignerHealth(logger cometlog.Logger, cosigners []Cosigner, leader Lead
 	}
 }
 
+// Reconcile pings the remote cosigners to measure their RTT. Followers only ping the cosigners
+// they negotiate the security scheme with, when our cosigner holds the keys of several schemes.
 func (ch *CosignerHealth) Reconcile(ctx context.Context) {
-	if !ch.leader.IsLeader() {
-		return
-	}
+	isLeader := ch.leader.IsLeader()
 	var wg sync.WaitGroup
-	wg.Add(len(ch.cosigners))
 	for _, cosigner := range ch.cosigners {
-		if rc, ok := cosigner.(*RemoteCosigner); ok {
-			go ch.updateRTT(ctx, rc, &wg)
+		rc, ok := cosigner.(*RemoteCosigner)
+		if !ok || (!isLeader && !rc.negotiatesSecurity()) {
+			continue
 		}
+		wg.Add(1)
+		go ch.updateRTT(ctx, rc, &wg)
 	}
 	wg.Wait()
 }
@@ -78,7 +79,7 @@ func (ch *CosignerHealth) updateRTT(ctx context.Context, cosigner *RemoteCosigne
 	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
 	defer cancel()
 
-	_, err := cosigner.client.Ping(ctx, &proto.PingRequest{})
+	err := cosigner.Ping(ctx)
 	if err != nil {
 		ch.logger.Error("Failed to ping", "cosigner", cosigner.GetID(), "error", err)
 		return
@@ -2,7 +2,7 @@ package signer
 
 import (
 	"encoding/json"
-	"os"
+	"fmt"
 
 	cometcrypto "github.com/cometbft/cometbft/crypto"
 	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
@@ -11,11 +11,42 @@ import (
 	amino "github.com/tendermint/go-amino"
 )
 
+// CosignerKey is a key shard of a cosigner for an m-of-n threshold signer, of any key type:
+// a *CosignerEd25519Key or a *CosignerBLS12381Key.
+type CosignerKey interface {
+	// ShardID returns the shard ID of the cosigner.
+	ShardID() int
+	// CombinedPubKey returns the public key of the combined key, whose type is the key type of the shard.
+	CombinedPubKey() cometcrypto.PubKey
+}
+
+// cosignerKeyType is the key type field of a key shard file. Ed25519 key shards do not have it,
+// so that their format is unchanged.
+type cosignerKeyType struct {
+	KeyType string `json:"keyType,omitempty"`
+}
+
+var _ CosignerKey = &CosignerEd25519Key{}
+
 // CosignerEd25519Key is a single Ed255219 key shard for an m-of-n threshold signer.
+// Key shards of BLS12-381 keys are CosignerBLS12381Key.
 type CosignerEd25519Key struct {
 	PubKey       cometcrypto.PubKey `json:"pubKey"`
 	PrivateShard []byte             `json:"privateShard"`
 	ID           int                `json:"id"`
+
+	// PublicShards are the public key shards of all cosigners, indexed by shard ID - 1.
+	// They are used to verify the partial signature of each cosigner before combining.
+	// Key shards created before public key shards were introduced do not have them.
+	PublicShards [][]byte `json:"publicShards,omitempty"`
+}
+
+func (key *CosignerEd25519Key) ShardID() int {
+	return key.ID
+}
+
+func (key *CosignerEd25519Key) CombinedPubKey() cometcrypto.PubKey {
+	return key.PubKey
 }
 
 func (key *CosignerEd25519Key) MarshalJSON() ([]byte, error) {
@@ -44,6 +75,7 @@ func (key *CosignerEd25519Key) UnmarshalJSON(data []byte) error {
 	type Alias CosignerEd25519Key
 
 	aux := &struct {
+		cosignerKeyType
 		PubkeyBytes []byte `json:"pubKey"`
 		*Alias
 	}{
@@ -53,6 +85,15 @@ func (key *CosignerEd25519Key) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	// Ed25519 key shards have no key type, only key shards of other key types have one
+	if aux.KeyType != "" && aux.KeyType != cometcryptoed25519.KeyType {
+		return fmt.Errorf("%s key shard is not an %s key shard", aux.KeyType, cometcryptoed25519.KeyType)
+	}
+	if _, ok := unmarshalPubKeyProtoBLS12381(aux.PubkeyBytes); ok {
+		return fmt.Errorf("%s key shard without a key type is not an %s key shard, create it again",
+			KeyTypeBLS12381, cometcryptoed25519.KeyType)
+	}
+
 	var pubkey cometcrypto.PubKey
 	var protoPubkey cometprotocrypto.PublicKey
 	err := protoPubkey.Unmarshal(aux.PubkeyBytes)
@@ -87,7 +128,7 @@ func (key *CosignerEd25519Key) UnmarshalJSON(data []byte) error {
 // LoadCosignerEd25519Key loads a CosignerEd25519Key from file.
 func LoadCosignerEd25519Key(file string) (CosignerEd25519Key, error) {
 	pvKey := CosignerEd25519Key{}
-	keyJSONBytes, err := os.ReadFile(file)
+	keyJSONBytes, err := readKeyFile(file)
 	if err != nil {
 		return pvKey, err
 	}
@@ -99,3 +140,38 @@ func LoadCosignerEd25519Key(file string) (CosignerEd25519Key, error) {
 
 	return pvKey, nil
 }
+
+// LoadCosignerKey loads the key shard of any key type from file.
+func LoadCosignerKey(file string) (CosignerKey, error) {
+	keyJSONBytes, err := readKeyFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return unmarshalCosignerKey(keyJSONBytes)
+}
+
+// unmarshalCosignerKey decodes a key shard of the key type of the data.
+// Key shards without a key type are Ed25519 key shards.
+func unmarshalCosignerKey(data []byte) (CosignerKey, error) {
+	var keyType cosignerKeyType
+	if err := json.Unmarshal(data, &keyType); err != nil {
+		return nil, err
+	}
+
+	var key CosignerKey
+	switch keyType.KeyType {
+	case "", cometcryptoed25519.KeyType:
+		key = &CosignerEd25519Key{}
+	case KeyTypeBLS12381:
+		key = &CosignerBLS12381Key{}
+	default:
+		return nil, fmt.Errorf("unsupported key shard type: %s", keyType.KeyType)
+	}
+
+	if err := json.Unmarshal(data, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
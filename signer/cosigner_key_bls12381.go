@@ -0,0 +1,85 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	cometcrypto "github.com/cometbft/cometbft/crypto"
+)
+
+var _ CosignerKey = &CosignerBLS12381Key{}
+
+// CosignerBLS12381Key is a single BLS12-381 key shard for an m-of-n threshold signer.
+// Its key type is written to the key shard file, so that it can not be loaded as an Ed25519 key shard
+// and an Ed25519 key shard can not be loaded as a BLS12-381 key shard.
+type CosignerBLS12381Key struct {
+	PubKey       PubKeyBLS12381 `json:"pubKey"`
+	PrivateShard []byte         `json:"privateShard"`
+	ID           int            `json:"id"`
+
+	// PublicShards are the public key shards of all cosigners, indexed by shard ID - 1.
+	PublicShards [][]byte `json:"publicShards,omitempty"`
+}
+
+func (key *CosignerBLS12381Key) ShardID() int {
+	return key.ID
+}
+
+func (key *CosignerBLS12381Key) CombinedPubKey() cometcrypto.PubKey {
+	return key.PubKey
+}
+
+func (key *CosignerBLS12381Key) MarshalJSON() ([]byte, error) {
+	type Alias CosignerBLS12381Key
+
+	return json.Marshal(&struct {
+		cosignerKeyType
+		*Alias
+	}{
+		cosignerKeyType: cosignerKeyType{KeyType: KeyTypeBLS12381},
+		Alias:           (*Alias)(key),
+	})
+}
+
+func (key *CosignerBLS12381Key) UnmarshalJSON(data []byte) error {
+	type Alias CosignerBLS12381Key
+
+	aux := &struct {
+		cosignerKeyType
+		*Alias
+	}{
+		Alias: (*Alias)(key),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if aux.KeyType != KeyTypeBLS12381 {
+		keyType := aux.KeyType
+		if keyType == "" {
+			keyType = "untyped"
+		}
+		return fmt.Errorf("%s key shard is not a %s key shard", keyType, KeyTypeBLS12381)
+	}
+	if len(key.PubKey) != BLS12381PubKeySize {
+		return fmt.Errorf("invalid %s public key size: %d", KeyTypeBLS12381, len(key.PubKey))
+	}
+
+	return nil
+}
+
+// LoadCosignerBLS12381Key loads a CosignerBLS12381Key from file.
+func LoadCosignerBLS12381Key(file string) (CosignerBLS12381Key, error) {
+	key := CosignerBLS12381Key{}
+	keyJSONBytes, err := readKeyFile(file)
+	if err != nil {
+		return key, err
+	}
+
+	err = json.Unmarshal(keyJSONBytes, &key)
+	if err != nil {
+		return key, err
+	}
+
+	return key, nil
+}
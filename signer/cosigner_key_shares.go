@@ -1,11 +1,14 @@
 package signer
 
 import (
+	"crypto/ecdh"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/json"
-	"os"
+	"fmt"
 
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
 	cometjson "github.com/cometbft/cometbft/libs/json"
 	"github.com/cometbft/cometbft/privval"
 	"github.com/ethereum/go-ethereum/crypto/ecies"
@@ -20,23 +23,84 @@ func CreateCosignerEd25519ShardsFromFile(priv string, threshold, shards uint8) (
 	if err != nil {
 		return nil, err
 	}
+	switch pv.PrivKey.(type) {
+	case cometcryptoed25519.PrivKey, PrivKeyEd25519Expanded:
+	case PrivKeyBLS12381:
+		return nil, fmt.Errorf("%s keys must be sharded as BLS12-381 shards", KeyTypeBLS12381)
+	default:
+		return nil, fmt.Errorf("%s keys can not be sharded, use single-signer mode instead", pv.PrivKey.Type())
+	}
 	return CreateCosignerEd25519Shards(pv, threshold, shards), nil
 }
 
 // CreateCosignerEd25519Shards creates CosignerEd25519Key objects from a privval.FilePVKey
 func CreateCosignerEd25519Shards(pv privval.FilePVKey, threshold, shards uint8) []CosignerEd25519Key {
-	privShards := tsed25519.DealShares(tsed25519.ExpandSecret(pv.PrivKey.Bytes()[:32]), threshold, shards)
+	secret := tsed25519.ExpandSecret(pv.PrivKey.Bytes()[:32])
+	if expanded, ok := pv.PrivKey.(PrivKeyEd25519Expanded); ok {
+		// a combined key already holds the secret scalar
+		secret = expanded.Bytes()[:32]
+	}
+	privShards := tsed25519.DealShares(secret, threshold, shards)
+	publicShards := make([][]byte, shards)
+	for i, shard := range privShards {
+		publicShards[i] = tsed25519.ScalarMultiplyBase(shard)
+	}
 	out := make([]CosignerEd25519Key, shards)
 	for i, shard := range privShards {
 		out[i] = CosignerEd25519Key{
 			PubKey:       pv.PubKey,
 			PrivateShard: shard,
 			ID:           i + 1,
+			PublicShards: publicShards,
 		}
 	}
 	return out
 }
 
+// CreateCosignerBLS12381ShardsFromFile creates CosignerBLS12381Key objects from a priv_validator_key.json file
+// with a BLS12-381 key.
+func CreateCosignerBLS12381ShardsFromFile(priv string, threshold, shards uint8) ([]CosignerBLS12381Key, error) {
+	pv, err := ReadPrivValidatorFile(priv)
+	if err != nil {
+		return nil, err
+	}
+	return CreateCosignerBLS12381Shards(pv, threshold, shards)
+}
+
+// CreateCosignerBLS12381Shards creates CosignerBLS12381Key objects from a privval.FilePVKey with a BLS12-381 key.
+func CreateCosignerBLS12381Shards(pv privval.FilePVKey, threshold, shards uint8) ([]CosignerBLS12381Key, error) {
+	privKey, ok := pv.PrivKey.(PrivKeyBLS12381)
+	if !ok {
+		return nil, fmt.Errorf("expected a %s private key, got %s", KeyTypeBLS12381, pv.PrivKey.Type())
+	}
+	secret, err := privKey.scalar()
+	if err != nil {
+		return nil, err
+	}
+	privShards, err := blsDealShares(secret, threshold, shards)
+	if err != nil {
+		return nil, err
+	}
+	publicShards := make([][]byte, shards)
+	for i, shard := range privShards {
+		publicShards[i] = blsPublicKey(shard)
+	}
+	out := make([]CosignerBLS12381Key, shards)
+	for i, shard := range privShards {
+		privateShard, err := shard.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out[i] = CosignerBLS12381Key{
+			PubKey:       privKey.PubKey().(PubKeyBLS12381),
+			PrivateShard: privateShard,
+			ID:           i + 1,
+			PublicShards: publicShards,
+		}
+	}
+	return out, nil
+}
+
 // CreateCosignerRSAShards generate  CosignerRSAKey objects.
 func CreateCosignerRSAShards(shards int) ([]CosignerRSAKey, error) {
 	rsaKeys, pubKeys, err := makeRSAKeys(shards)
@@ -55,24 +119,46 @@ func CreateCosignerRSAShards(shards int) ([]CosignerRSAKey, error) {
 }
 
 // ReadPrivValidatorFile reads in a privval.FilePVKey from a given file.
+// The file may also be a tmkms softsign key file, in the raw or base64 key format.
 func ReadPrivValidatorFile(priv string) (out privval.FilePVKey, err error) {
 	var bz []byte
-	if bz, err = os.ReadFile(priv); err != nil {
+	if bz, err = readKeyFile(priv); err != nil {
 		return
 	}
+	if !json.Valid(bz) {
+		return ParseTmkmsSoftsignKey(bz)
+	}
 	if err = cometjson.Unmarshal(bz, &out); err != nil {
 		return
 	}
 	return
 }
 
+// WritePrivValidatorFile writes a privval.FilePVKey to a given file name.
+func WritePrivValidatorFile(key privval.FilePVKey, file string) error {
+	jsonBytes, err := cometjson.MarshalIndent(key, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(file, jsonBytes)
+}
+
 // WriteCosignerEd25519ShardFile writes a cosigner Ed25519 key to a given file name.
 func WriteCosignerEd25519ShardFile(cosigner CosignerEd25519Key, file string) error {
 	jsonBytes, err := json.Marshal(&cosigner)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(file, jsonBytes, 0600)
+	return writeKeyFile(file, jsonBytes)
+}
+
+// WriteCosignerShardFile writes a cosigner key shard of any key type to a given file name.
+func WriteCosignerShardFile(key CosignerKey, file string) error {
+	jsonBytes, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(file, jsonBytes)
 }
 
 // WriteCosignerRSAShardFile writes a cosigner RSA key to a given file name.
@@ -81,7 +167,7 @@ func WriteCosignerRSAShardFile(cosigner CosignerRSAKey, file string) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(file, jsonBytes, 0600)
+	return writeKeyFile(file, jsonBytes)
 }
 
 // CreateCosignerECIESShards generates CosignerECIESKey objects.
@@ -107,7 +193,47 @@ func WriteCosignerECIESShardFile(cosigner CosignerECIESKey, file string) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(file, jsonBytes, 0600)
+	return writeKeyFile(file, jsonBytes)
+}
+
+// CreateCosignerX25519Shards generates CosignerX25519Key objects.
+func CreateCosignerX25519Shards(shards int) ([]CosignerX25519Key, error) {
+	x25519Keys := make([]*ecdh.PrivateKey, shards)
+	x25519Pubs := make([]*ecdh.PublicKey, shards)
+	ed25519Keys := make([]ed25519.PrivateKey, shards)
+	ed25519Pubs := make([]ed25519.PublicKey, shards)
+	for i := 0; i < shards; i++ {
+		x25519Key, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		ed25519Pub, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		x25519Keys[i], x25519Pubs[i] = x25519Key, x25519Key.PublicKey()
+		ed25519Keys[i], ed25519Pubs[i] = ed25519Key, ed25519Pub
+	}
+	out := make([]CosignerX25519Key, shards)
+	for i := range out {
+		out[i] = CosignerX25519Key{
+			ID:          i + 1,
+			X25519Key:   x25519Keys[i],
+			Ed25519Key:  ed25519Keys[i],
+			X25519Pubs:  x25519Pubs,
+			Ed25519Pubs: ed25519Pubs,
+		}
+	}
+	return out, nil
+}
+
+// WriteCosignerX25519ShardFile writes a cosigner X25519 key to a given file name.
+func WriteCosignerX25519ShardFile(cosigner CosignerX25519Key, file string) error {
+	jsonBytes, err := json.Marshal(&cosigner)
+	if err != nil {
+		return err
+	}
+	return writeKeyFile(file, jsonBytes)
 }
 
 func makeRSAKeys(num int) (rsaKeys []*rsa.PrivateKey, pubKeys []*rsa.PublicKey, err error) {
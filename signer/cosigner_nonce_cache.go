@@ -33,6 +33,9 @@ type CosignerNonceCache struct {
 
 	threshold uint8
 
+	// allowMissingCommitments accepts nonces without commitments from cosigners of previous versions.
+	allowMissingCommitments bool
+
 	cache *NonceCache
 
 	pruner NonceCachePruner
@@ -286,6 +289,9 @@ func (cnc *CosignerNonceCache) LoadN(ctx context.Context, n int) {
 
 			peerStartTime := time.Now()
 			n, err := p.GetNonces(ctx, uuids)
+			if err == nil {
+				err = n.verifyCommitments(p.GetID(), cnc.allowMissingCommitments)
+			}
 			if err != nil {
 				// Significant missing shares may lead to signature failure
 				missedNonces.WithLabelValues(p.GetAddress()).Add(float64(1))
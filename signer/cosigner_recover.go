@@ -0,0 +1,210 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+)
+
+// Recover restores the key shard of the target cosigner from the key shards of the helpers,
+// without revealing the key shards of the helpers to the target or to each other.
+//
+// Each helper multiplies its key shard by its lagrange coefficient at the target's shamir index
+// and blinds the result with pairwise masks exchanged with the other helpers, which cancel out
+// in the sum. The blinded contributions are sent to the target, encrypted with the target's
+// ECIES key, and their sum is the target's key shard. Along with its contribution, each helper
+// sends the combined public key and its public key shard, which the target uses to verify
+// the recovered key shard. There must be at least the threshold number of distinct helpers.
+//
+// key is our key shard, which must be provided if we are a helper.
+// The returned key shard is nil if we are not the target.
+func (c *KeyCeremony) Recover(
+	ctx context.Context,
+	chainID string,
+	key *CosignerEd25519Key,
+	target int,
+	helpers []int,
+	threshold uint8,
+) (*CosignerEd25519Key, error) {
+	id := c.ID()
+
+	helpers = sortedIDs(helpers)
+
+	if threshold == 0 || len(helpers) < int(threshold) {
+		return nil, fmt.Errorf("number of helpers (%d) must be at least the threshold (%d), which must be greater than zero",
+			len(helpers), threshold)
+	}
+
+	if containsID(helpers, target) {
+		return nil, fmt.Errorf("target cosigner %d can not be a helper", target)
+	}
+
+	for _, i := range append([]int{target}, helpers...) {
+		if i != id && c.peers[i] == nil {
+			return nil, fmt.Errorf("cosigner %d is not part of the key ceremony", i)
+		}
+	}
+
+	maskSession := fmt.Sprintf("recover/%s/%d/mask", chainID, target)
+	contributionSession := fmt.Sprintf("recover/%s/%d/contribution", chainID, target)
+
+	if id == target {
+		return c.recoverTarget(ctx, chainID, contributionSession, helpers)
+	}
+
+	if !containsID(helpers, id) {
+		return nil, fmt.Errorf("cosigner %d is neither the target nor a helper", id)
+	}
+
+	if key == nil {
+		return nil, fmt.Errorf("helper %d must have a key shard", id)
+	}
+
+	var otherHelpers []int
+	for _, helper := range helpers {
+		if helper != id {
+			otherHelpers = append(otherHelpers, helper)
+		}
+	}
+
+	c.open(maskSession, otherHelpers)
+
+	masks := make(map[int][]byte, len(otherHelpers))
+	contribution := edwards25519.NewScalar()
+	for _, helper := range otherHelpers {
+		mask, err := randomScalar()
+		if err != nil {
+			return nil, err
+		}
+		masks[helper] = mask.Bytes()
+		contribution.Add(contribution, mask)
+	}
+
+	c.logger.Info("Exchanging recovery masks", "chain_id", chainID, "target", target)
+
+	if err := c.send(ctx, maskSession, otherHelpers, nil, masks); err != nil {
+		return nil, err
+	}
+
+	received, err := c.wait(ctx, maskSession)
+	if err != nil {
+		return nil, err
+	}
+
+	for source, d := range received {
+		mask, err := edwards25519.NewScalar().SetCanonicalBytes(d.share)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mask from cosigner %d: %w", source, err)
+		}
+		contribution.Subtract(contribution, mask)
+	}
+
+	lambda, err := lagrangeCoefficient(id, helpers, target)
+	if err != nil {
+		return nil, err
+	}
+
+	shard, err := edwards25519.NewScalar().SetCanonicalBytes(key.PrivateShard)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key shard: %w", err)
+	}
+
+	contribution.MultiplyAdd(lambda, shard, contribution)
+
+	publicShard := new(edwards25519.Point).ScalarBaseMult(shard).Bytes()
+
+	c.logger.Info("Sending recovery contribution", "chain_id", chainID, "target", target)
+
+	if err := c.send(
+		ctx,
+		contributionSession,
+		[]int{target},
+		[][]byte{key.PubKey.Bytes(), publicShard},
+		map[int][]byte{target: contribution.Bytes()},
+	); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// recoverTarget collects the contributions of the helpers and verifies the recovered key shard.
+func (c *KeyCeremony) recoverTarget(
+	ctx context.Context,
+	chainID string,
+	contributionSession string,
+	helpers []int,
+) (*CosignerEd25519Key, error) {
+	id := c.ID()
+
+	c.open(contributionSession, helpers)
+
+	c.logger.Info("Waiting for recovery contributions", "chain_id", chainID, "helpers", helpers)
+
+	contributions, err := c.wait(ctx, contributionSession)
+	if err != nil {
+		return nil, err
+	}
+
+	var pubKey []byte
+	publicShards := make([][]byte, len(helpers))
+	privateShard := edwards25519.NewScalar()
+
+	for i, helper := range helpers {
+		d := contributions[helper]
+		if len(d.deal.Commitments) != 2 {
+			return nil, fmt.Errorf("cosigner %d sent %d commitments, expected 2", helper, len(d.deal.Commitments))
+		}
+		if pubKey == nil {
+			pubKey = d.deal.Commitments[0]
+		} else if !bytes.Equal(pubKey, d.deal.Commitments[0]) {
+			return nil, fmt.Errorf("cosigner %d has a different public key", helper)
+		}
+		publicShards[i] = d.deal.Commitments[1]
+
+		contribution, err := edwards25519.NewScalar().SetCanonicalBytes(d.share)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contribution from cosigner %d: %w", helper, err)
+		}
+		privateShard.Add(privateShard, contribution)
+	}
+
+	// The public key shards of the helpers must interpolate to the combined public key,
+	// and to the public key of the recovered key shard at our shamir index.
+	combined, err := interpolatePoints(helpers, publicShards, 0)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(combined.Bytes(), pubKey) {
+		return nil, fmt.Errorf("public key shards of the helpers do not match the public key")
+	}
+
+	expected, err := interpolatePoints(helpers, publicShards, id)
+	if err != nil {
+		return nil, err
+	}
+	if new(edwards25519.Point).ScalarBaseMult(privateShard).Equal(expected) != 1 {
+		return nil, fmt.Errorf("recovered key shard does not match the public key shards of the helpers")
+	}
+
+	// The public key shards of all cosigners lie on the same polynomial in the exponent.
+	ids := c.ids()
+	publicShardsAll := make([][]byte, ids[len(ids)-1])
+	for _, i := range ids {
+		p, err := interpolatePoints(helpers, publicShards, i)
+		if err != nil {
+			return nil, err
+		}
+		publicShardsAll[i-1] = p.Bytes()
+	}
+
+	return &CosignerEd25519Key{
+		PubKey:       cometcryptoed25519.PubKey(pubKey),
+		PrivateShard: privateShard.Bytes(),
+		ID:           id,
+		PublicShards: publicShardsAll,
+	}, nil
+}
@@ -0,0 +1,76 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestRecover2of3(t *testing.T) {
+	testRecover(t, 2, 3, 3, []int{1, 2})
+}
+
+func TestRecover3of5(t *testing.T) {
+	testRecover(t, 3, 5, 2, []int{1, 4, 5})
+}
+
+func TestRecoverBelowThreshold(t *testing.T) {
+	eciesKeys, err := CreateCosignerECIESShards(3)
+	require.NoError(t, err)
+
+	ceremony, err := NewKeyCeremony(log.NewNopLogger(), NewCosignerSecurityECIES(eciesKeys[0]),
+		testKeyCeremonyCosigners(t, 3), insecure.NewCredentials())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// a helper that is listed twice only counts once
+	_, err = ceremony.Recover(ctx, testChainID, nil, 1, []int{2, 2}, 2)
+	require.ErrorContains(t, err, "must be at least the threshold")
+}
+
+func testRecover(t *testing.T, threshold, total uint8, target int, helpers []int) {
+	eciesKeys, err := CreateCosignerECIESShards(int(total))
+	require.NoError(t, err)
+
+	keys := testRunDKG(t, eciesKeys, threshold)
+
+	cosigners := testKeyCeremonyCosigners(t, int(total))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// only the target and the helpers take part in the recovery
+	participants := append([]int{target}, helpers...)
+
+	ceremonies := make([]*KeyCeremony, len(participants))
+	for i, id := range participants {
+		ceremonies[i], err = NewKeyCeremony(log.NewNopLogger(), NewCosignerSecurityECIES(eciesKeys[id-1]), cosigners,
+			insecure.NewCredentials())
+		require.NoError(t, err)
+		require.NoError(t, ceremonies[i].Start())
+		defer ceremonies[i].Stop()
+	}
+
+	recovered := make([]*CosignerEd25519Key, len(participants))
+	testRunCeremonies(t, len(participants), func(i int) (err error) {
+		var key *CosignerEd25519Key
+		if id := participants[i]; id != target {
+			key = &keys[id-1]
+		}
+		recovered[i], err = ceremonies[i].Recover(ctx, testChainID, key, target, helpers, threshold)
+		return err
+	})
+
+	for i := range helpers {
+		require.Nil(t, recovered[i+1])
+	}
+
+	require.NotNil(t, recovered[0])
+	require.Equal(t, keys[target-1], *recovered[0])
+}
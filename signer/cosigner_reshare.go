@@ -0,0 +1,245 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+
+	"filippo.io/edwards25519"
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+)
+
+// Reshare produces new key shards of the existing combined key for a new set of cosigners
+// with a new threshold, without reconstructing the key.
+//
+// Each dealer multiplies its current shard by its lagrange coefficient over the set of dealers,
+// so that the weighted shards sum to the combined private key, and deals shares of the result
+// to the receivers with Feldman verifiable secret sharing. The new key shard of each receiver
+// is the sum of the shares it received. The dealers must hold at least the current threshold
+// of key shards. Shards of the previous set can not be combined with shards of the new set.
+//
+// key is the current key shard, which must be provided if we are a dealer.
+// The returned key shard is nil if we are not a receiver.
+func (c *KeyCeremony) Reshare(
+	ctx context.Context,
+	chainID string,
+	key *CosignerEd25519Key,
+	dealers []int,
+	receivers []int,
+	threshold uint8,
+) (*CosignerEd25519Key, error) {
+	id := c.ID()
+
+	dealers = sortedIDs(dealers)
+	receivers = sortedIDs(receivers)
+
+	if len(dealers) == 0 {
+		return nil, fmt.Errorf("no dealers")
+	}
+
+	if threshold == 0 || int(threshold) > len(receivers) {
+		return nil, fmt.Errorf("threshold (%d) must be greater than zero and at most the number of receivers (%d)",
+			threshold, len(receivers))
+	}
+
+	for _, i := range append(append([]int{}, dealers...), receivers...) {
+		if i != id && c.peers[i] == nil {
+			return nil, fmt.Errorf("cosigner %d is not part of the key ceremony", i)
+		}
+	}
+
+	isDealer := containsID(dealers, id)
+	isReceiver := containsID(receivers, id)
+
+	if !isDealer && !isReceiver {
+		return nil, fmt.Errorf("cosigner %d is neither a dealer nor a receiver", id)
+	}
+
+	if isDealer && key == nil {
+		return nil, fmt.Errorf("dealer %d must have a key shard", id)
+	}
+
+	// Only the dealers and receivers take part, so that current cosigners that neither deal
+	// nor receive a new shard may be offline.
+	var participants []int
+	for _, i := range sortedIDs(append(append([]int{}, dealers...), receivers...)) {
+		if i != id {
+			participants = append(participants, i)
+		}
+	}
+
+	dealSession := fmt.Sprintf("reshare/%s/deal", chainID)
+	confirmSession := fmt.Sprintf("reshare/%s/confirm", chainID)
+
+	var otherDealers []int
+	for _, dealer := range dealers {
+		if dealer != id {
+			otherDealers = append(otherDealers, dealer)
+		}
+	}
+
+	c.open(dealSession, otherDealers)
+	c.open(confirmSession, participants)
+
+	allCommitments := make(map[int][][]byte, len(dealers))
+	privateShard := edwards25519.NewScalar()
+
+	if isDealer {
+		lambda, err := lagrangeCoefficient(id, dealers, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		shard, err := edwards25519.NewScalar().SetCanonicalBytes(key.PrivateShard)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key shard: %w", err)
+		}
+
+		poly, err := newVSSPolynomial(edwards25519.NewScalar().Multiply(lambda, shard), threshold)
+		if err != nil {
+			return nil, err
+		}
+
+		commitments := poly.commitments()
+		shares := make(map[int][]byte, len(receivers))
+		for _, receiver := range receivers {
+			if receiver == id {
+				privateShard.Add(privateShard, poly.evaluate(id))
+				continue
+			}
+			shares[receiver] = poly.evaluate(receiver).Bytes()
+		}
+
+		allCommitments[id] = commitments
+
+		c.logger.Info("Sending reshare deals", "chain_id", chainID, "threshold", threshold, "receivers", len(receivers))
+
+		// Commitments are sent to every participant so that all of them can confirm the result.
+		if err := c.send(ctx, dealSession, participants, commitments, shares); err != nil {
+			return nil, err
+		}
+	}
+
+	deals, err := c.wait(ctx, dealSession)
+	if err != nil {
+		return nil, err
+	}
+
+	for source, d := range deals {
+		if len(d.deal.Commitments) != int(threshold) {
+			return nil, fmt.Errorf("cosigner %d dealt %d commitments, expected threshold (%d)",
+				source, len(d.deal.Commitments), threshold)
+		}
+		allCommitments[source] = d.deal.Commitments
+
+		if !isReceiver {
+			continue
+		}
+		if err := verifyVSSShare(id, d.share, d.deal.Commitments); err != nil {
+			return nil, fmt.Errorf("invalid deal from cosigner %d: %w", source, err)
+		}
+		share, err := edwards25519.NewScalar().SetCanonicalBytes(d.share)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deal from cosigner %d: %w", source, err)
+		}
+		privateShard.Add(privateShard, share)
+	}
+
+	secretCommitments := make([][]byte, 0, len(dealers))
+	for _, dealer := range dealers {
+		secretCommitments = append(secretCommitments, allCommitments[dealer][0])
+	}
+
+	pubKey, err := addPoints(secretCommitments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dealers know the combined public key and confirm that it is unchanged.
+	// Receivers that were not part of the previous set rely on that confirmation.
+	if key != nil && !bytes.Equal(pubKey, key.PubKey.Bytes()) {
+		return nil, fmt.Errorf("reshared public key does not match the current public key")
+	}
+
+	transcript := reshareTranscript(dealers, receivers, threshold, allCommitments)
+
+	c.logger.Info("Confirming reshare commitments", "chain_id", chainID)
+
+	if err := c.send(ctx, confirmSession, participants, [][]byte{transcript}, nil); err != nil {
+		return nil, err
+	}
+
+	confirms, err := c.wait(ctx, confirmSession)
+	if err != nil {
+		return nil, err
+	}
+
+	for source, d := range confirms {
+		if len(d.deal.Commitments) != 1 || !bytes.Equal(d.deal.Commitments[0], transcript) {
+			return nil, fmt.Errorf("cosigner %d did not confirm the same reshare", source)
+		}
+	}
+
+	if !isReceiver {
+		return nil, nil
+	}
+
+	dealtCommitments := make([][][]byte, 0, len(dealers))
+	for _, dealer := range dealers {
+		dealtCommitments = append(dealtCommitments, allCommitments[dealer])
+	}
+
+	publicShards, err := vssPublicShards(receivers, dealtCommitments)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CosignerEd25519Key{
+		PubKey:       cometcryptoed25519.PubKey(pubKey),
+		PrivateShard: privateShard.Bytes(),
+		ID:           id,
+		PublicShards: publicShards,
+	}, nil
+}
+
+// reshareTranscript returns a hash of the reshare parameters and the commitments of all dealers.
+func reshareTranscript(dealers, receivers []int, threshold uint8, commitments map[int][][]byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{threshold})
+	for _, ids := range [][]int{dealers, receivers} {
+		var bz [8]byte
+		binary.BigEndian.PutUint64(bz[:], uint64(len(ids)))
+		h.Write(bz[:])
+		for _, id := range ids {
+			binary.BigEndian.PutUint64(bz[:], uint64(id))
+			h.Write(bz[:])
+		}
+	}
+	h.Write(dkgTranscript(dealers, commitments))
+	return h.Sum(nil)
+}
+
+// sortedIDs returns the distinct ids in ascending order.
+func sortedIDs(ids []int) []int {
+	out := append([]int{}, ids...)
+	sort.Ints(out)
+	for i := 1; i < len(out); i++ {
+		if out[i] == out[i-1] {
+			out = append(out[:i], out[i+1:]...)
+			i--
+		}
+	}
+	return out
+}
+
+func containsID(ids []int, id int) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+func TestReshare2of3To3of5(t *testing.T) {
+	const (
+		threshold    = 2
+		total        = 3
+		newThreshold = 3
+		newTotal     = 5
+	)
+
+	eciesKeys, err := CreateCosignerECIESShards(newTotal)
+	require.NoError(t, err)
+
+	// the first 3 cosigners generate the key
+	keys := testRunDKG(t, eciesKeys[:total], threshold)
+
+	cosigners := testKeyCeremonyCosigners(t, newTotal)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ceremonies := make([]*KeyCeremony, newTotal)
+	for i := range ceremonies {
+		ceremonies[i], err = NewKeyCeremony(log.NewNopLogger(), NewCosignerSecurityECIES(eciesKeys[i]), cosigners,
+			insecure.NewCredentials())
+		require.NoError(t, err)
+		require.NoError(t, ceremonies[i].Start())
+		defer ceremonies[i].Stop()
+	}
+
+	// dealers 1 and 3 reshare to all 5 cosigners, repeated dealers are only counted once
+	dealers := []int{3, 1, 3}
+	receivers := []int{1, 2, 3, 4, 5}
+
+	newKeys := make([]*CosignerEd25519Key, newTotal)
+	testRunCeremonies(t, newTotal, func(i int) (err error) {
+		var key *CosignerEd25519Key
+		if i < total {
+			key = &keys[i]
+		}
+		newKeys[i], err = ceremonies[i].Reshare(ctx, testChainID, key, dealers, receivers, newThreshold)
+		return err
+	})
+
+	newShards := make([]CosignerEd25519Key, newTotal)
+	for i, key := range newKeys {
+		require.NotNil(t, key)
+		require.Equal(t, i+1, key.ID)
+		require.Equal(t, keys[0].PubKey, key.PubKey)
+		newShards[i] = *key
+	}
+
+	msg := []byte("reshare")
+
+	sig := testThresholdSign(t, newThreshold, newTotal, newShards[:newThreshold], msg)
+	require.True(t, keys[0].PubKey.VerifySignature(msg, sig))
+
+	sig = testThresholdSign(t, newThreshold, newTotal, newShards[newTotal-newThreshold:], msg)
+	require.True(t, keys[0].PubKey.VerifySignature(msg, sig))
+
+	// old shards can not be combined with new shards
+	mixed := []CosignerEd25519Key{keys[0], keys[1], newShards[2]}
+	sig = testThresholdSign(t, newThreshold, newTotal, mixed, msg)
+	require.False(t, keys[0].PubKey.VerifySignature(msg, sig))
+}
+
+func TestReshareWithOfflineNonDealer(t *testing.T) {
+	const (
+		threshold = 2
+		total     = 3
+		newTotal  = 4
+	)
+
+	eciesKeys, err := CreateCosignerECIESShards(newTotal)
+	require.NoError(t, err)
+
+	keys := testRunDKG(t, eciesKeys[:total], threshold)
+
+	cosigners := testKeyCeremonyCosigners(t, newTotal)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// cosigner 2 is a current cosigner that neither deals nor receives, and is offline
+	dealers := []int{1, 3}
+	receivers := []int{1, 3, 4}
+	online := []int{1, 3, 4}
+
+	ceremonies := make(map[int]*KeyCeremony, len(online))
+	for _, id := range online {
+		ceremonies[id], err = NewKeyCeremony(log.NewNopLogger(), NewCosignerSecurityECIES(eciesKeys[id-1]), cosigners,
+			insecure.NewCredentials())
+		require.NoError(t, err)
+		require.NoError(t, ceremonies[id].Start())
+		defer ceremonies[id].Stop()
+	}
+
+	newKeys := make([]*CosignerEd25519Key, len(online))
+	testRunCeremonies(t, len(online), func(i int) (err error) {
+		id := online[i]
+		var key *CosignerEd25519Key
+		if id <= total {
+			key = &keys[id-1]
+		}
+		newKeys[i], err = ceremonies[id].Reshare(ctx, testChainID, key, dealers, receivers, threshold)
+		return err
+	})
+
+	newShards := make([]CosignerEd25519Key, len(online))
+	for i, key := range newKeys {
+		require.NotNil(t, key)
+		require.Equal(t, online[i], key.ID)
+		require.Equal(t, keys[0].PubKey, key.PubKey)
+		newShards[i] = *key
+	}
+
+	msg := []byte("reshare")
+
+	sig := testThresholdSign(t, threshold, newTotal, []CosignerEd25519Key{newShards[0], newShards[2]}, msg)
+	require.True(t, keys[0].PubKey.VerifySignature(msg, sig))
+
+	// the offline cosigner is not a participant
+	_, err = ceremonies[1].Reshare(ctx, testChainID, nil, []int{3}, []int{3, 4}, threshold)
+	require.ErrorContains(t, err, "neither a dealer nor a receiver")
+}
@@ -1,22 +1,45 @@
 package signer
 
+const (
+	// CosignerSecuritySchemeX25519 encrypts with X25519 and ChaCha20-Poly1305, and signs with Ed25519.
+	CosignerSecuritySchemeX25519 = "x25519"
+
+	// CosignerSecuritySchemeECIES encrypts with ECIES and signs with ECDSA, both over secp256k1.
+	CosignerSecuritySchemeECIES = "ecies"
+
+	// CosignerSecuritySchemeRSA encrypts with RSA-OAEP and signs with RSA-PSS. It is deprecated.
+	CosignerSecuritySchemeRSA = "rsa"
+)
+
 // CosignerSecurity is an interface for the security layer of the cosigner.
 type CosignerSecurity interface {
 	// GetID returns the ID of the cosigner.
 	GetID() int
 
-	// EncryptAndSign encrypts the nonce and signs it for authentication.
+	// Schemes returns the security schemes of the cosigner, most preferred first.
+	Schemes() []string
+
+	// EncryptAndSign encrypts the nonce and signs it, including the nonce commitments, for authentication.
 	EncryptAndSign(
 		id int,
 		noncePub []byte,
 		nonceShare []byte,
+		commitments [][]byte,
 	) (CosignerNonce, error)
 
-	// DecryptAndVerify decrypts the nonce and verifies the signature to authenticate the source cosigner.
+	// DecryptAndVerify decrypts the nonce and verifies the signature, including the nonce commitments,
+	// to authenticate the source cosigner.
 	DecryptAndVerify(
 		id int,
 		encryptedNoncePub []byte,
 		encryptedNonceShare []byte,
+		commitments [][]byte,
 		signature []byte,
 	) (noncePub []byte, nonceShare []byte, err error)
+
+	// Sign signs a message, such as a gRPC request, to authenticate this cosigner.
+	Sign(msg []byte) ([]byte, error)
+
+	// Verify verifies the signature of a message from the cosigner with the ID.
+	Verify(id int, msg []byte, signature []byte) error
 }
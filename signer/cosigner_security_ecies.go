@@ -7,9 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
-	"os"
 
-	cometjson "github.com/cometbft/cometbft/libs/json"
 	"github.com/ethereum/go-ethereum/crypto/ecies"
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
 	"golang.org/x/sync/errgroup"
@@ -100,7 +98,7 @@ func (key *CosignerECIESKey) UnmarshalJSON(data []byte) error {
 // LoadCosignerECIESKey loads a CosignerECIESKey from file.
 func LoadCosignerECIESKey(file string) (CosignerECIESKey, error) {
 	pvKey := CosignerECIESKey{}
-	keyJSONBytes, err := os.ReadFile(file)
+	keyJSONBytes, err := readKeyFile(file)
 	if err != nil {
 		return pvKey, err
 	}
@@ -135,8 +133,18 @@ func (c *CosignerSecurityECIES) GetID() int {
 	return c.key.ID
 }
 
+// Schemes returns the security scheme of the cosigner.
+func (c *CosignerSecurityECIES) Schemes() []string {
+	return []string{CosignerSecuritySchemeECIES}
+}
+
 // EncryptAndSign encrypts the nonce and signs it for authentication.
-func (c *CosignerSecurityECIES) EncryptAndSign(id int, noncePub []byte, nonceShare []byte) (CosignerNonce, error) {
+func (c *CosignerSecurityECIES) EncryptAndSign(
+	id int,
+	noncePub []byte,
+	nonceShare []byte,
+	commitments [][]byte,
+) (CosignerNonce, error) {
 	nonce := CosignerNonce{
 		SourceID: c.key.ID,
 	}
@@ -171,7 +179,7 @@ func (c *CosignerSecurityECIES) EncryptAndSign(id int, noncePub []byte, nonceSha
 	// sign the response payload with our private key
 	// cosigners can verify the signature to confirm sender validity
 
-	jsonBytes, err := cometjson.Marshal(nonce)
+	jsonBytes, err := nonceDigestMessage(nonce.SourceID, nonce.PubKey, nonce.Share, commitments)
 
 	if err != nil {
 		return nonce, err
@@ -188,6 +196,7 @@ func (c *CosignerSecurityECIES) EncryptAndSign(id int, noncePub []byte, nonceSha
 	}
 
 	nonce.DestinationID = id
+	nonce.Commitments = commitments
 	nonce.Signature = signature
 
 	return nonce, nil
@@ -199,6 +208,7 @@ func (c *CosignerSecurityECIES) DecryptAndVerify(
 	id int,
 	encryptedNoncePub []byte,
 	encryptedNonceShare []byte,
+	commitments [][]byte,
 	signature []byte,
 ) ([]byte, []byte, error) {
 	pubKey, ok := c.eciesPubKeys[id]
@@ -206,13 +216,7 @@ func (c *CosignerSecurityECIES) DecryptAndVerify(
 		return nil, nil, fmt.Errorf("unknown cosigner: %d", id)
 	}
 
-	digestMsg := CosignerNonce{
-		SourceID: id,
-		PubKey:   encryptedNoncePub,
-		Share:    encryptedNonceShare,
-	}
-
-	digestBytes, err := cometjson.Marshal(digestMsg)
+	digestBytes, err := nonceDigestMessage(id, encryptedNoncePub, encryptedNonceShare, commitments)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -251,3 +255,104 @@ func (c *CosignerSecurityECIES) DecryptAndVerify(
 
 	return noncePub, nonceShare, nil
 }
+
+// Sign signs the SHA-256 hash of the message with our ECDSA key.
+func (c *CosignerSecurityECIES) Sign(msg []byte) ([]byte, error) {
+	hash := sha256.Sum256(msg)
+	return ecdsa.SignASN1(rand.Reader, c.key.ECIESKey.ExportECDSA(), hash[:])
+}
+
+// Verify verifies the ECDSA signature of the message from the cosigner with the ID.
+func (c *CosignerSecurityECIES) Verify(id int, msg []byte, signature []byte) error {
+	pubKey, ok := c.eciesPubKeys[id]
+	if !ok {
+		return fmt.Errorf("unknown cosigner: %d", id)
+	}
+
+	hash := sha256.Sum256(msg)
+	if !ecdsa.VerifyASN1(pubKey.PublicKey.ExportECDSA(), hash[:], signature) {
+		return fmt.Errorf("signature is invalid")
+	}
+
+	return nil
+}
+
+// EncryptAndSignDeal encrypts the key share for the destination cosigner
+// and signs the deal for authentication.
+func (c *CosignerSecurityECIES) EncryptAndSignDeal(
+	id int,
+	session string,
+	commitments [][]byte,
+	share []byte,
+) (CosignerDeal, error) {
+	deal := CosignerDeal{
+		Session:       session,
+		SourceID:      c.key.ID,
+		DestinationID: id,
+		Commitments:   commitments,
+	}
+
+	pubKey, ok := c.eciesPubKeys[id]
+	if !ok {
+		return deal, fmt.Errorf("unknown cosigner ID: %d", id)
+	}
+
+	if len(share) > 0 {
+		encryptedShare, err := ecies.Encrypt(rand.Reader, pubKey.PublicKey, share, nil, nil)
+		if err != nil {
+			return deal, err
+		}
+		deal.Share = encryptedShare
+	}
+
+	digest, err := deal.digest()
+	if err != nil {
+		return deal, err
+	}
+
+	signature, err := ecdsa.SignASN1(
+		rand.Reader,
+		c.key.ECIESKey.ExportECDSA(),
+		digest,
+	)
+	if err != nil {
+		return deal, err
+	}
+
+	deal.Signature = signature
+
+	return deal, nil
+}
+
+// DecryptAndVerifyDeal verifies the signature of the deal to authenticate
+// the source cosigner and decrypts the key share.
+func (c *CosignerSecurityECIES) DecryptAndVerifyDeal(deal CosignerDeal) ([]byte, error) {
+	if deal.DestinationID != c.key.ID {
+		return nil, fmt.Errorf("deal is for cosigner %d, not %d", deal.DestinationID, c.key.ID)
+	}
+
+	pubKey, ok := c.eciesPubKeys[deal.SourceID]
+	if !ok {
+		return nil, fmt.Errorf("unknown cosigner: %d", deal.SourceID)
+	}
+
+	digest, err := deal.digest()
+	if err != nil {
+		return nil, err
+	}
+
+	if !ecdsa.VerifyASN1(pubKey.PublicKey.ExportECDSA(), digest, deal.Signature) {
+		return nil, fmt.Errorf("signature is invalid")
+	}
+
+	if len(deal.Share) == 0 {
+		return nil, nil
+	}
+
+	share, err := c.key.ECIESKey.Decrypt(deal.Share, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt share: %w", err)
+	}
+
+	return share, nil
+}
@@ -55,26 +55,86 @@ func TestCosignerECIES(t *testing.T) {
 	require.ErrorContains(t, err, "failed to decrypt")
 }
 
+func TestCosignerECIESNonceCommitments(t *testing.T) {
+	keys, err := CreateCosignerECIESShards(2)
+	require.NoError(t, err)
+
+	security1 := NewCosignerSecurityECIES(keys[0])
+	security2 := NewCosignerSecurityECIES(keys[1])
+
+	commitments := [][]byte{[]byte("mock_pub"), []byte("mock_commitment")}
+
+	nonce, err := security1.EncryptAndSign(2, []byte("mock_pub"), []byte("mock_share"), commitments)
+	require.NoError(t, err)
+	require.Equal(t, commitments, nonce.Commitments)
+
+	_, _, err = security2.DecryptAndVerify(1, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
+	require.NoError(t, err)
+
+	// the commitments are signed, so they can not be replaced or dropped on the way
+	_, _, err = security2.DecryptAndVerify(1, nonce.PubKey, nonce.Share, commitments[:1], nonce.Signature)
+	require.ErrorContains(t, err, "signature is invalid")
+
+	_, _, err = security2.DecryptAndVerify(1, nonce.PubKey, nonce.Share, nil, nonce.Signature)
+	require.ErrorContains(t, err, "signature is invalid")
+}
+
 func testCosignerSecurity(t *testing.T, securities []CosignerSecurity) error {
 	var (
 		mockPub   = []byte("mock_pub")
 		mockShare = []byte("mock_share")
 	)
 
-	nonce, err := securities[0].EncryptAndSign(2, mockPub, mockShare)
+	nonce, err := securities[0].EncryptAndSign(2, mockPub, mockShare, nil)
 	require.NoError(t, err)
 
-	decryptedPub, decryptedShare, err := securities[1].DecryptAndVerify(1, nonce.PubKey, nonce.Share, nonce.Signature)
+	decryptedPub, decryptedShare, err := securities[1].DecryptAndVerify(
+		1, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
 	require.NoError(t, err)
 
 	require.Equal(t, mockPub, decryptedPub)
 	require.Equal(t, mockShare, decryptedShare)
 
-	_, _, err = securities[2].DecryptAndVerify(1, nonce.PubKey, nonce.Share, nonce.Signature)
+	_, _, err = securities[2].DecryptAndVerify(1, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
 
 	return err
 }
 
+// benchmarkCosignerSecurity benchmarks the nonce exchange from the first to the second cosigner.
+func benchmarkCosignerSecurity(b *testing.B, security1, security2 CosignerSecurity) {
+	var (
+		mockPub   = make([]byte, 32)
+		mockShare = make([]byte, 32)
+	)
+
+	b.Run("EncryptAndSign", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := security1.EncryptAndSign(2, mockPub, mockShare, nil); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	nonce, err := security1.EncryptAndSign(2, mockPub, mockShare, nil)
+	require.NoError(b, err)
+
+	b.Run("DecryptAndVerify", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_, _, err := security2.DecryptAndVerify(1, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkCosignerSecurityECIES(b *testing.B) {
+	keys, err := CreateCosignerECIESShards(2)
+	require.NoError(b, err)
+
+	benchmarkCosignerSecurity(b, NewCosignerSecurityECIES(keys[0]), NewCosignerSecurityECIES(keys[1]))
+}
+
 func TestConcurrentIterateCosignerECIES(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping in short mode")
@@ -115,12 +175,12 @@ func TestConcurrentIterateCosignerECIES(t *testing.T) {
 					security2 := security2
 					j := j
 					nestedEg.Go(func() error {
-						n, err := security.EncryptAndSign(j+1, []byte("mock_pub"), []byte("mock_share"))
+						n, err := security.EncryptAndSign(j+1, []byte("mock_pub"), []byte("mock_share"), nil)
 						if err != nil {
 							return err
 						}
 
-						_, _, err = security2.DecryptAndVerify(i+1, n.PubKey, n.Share, n.Signature)
+						_, _, err = security2.DecryptAndVerify(i+1, n.PubKey, n.Share, n.Commitments, n.Signature)
 						if err != nil {
 							return err
 						}
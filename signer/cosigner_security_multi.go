@@ -0,0 +1,270 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var _ CosignerSecurity = &CosignerSecurityMulti{}
+
+// CosignerSecurityMulti is an implementation of CosignerSecurity for a cosigner that holds the keys
+// of several security schemes, e.g. both RSA and ECIES keys while a cluster migrates from RSA to ECIES.
+//
+// The schemes of the peers are learned from their Ping responses. Nonces for a peer are encrypted with
+// the most preferred scheme that both cosigners support, or with our least preferred scheme until the
+// schemes of the peer are known. Nonces and requests from a peer are verified with any of our schemes.
+type CosignerSecurityMulti struct {
+	// securities are ordered by preference, most preferred first.
+	securities []CosignerSecurity
+
+	mu          sync.RWMutex
+	peerSchemes map[int][]string
+}
+
+// NewCosignerSecurityMulti creates a new CosignerSecurityMulti for the securities of our cosigner,
+// ordered by preference, most preferred first.
+func NewCosignerSecurityMulti(securities ...CosignerSecurity) (*CosignerSecurityMulti, error) {
+	if len(securities) == 0 {
+		return nil, errors.New("no cosigner security")
+	}
+	for _, security := range securities[1:] {
+		if security.GetID() != securities[0].GetID() {
+			return nil, fmt.Errorf("cosigner security schemes %v and %v are for different cosigners (%d, %d)",
+				securities[0].Schemes(), security.Schemes(), securities[0].GetID(), security.GetID())
+		}
+	}
+
+	return &CosignerSecurityMulti{
+		securities:  securities,
+		peerSchemes: make(map[int][]string),
+	}, nil
+}
+
+// GetID returns the ID of the cosigner.
+func (c *CosignerSecurityMulti) GetID() int {
+	return c.securities[0].GetID()
+}
+
+// Schemes returns the security schemes of the cosigner, most preferred first.
+func (c *CosignerSecurityMulti) Schemes() []string {
+	schemes := make([]string, 0, len(c.securities))
+	for _, security := range c.securities {
+		schemes = append(schemes, security.Schemes()...)
+	}
+	return schemes
+}
+
+// pingNonceSize is the size of the random nonce of a Ping request, which the advertised schemes are signed for.
+const pingNonceSize = 16
+
+// pingSchemesMessage is the message that the cosigner with the ID signs to advertise its security schemes
+// in response to a Ping request with the nonce, so that the schemes can not be altered or replayed.
+func pingSchemesMessage(id int, nonce []byte, schemes []string) []byte {
+	msg := fmt.Appendf(nil, "Ping\n%d\n", id)
+	msg = append(msg, nonce...)
+	for _, scheme := range schemes {
+		msg = append(msg, '\n')
+		msg = append(msg, scheme...)
+	}
+	return msg
+}
+
+// signPingSchemes signs the advertised security schemes of our cosigner with each of its schemes.
+func signPingSchemes(security CosignerSecurity, nonce []byte) ([][]byte, error) {
+	msg := pingSchemesMessage(security.GetID(), nonce, security.Schemes())
+	if multi, ok := security.(*CosignerSecurityMulti); ok {
+		signatures := make([][]byte, len(multi.securities))
+		for i, s := range multi.securities {
+			signature, err := s.Sign(msg)
+			if err != nil {
+				return nil, err
+			}
+			signatures[i] = signature
+		}
+		return signatures, nil
+	}
+	signature, err := security.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{signature}, nil
+}
+
+// SetPeerSchemes sets the security schemes that the cosigner with the ID advertised.
+// Cosigners that do not advertise their schemes are treated as if their schemes are unknown.
+// Use SetSignedPeerSchemes for schemes that were received over the network.
+func (c *CosignerSecurityMulti) SetPeerSchemes(id int, schemes []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(schemes) == 0 {
+		delete(c.peerSchemes, id)
+		return
+	}
+	c.peerSchemes[id] = schemes
+}
+
+// SetSignedPeerSchemes sets the security schemes that the cosigner with the ID advertised in response to a Ping
+// with the nonce, if any of the signatures of the schemes is valid. Otherwise the schemes could have been altered
+// to downgrade the scheme, so an error is returned and the schemes that were negotiated before are kept.
+// The negotiated schemes are also kept if the cosigner advertised no schemes, since they could have been removed
+// on the network just as well. Schemes that are negotiated are only replaced by schemes that the cosigner signed.
+func (c *CosignerSecurityMulti) SetSignedPeerSchemes(
+	id int,
+	nonce []byte,
+	schemes []string,
+	signatures [][]byte,
+) error {
+	if len(schemes) == 0 {
+		return nil
+	}
+
+	msg := pingSchemesMessage(id, nonce, schemes)
+	if len(signatures) == 0 || len(signatures) > cosignerAuthMaxSignatures {
+		return fmt.Errorf("security schemes %v of cosigner %d are not signed", schemes, id)
+	}
+	var errs []error
+	for _, signature := range signatures {
+		err := c.Verify(id, msg, signature)
+		if err == nil {
+			c.SetPeerSchemes(id, schemes)
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("invalid signature of security schemes %v of cosigner %d: %w", schemes, id, errors.Join(errs...))
+}
+
+// PeerScheme returns the security scheme that is negotiated with the cosigner with the ID.
+func (c *CosignerSecurityMulti) PeerScheme(id int) (string, error) {
+	security, err := c.peerSecurity(id)
+	if err != nil {
+		return "", err
+	}
+	return security.Schemes()[0], nil
+}
+
+// peerSecurity returns our most preferred security that the cosigner with the ID supports,
+// or our least preferred security if the schemes of the cosigner are not known yet.
+func (c *CosignerSecurityMulti) peerSecurity(id int) (CosignerSecurity, error) {
+	c.mu.RLock()
+	peerSchemes, ok := c.peerSchemes[id]
+	c.mu.RUnlock()
+
+	if !ok {
+		return c.securities[len(c.securities)-1], nil
+	}
+
+	for _, security := range c.securities {
+		for _, scheme := range peerSchemes {
+			if security.Schemes()[0] == scheme {
+				return security, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no common security scheme with cosigner %d, ours: %v, theirs: %v",
+		id, c.Schemes(), peerSchemes)
+}
+
+// verifiers returns our securities, with the one negotiated with the cosigner with the ID first.
+func (c *CosignerSecurityMulti) verifiers(id int) []CosignerSecurity {
+	preferred, err := c.peerSecurity(id)
+	if err != nil {
+		return c.securities
+	}
+
+	verifiers := make([]CosignerSecurity, 0, len(c.securities))
+	verifiers = append(verifiers, preferred)
+	for _, security := range c.securities {
+		if security != preferred {
+			verifiers = append(verifiers, security)
+		}
+	}
+	return verifiers
+}
+
+// EncryptAndSign encrypts the nonce and signs it for authentication,
+// with the security scheme that is negotiated with the destination cosigner.
+func (c *CosignerSecurityMulti) EncryptAndSign(
+	id int,
+	noncePub []byte,
+	nonceShare []byte,
+	commitments [][]byte,
+) (CosignerNonce, error) {
+	security, err := c.peerSecurity(id)
+	if err != nil {
+		return CosignerNonce{SourceID: c.GetID()}, err
+	}
+	return security.EncryptAndSign(id, noncePub, nonceShare, commitments)
+}
+
+// DecryptAndVerify decrypts the nonce and verifies the signature to authenticate the source cosigner,
+// with the first of our security schemes that the signature is valid for.
+func (c *CosignerSecurityMulti) DecryptAndVerify(
+	id int,
+	encryptedNoncePub []byte,
+	encryptedNonceShare []byte,
+	commitments [][]byte,
+	signature []byte,
+) ([]byte, []byte, error) {
+	var errs []error
+	for _, security := range c.verifiers(id) {
+		noncePub, nonceShare, err := security.DecryptAndVerify(
+			id, encryptedNoncePub, encryptedNonceShare, commitments, signature)
+		if err == nil {
+			return noncePub, nonceShare, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", security.Schemes()[0], err))
+	}
+	return nil, nil, errors.Join(errs...)
+}
+
+// Sign signs the message with our most preferred security scheme.
+// Use SignFor to sign for a cosigner whose security schemes may differ from ours.
+func (c *CosignerSecurityMulti) Sign(msg []byte) ([]byte, error) {
+	return c.securities[0].Sign(msg)
+}
+
+// SignFor signs the message for the cosigner with the ID. The message is signed with the security scheme
+// that is negotiated with the cosigner, or with all of our schemes if the schemes of the cosigner are not
+// known, so that the cosigner can verify at least one of the signatures.
+func (c *CosignerSecurityMulti) SignFor(id int, msg []byte) ([][]byte, error) {
+	c.mu.RLock()
+	_, known := c.peerSchemes[id]
+	c.mu.RUnlock()
+
+	securities := c.securities
+	if known {
+		security, err := c.peerSecurity(id)
+		if err != nil {
+			return nil, err
+		}
+		securities = []CosignerSecurity{security}
+	}
+
+	signatures := make([][]byte, len(securities))
+	for i, security := range securities {
+		signature, err := security.Sign(msg)
+		if err != nil {
+			return nil, err
+		}
+		signatures[i] = signature
+	}
+	return signatures, nil
+}
+
+// Verify verifies the signature of the message from the cosigner with the ID,
+// with the first of our security schemes that the signature is valid for.
+func (c *CosignerSecurityMulti) Verify(id int, msg []byte, signature []byte) error {
+	var errs []error
+	for _, security := range c.verifiers(id) {
+		err := security.Verify(id, msg, signature)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", security.Schemes()[0], err))
+	}
+	return errors.Join(errs...)
+}
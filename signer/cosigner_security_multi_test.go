@@ -0,0 +1,140 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCosignerSecurityMulti(t *testing.T) {
+	eciesKeys, err := CreateCosignerECIESShards(3)
+	require.NoError(t, err)
+
+	rsaKeys, err := CreateCosignerRSAShards(3)
+	require.NoError(t, err)
+
+	// cosigners 1 and 3 migrated to ECIES, cosigner 2 still only has its RSA key
+	security1, err := NewCosignerSecurityMulti(NewCosignerSecurityECIES(eciesKeys[0]), NewCosignerSecurityRSA(rsaKeys[0]))
+	require.NoError(t, err)
+	security2 := NewCosignerSecurityRSA(rsaKeys[1])
+	security3, err := NewCosignerSecurityMulti(NewCosignerSecurityECIES(eciesKeys[2]), NewCosignerSecurityRSA(rsaKeys[2]))
+	require.NoError(t, err)
+
+	require.Equal(t, 1, security1.GetID())
+	require.Equal(t, []string{CosignerSecuritySchemeECIES, CosignerSecuritySchemeRSA}, security1.Schemes())
+
+	var (
+		mockPub   = []byte("mock_pub")
+		mockShare = []byte("mock_share")
+	)
+
+	// the least preferred scheme is used until the schemes of a peer are known
+	scheme, err := security1.PeerScheme(3)
+	require.NoError(t, err)
+	require.Equal(t, CosignerSecuritySchemeRSA, scheme)
+
+	nonce, err := security1.EncryptAndSign(2, mockPub, mockShare, nil)
+	require.NoError(t, err)
+	pub, share, err := security2.DecryptAndVerify(1, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
+	require.NoError(t, err)
+	require.Equal(t, mockPub, pub)
+	require.Equal(t, mockShare, share)
+
+	// the most preferred common scheme is used once the schemes of a peer are known
+	security1.SetPeerSchemes(3, security3.Schemes())
+	scheme, err = security1.PeerScheme(3)
+	require.NoError(t, err)
+	require.Equal(t, CosignerSecuritySchemeECIES, scheme)
+
+	nonce, err = security1.EncryptAndSign(3, mockPub, mockShare, nil)
+	require.NoError(t, err)
+	_, _, err = NewCosignerSecurityECIES(eciesKeys[2]).DecryptAndVerify(
+		1, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
+	require.NoError(t, err)
+	pub, share, err = security3.DecryptAndVerify(1, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
+	require.NoError(t, err)
+	require.Equal(t, mockPub, pub)
+	require.Equal(t, mockShare, share)
+
+	// nonces of a cosigner that did not migrate are still accepted
+	nonce, err = security2.EncryptAndSign(3, mockPub, mockShare, nil)
+	require.NoError(t, err)
+	_, _, err = security3.DecryptAndVerify(2, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
+	require.NoError(t, err)
+
+	// but not from another cosigner
+	_, _, err = security3.DecryptAndVerify(1, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
+	require.Error(t, err)
+
+	security1.SetPeerSchemes(2, []string{CosignerSecuritySchemeX25519})
+	_, err = security1.EncryptAndSign(2, mockPub, mockShare, nil)
+	require.ErrorContains(t, err, "no common security scheme with cosigner 2")
+
+	// cosigners that do not advertise their schemes are unknown
+	security1.SetPeerSchemes(2, nil)
+	scheme, err = security1.PeerScheme(2)
+	require.NoError(t, err)
+	require.Equal(t, CosignerSecuritySchemeRSA, scheme)
+
+	// requests are signed with all schemes until the schemes of a peer are known
+	signatures, err := security1.SignFor(2, []byte("msg"))
+	require.NoError(t, err)
+	require.Len(t, signatures, 2)
+	require.NoError(t, security2.Verify(1, []byte("msg"), signatures[1]))
+	require.NoError(t, security3.Verify(1, []byte("msg"), signatures[0]))
+	require.NoError(t, security3.Verify(1, []byte("msg"), signatures[1]))
+
+	signatures, err = security1.SignFor(3, []byte("msg"))
+	require.NoError(t, err)
+	require.Len(t, signatures, 1)
+	require.NoError(t, security3.Verify(1, []byte("msg"), signatures[0]))
+
+	_, err = NewCosignerSecurityMulti(NewCosignerSecurityECIES(eciesKeys[0]), NewCosignerSecurityRSA(rsaKeys[1]))
+	require.Error(t, err)
+}
+
+func TestCosignerSecurityMultiSignedPeerSchemes(t *testing.T) {
+	eciesKeys, err := CreateCosignerECIESShards(2)
+	require.NoError(t, err)
+
+	rsaKeys, err := CreateCosignerRSAShards(2)
+	require.NoError(t, err)
+
+	security1, err := NewCosignerSecurityMulti(NewCosignerSecurityECIES(eciesKeys[0]), NewCosignerSecurityRSA(rsaKeys[0]))
+	require.NoError(t, err)
+	security2, err := NewCosignerSecurityMulti(NewCosignerSecurityECIES(eciesKeys[1]), NewCosignerSecurityRSA(rsaKeys[1]))
+	require.NoError(t, err)
+
+	nonce := []byte("0123456789abcdef")
+	signatures, err := signPingSchemes(security2, nonce)
+	require.NoError(t, err)
+	require.Len(t, signatures, 2)
+
+	// the schemes signed by the peer are negotiated
+	require.NoError(t, security1.SetSignedPeerSchemes(2, nonce, security2.Schemes(), signatures))
+	scheme, err := security1.PeerScheme(2)
+	require.NoError(t, err)
+	require.Equal(t, CosignerSecuritySchemeECIES, scheme)
+
+	// schemes that were altered to downgrade the scheme are not negotiated
+	downgraded := []string{CosignerSecuritySchemeRSA}
+	require.Error(t, security1.SetSignedPeerSchemes(2, nonce, downgraded, signatures))
+	require.Error(t, security1.SetSignedPeerSchemes(2, nonce, downgraded, nil))
+
+	// nor are schemes replayed from a previous Ping
+	require.Error(t, security1.SetSignedPeerSchemes(2, []byte("fedcba9876543210"), security2.Schemes(), signatures))
+
+	// the negotiated scheme is kept, so requests are still only signed with it
+	scheme, err = security1.PeerScheme(2)
+	require.NoError(t, err)
+	require.Equal(t, CosignerSecuritySchemeECIES, scheme)
+	signatures, err = security1.SignFor(2, []byte("msg"))
+	require.NoError(t, err)
+	require.Len(t, signatures, 1)
+
+	// as it is if the peer advertises no schemes
+	require.NoError(t, security1.SetSignedPeerSchemes(2, nonce, nil, nil))
+	scheme, err = security1.PeerScheme(2)
+	require.NoError(t, err)
+	require.Equal(t, CosignerSecuritySchemeECIES, scheme)
+}
@@ -8,9 +8,7 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
-	"os"
 
-	cometjson "github.com/cometbft/cometbft/libs/json"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -92,7 +90,7 @@ func (key *CosignerRSAKey) UnmarshalJSON(data []byte) error {
 // LoadCosignerRSAKey loads a CosignerRSAKey from file.
 func LoadCosignerRSAKey(file string) (CosignerRSAKey, error) {
 	pvKey := CosignerRSAKey{}
-	keyJSONBytes, err := os.ReadFile(file)
+	keyJSONBytes, err := readKeyFile(file)
 	if err != nil {
 		return pvKey, err
 	}
@@ -127,8 +125,18 @@ func (c *CosignerSecurityRSA) GetID() int {
 	return c.key.ID
 }
 
+// Schemes returns the security scheme of the cosigner.
+func (c *CosignerSecurityRSA) Schemes() []string {
+	return []string{CosignerSecuritySchemeRSA}
+}
+
 // EncryptAndSign encrypts the nonce and signs it for authentication.
-func (c *CosignerSecurityRSA) EncryptAndSign(id int, noncePub []byte, nonceShare []byte) (CosignerNonce, error) {
+func (c *CosignerSecurityRSA) EncryptAndSign(
+	id int,
+	noncePub []byte,
+	nonceShare []byte,
+	commitments [][]byte,
+) (CosignerNonce, error) {
 	nonce := CosignerNonce{
 		SourceID: c.key.ID,
 	}
@@ -163,7 +171,7 @@ func (c *CosignerSecurityRSA) EncryptAndSign(id int, noncePub []byte, nonceShare
 	// sign the response payload with our private key
 	// cosigners can verify the signature to confirm sender validity
 
-	jsonBytes, err := cometjson.Marshal(nonce)
+	jsonBytes, err := nonceDigestMessage(nonce.SourceID, nonce.PubKey, nonce.Share, commitments)
 
 	if err != nil {
 		return nonce, err
@@ -176,6 +184,7 @@ func (c *CosignerSecurityRSA) EncryptAndSign(id int, noncePub []byte, nonceShare
 	}
 
 	nonce.DestinationID = id
+	nonce.Commitments = commitments
 	nonce.Signature = signature
 
 	return nonce, nil
@@ -187,6 +196,7 @@ func (c *CosignerSecurityRSA) DecryptAndVerify(
 	id int,
 	encryptedNoncePub []byte,
 	encryptedNonceShare []byte,
+	commitments [][]byte,
 	signature []byte,
 ) ([]byte, []byte, error) {
 	pubKey, ok := c.rsaPubKeys[id]
@@ -194,13 +204,7 @@ func (c *CosignerSecurityRSA) DecryptAndVerify(
 		return nil, nil, fmt.Errorf("unknown cosigner: %d", id)
 	}
 
-	digestMsg := CosignerNonce{
-		SourceID: id,
-		PubKey:   encryptedNoncePub,
-		Share:    encryptedNonceShare,
-	}
-
-	digestBytes, err := cometjson.Marshal(digestMsg)
+	digestBytes, err := nonceDigestMessage(id, encryptedNoncePub, encryptedNonceShare, commitments)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -233,3 +237,20 @@ func (c *CosignerSecurityRSA) DecryptAndVerify(
 
 	return noncePub, nonceShare, nil
 }
+
+// Sign signs the SHA-256 hash of the message with our RSA key.
+func (c *CosignerSecurityRSA) Sign(msg []byte) ([]byte, error) {
+	hash := sha256.Sum256(msg)
+	return rsa.SignPSS(rand.Reader, &c.key.RSAKey, crypto.SHA256, hash[:], nil)
+}
+
+// Verify verifies the RSA signature of the message from the cosigner with the ID.
+func (c *CosignerSecurityRSA) Verify(id int, msg []byte, signature []byte) error {
+	pubKey, ok := c.rsaPubKeys[id]
+	if !ok {
+		return fmt.Errorf("unknown cosigner: %d", id)
+	}
+
+	hash := sha256.Sum256(msg)
+	return rsa.VerifyPSS(&pubKey.PublicKey, crypto.SHA256, hash[:], signature, nil)
+}
@@ -91,12 +91,12 @@ func TestConcurrentIterateCosignerRSA(t *testing.T) {
 					security2 := security2
 					j := j
 					nestedEg.Go(func() error {
-						n, err := security.EncryptAndSign(j+1, []byte("mock_pub"), []byte("mock_share"))
+						n, err := security.EncryptAndSign(j+1, []byte("mock_pub"), []byte("mock_share"), nil)
 						if err != nil {
 							return err
 						}
 
-						_, _, err = security2.DecryptAndVerify(i+1, n.PubKey, n.Share, n.Signature)
+						_, _, err = security2.DecryptAndVerify(i+1, n.PubKey, n.Share, n.Commitments, n.Signature)
 						if err != nil {
 							return err
 						}
@@ -0,0 +1,315 @@
+package signer
+
+import (
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+var _ CosignerSecurity = &CosignerSecurityX25519{}
+
+// x25519KDFInfo binds the keys derived from an X25519 shared secret to their use by horcrux.
+var x25519KDFInfo = []byte("horcrux x25519 chacha20poly1305 v1")
+
+// CosignerSecurityX25519 is an implementation of CosignerSecurity
+// using X25519 key agreement with ChaCha20-Poly1305 for encryption and Ed25519 for digital signature.
+// It only depends on standard library crypto and golang.org/x/crypto.
+type CosignerSecurityX25519 struct {
+	key     CosignerX25519Key
+	pubKeys map[int]CosignerX25519PubKey
+}
+
+// CosignerX25519PubKey is a cosigner's X25519 and Ed25519 public keys.
+type CosignerX25519PubKey struct {
+	ID         int
+	X25519Key  *ecdh.PublicKey
+	Ed25519Key ed25519.PublicKey
+}
+
+// CosignerX25519Key is an X25519 and Ed25519 key for an m-of-n threshold signer,
+// composed of our private keys and the public keys of all n cosigners.
+type CosignerX25519Key struct {
+	X25519Key   *ecdh.PrivateKey    `json:"x25519Key"`
+	Ed25519Key  ed25519.PrivateKey  `json:"ed25519Key"`
+	ID          int                 `json:"id"`
+	X25519Pubs  []*ecdh.PublicKey   `json:"x25519Pubs"`
+	Ed25519Pubs []ed25519.PublicKey `json:"ed25519Pubs"`
+}
+
+func (key *CosignerX25519Key) MarshalJSON() ([]byte, error) {
+	type Alias CosignerX25519Key
+
+	// marshal our private key and all public keys
+	pubKeysBytes := make([][]byte, len(key.X25519Pubs))
+	for i, pubKey := range key.X25519Pubs {
+		pubKeysBytes[i] = pubKey.Bytes()
+	}
+
+	return json.Marshal(&struct {
+		X25519Key  []byte   `json:"x25519Key"`
+		X25519Pubs [][]byte `json:"x25519Pubs"`
+		*Alias
+	}{
+		X25519Key:  key.X25519Key.Bytes(),
+		X25519Pubs: pubKeysBytes,
+		Alias:      (*Alias)(key),
+	})
+}
+
+func (key *CosignerX25519Key) UnmarshalJSON(data []byte) error {
+	type Alias CosignerX25519Key
+
+	aux := &struct {
+		X25519Key  []byte   `json:"x25519Key"`
+		X25519Pubs [][]byte `json:"x25519Pubs"`
+		*Alias
+	}{
+		Alias: (*Alias)(key),
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	// unmarshal the public key bytes for each cosigner
+	key.X25519Pubs = make([]*ecdh.PublicKey, len(aux.X25519Pubs))
+	for i, bytes := range aux.X25519Pubs {
+		pub, err := ecdh.X25519().NewPublicKey(bytes)
+		if err != nil {
+			return fmt.Errorf("invalid x25519 public key of cosigner %d: %w", i+1, err)
+		}
+		key.X25519Pubs[i] = pub
+	}
+
+	privKey, err := ecdh.X25519().NewPrivateKey(aux.X25519Key)
+	if err != nil {
+		return fmt.Errorf("invalid x25519 private key: %w", err)
+	}
+	key.X25519Key = privKey
+
+	if len(key.Ed25519Key) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid ed25519 private key size: %d", len(key.Ed25519Key))
+	}
+	if len(key.Ed25519Pubs) != len(key.X25519Pubs) {
+		return fmt.Errorf("expected %d ed25519 public keys, got %d", len(key.X25519Pubs), len(key.Ed25519Pubs))
+	}
+	for i, pub := range key.Ed25519Pubs {
+		if len(pub) != ed25519.PublicKeySize {
+			return fmt.Errorf("invalid ed25519 public key size of cosigner %d: %d", i+1, len(pub))
+		}
+	}
+
+	return nil
+}
+
+// LoadCosignerX25519Key loads a CosignerX25519Key from file.
+func LoadCosignerX25519Key(file string) (CosignerX25519Key, error) {
+	pvKey := CosignerX25519Key{}
+	keyJSONBytes, err := readKeyFile(file)
+	if err != nil {
+		return pvKey, err
+	}
+
+	err = json.Unmarshal(keyJSONBytes, &pvKey)
+	if err != nil {
+		return pvKey, err
+	}
+
+	return pvKey, nil
+}
+
+// NewCosignerSecurityX25519 creates a new CosignerSecurityX25519.
+func NewCosignerSecurityX25519(key CosignerX25519Key) *CosignerSecurityX25519 {
+	c := &CosignerSecurityX25519{
+		key:     key,
+		pubKeys: make(map[int]CosignerX25519PubKey, len(key.X25519Pubs)),
+	}
+
+	for i, pubKey := range key.X25519Pubs {
+		c.pubKeys[i+1] = CosignerX25519PubKey{
+			ID:         i + 1,
+			X25519Key:  pubKey,
+			Ed25519Key: key.Ed25519Pubs[i],
+		}
+	}
+
+	return c
+}
+
+// GetID returns the ID of the cosigner.
+func (c *CosignerSecurityX25519) GetID() int {
+	return c.key.ID
+}
+
+// Schemes returns the security scheme of the cosigner.
+func (c *CosignerSecurityX25519) Schemes() []string {
+	return []string{CosignerSecuritySchemeX25519}
+}
+
+// x25519AEAD derives the ChaCha20-Poly1305 key of a message from the X25519 shared secret
+// of the ephemeral key of the message and the key of the recipient.
+func x25519AEAD(sharedSecret, ephemeralPub, recipientPub []byte) (cipher.AEAD, error) {
+	salt := make([]byte, 0, len(ephemeralPub)+len(recipientPub))
+	salt = append(salt, ephemeralPub...)
+	salt = append(salt, recipientPub...)
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, sharedSecret, salt, x25519KDFInfo), key); err != nil {
+		return nil, err
+	}
+
+	return chacha20poly1305.New(key)
+}
+
+// x25519Encrypt encrypts the plaintext for the X25519 public key of the recipient.
+// Every message is encrypted with a new ephemeral key, so the derived key is only used once
+// and the AEAD nonce can be zero. The ciphertext is prefixed with the ephemeral public key.
+func x25519Encrypt(recipient *ecdh.PublicKey, plaintext []byte) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedSecret, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralPub := ephemeral.PublicKey().Bytes()
+	aead, err := x25519AEAD(sharedSecret, ephemeralPub, recipient.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(ephemeralPub, make([]byte, aead.NonceSize()), plaintext, nil), nil
+}
+
+// x25519Decrypt decrypts a ciphertext of x25519Encrypt with our X25519 private key.
+func x25519Decrypt(key *ecdh.PrivateKey, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 32+chacha20poly1305.Overhead {
+		return nil, fmt.Errorf("x25519: ciphertext is too short")
+	}
+
+	ephemeralPub, err := ecdh.X25519().NewPublicKey(ciphertext[:32])
+	if err != nil {
+		return nil, err
+	}
+
+	sharedSecret, err := key.ECDH(ephemeralPub)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := x25519AEAD(sharedSecret, ciphertext[:32], key.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext[32:], nil)
+}
+
+// EncryptAndSign encrypts the nonce and signs it for authentication.
+func (c *CosignerSecurityX25519) EncryptAndSign(
+	id int,
+	noncePub []byte,
+	nonceShare []byte,
+	commitments [][]byte,
+) (CosignerNonce, error) {
+	nonce := CosignerNonce{
+		SourceID: c.key.ID,
+	}
+
+	// grab the cosigner info for the ID being requested
+	pubKey, ok := c.pubKeys[id]
+	if !ok {
+		return nonce, fmt.Errorf("unknown cosigner ID: %d", id)
+	}
+
+	encryptedPub, err := x25519Encrypt(pubKey.X25519Key, noncePub)
+	if err != nil {
+		return nonce, err
+	}
+
+	encryptedShare, err := x25519Encrypt(pubKey.X25519Key, nonceShare)
+	if err != nil {
+		return nonce, err
+	}
+
+	nonce.PubKey = encryptedPub
+	nonce.Share = encryptedShare
+
+	// sign the response payload with our private key
+	// cosigners can verify the signature to confirm sender validity
+	jsonBytes, err := nonceDigestMessage(nonce.SourceID, nonce.PubKey, nonce.Share, commitments)
+	if err != nil {
+		return nonce, err
+	}
+
+	nonce.DestinationID = id
+	nonce.Commitments = commitments
+	nonce.Signature = ed25519.Sign(c.key.Ed25519Key, jsonBytes)
+
+	return nonce, nil
+}
+
+// DecryptAndVerify decrypts the nonce and verifies
+// the signature to authenticate the source cosigner.
+func (c *CosignerSecurityX25519) DecryptAndVerify(
+	id int,
+	encryptedNoncePub []byte,
+	encryptedNonceShare []byte,
+	commitments [][]byte,
+	signature []byte,
+) ([]byte, []byte, error) {
+	pubKey, ok := c.pubKeys[id]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown cosigner: %d", id)
+	}
+
+	digestBytes, err := nonceDigestMessage(id, encryptedNoncePub, encryptedNonceShare, commitments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !ed25519.Verify(pubKey.Ed25519Key, digestBytes, signature) {
+		return nil, nil, fmt.Errorf("signature is invalid")
+	}
+
+	noncePub, err := x25519Decrypt(c.key.X25519Key, encryptedNoncePub)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt nonce pub: %w", err)
+	}
+
+	nonceShare, err := x25519Decrypt(c.key.X25519Key, encryptedNonceShare)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt nonce share: %w", err)
+	}
+
+	return noncePub, nonceShare, nil
+}
+
+// Sign signs the message with our Ed25519 key.
+func (c *CosignerSecurityX25519) Sign(msg []byte) ([]byte, error) {
+	return ed25519.Sign(c.key.Ed25519Key, msg), nil
+}
+
+// Verify verifies the Ed25519 signature of the message from the cosigner with the ID.
+func (c *CosignerSecurityX25519) Verify(id int, msg []byte, signature []byte) error {
+	pubKey, ok := c.pubKeys[id]
+	if !ok {
+		return fmt.Errorf("unknown cosigner: %d", id)
+	}
+
+	if !ed25519.Verify(pubKey.Ed25519Key, msg, signature) {
+		return fmt.Errorf("signature is invalid")
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package signer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCosignerX25519(t *testing.T) {
+	t.Parallel()
+
+	keys, err := CreateCosignerX25519Shards(3)
+	require.NoError(t, err)
+
+	securities := make([]CosignerSecurity, 3)
+
+	for i, key := range keys {
+		securities[i] = NewCosignerSecurityX25519(key)
+
+		bz, err := json.Marshal(&key)
+		require.NoError(t, err)
+
+		var key2 CosignerX25519Key
+		require.NoError(t, json.Unmarshal(bz, &key2))
+
+		require.Equal(t, key.ID, key2.ID)
+		require.True(t, key.X25519Key.Equal(key2.X25519Key))
+		require.Equal(t, key.Ed25519Key, key2.Ed25519Key)
+		require.Equal(t, key.Ed25519Pubs, key2.Ed25519Pubs)
+		for i := range key.X25519Pubs {
+			require.True(t, key.X25519Pubs[i].Equal(key2.X25519Pubs[i]))
+		}
+	}
+
+	err = testCosignerSecurity(t, securities)
+	require.ErrorContains(t, err, "message authentication failed")
+	require.ErrorContains(t, err, "failed to decrypt")
+}
+
+func TestCosignerX25519Tampered(t *testing.T) {
+	keys, err := CreateCosignerX25519Shards(2)
+	require.NoError(t, err)
+
+	security1 := NewCosignerSecurityX25519(keys[0])
+	security2 := NewCosignerSecurityX25519(keys[1])
+
+	nonce, err := security1.EncryptAndSign(2, []byte("mock_pub"), []byte("mock_share"), nil)
+	require.NoError(t, err)
+
+	nonce.Share[len(nonce.Share)-1] ^= 0x01
+	_, _, err = security2.DecryptAndVerify(1, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
+	require.ErrorContains(t, err, "signature is invalid")
+
+	// a valid signature of the source cosigner over the nonce of another cosigner is rejected
+	_, _, err = security2.DecryptAndVerify(2, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
+	require.ErrorContains(t, err, "signature is invalid")
+
+	sig, err := security1.Sign([]byte("msg"))
+	require.NoError(t, err)
+	require.NoError(t, security2.Verify(1, []byte("msg"), sig))
+	require.Error(t, security2.Verify(2, []byte("msg"), sig))
+	require.Error(t, security2.Verify(1, []byte("other msg"), sig))
+}
+
+func BenchmarkCosignerSecurityX25519(b *testing.B) {
+	keys, err := CreateCosignerX25519Shards(2)
+	require.NoError(b, err)
+
+	benchmarkCosignerSecurity(b, NewCosignerSecurityX25519(keys[0]), NewCosignerSecurityX25519(keys[1]))
+}
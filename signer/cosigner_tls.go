@@ -0,0 +1,368 @@
+package signer
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	// TLSModeDisabled sends all cosigner p2p traffic in cleartext. This is the default.
+	TLSModeDisabled = "disabled"
+
+	// TLSModePermissive accepts both mutual TLS and cleartext connections, but dials cleartext.
+	TLSModePermissive = "permissive"
+
+	// TLSModeEnabled accepts both mutual TLS and cleartext connections, and dials mutual TLS.
+	TLSModeEnabled = "enabled"
+
+	// TLSModeStrict only accepts and dials mutual TLS connections.
+	TLSModeStrict = "strict"
+)
+
+const (
+	CosignerTLSCertFile = "tls_cert.pem"
+	CosignerTLSKeyFile  = "tls_key.pem"
+	CosignerTLSCAFile   = "tls_ca.pem"
+
+	cosignerTLSCommonNamePrefix = "cosigner-"
+	cosignerTLSValidity         = 10 * 365 * 24 * time.Hour
+
+	// tlsRecordTypeHandshake is the first byte of a TLS client hello.
+	tlsRecordTypeHandshake = 0x16
+)
+
+// CosignerTLSKey is the TLS certificate and key of a cosigner, and the certificate of the CA
+// that issued the certificates of all cosigners, all PEM encoded.
+// The shard ID of the cosigner is the common name of its certificate.
+type CosignerTLSKey struct {
+	ID   int
+	Cert []byte
+	Key  []byte
+	CA   []byte
+}
+
+// CreateCosignerTLSCerts creates a CA and issues a TLS certificate for each of the cosigners.
+// The private key of the CA is discarded, so the certificates of all cosigners
+// must be created again to add a cosigner.
+func CreateCosignerTLSCerts(shards int) ([]CosignerTLSKey, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	notBefore := time.Now().Add(-time.Hour)
+	notAfter := notBefore.Add(cosignerTLSValidity)
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "horcrux cosigner CA"},
+		NotBefore:             notBefore,
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, err
+	}
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+
+	out := make([]CosignerTLSKey, shards)
+	for i := range out {
+		id := i + 1
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(int64(id + 1)),
+			Subject:      pkix.Name{CommonName: cosignerTLSCommonName(id)},
+			DNSNames:     []string{cosignerTLSCommonName(id)},
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		}
+		certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		if err != nil {
+			return nil, err
+		}
+		keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		out[i] = CosignerTLSKey{
+			ID:   id,
+			Cert: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+			Key:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+			CA:   caPEM,
+		}
+	}
+
+	return out, nil
+}
+
+// WriteCosignerTLSFiles writes the TLS certificate, key and CA certificate of a cosigner to the directory.
+func WriteCosignerTLSFiles(cosigner CosignerTLSKey, dir string) error {
+	if err := os.WriteFile(filepath.Join(dir, CosignerTLSCertFile), cosigner.Cert, 0600); err != nil {
+		return err
+	}
+	if err := writeKeyFile(filepath.Join(dir, CosignerTLSKeyFile), cosigner.Key); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, CosignerTLSCAFile), cosigner.CA, 0600)
+}
+
+func cosignerTLSCommonName(id int) string {
+	return cosignerTLSCommonNamePrefix + strconv.Itoa(id)
+}
+
+// cosignerTLSShardID returns the shard ID of the cosigner that the certificate was issued to.
+func cosignerTLSShardID(cert *x509.Certificate) (int, error) {
+	id, ok := strings.CutPrefix(cert.Subject.CommonName, cosignerTLSCommonNamePrefix)
+	if !ok {
+		return 0, fmt.Errorf("certificate %q is not a cosigner certificate", cert.Subject.CommonName)
+	}
+	shardID, err := strconv.Atoi(id)
+	if err != nil || shardID < 1 {
+		return 0, fmt.Errorf("certificate %q is not a cosigner certificate", cert.Subject.CommonName)
+	}
+	return shardID, nil
+}
+
+// CosignerTLSInfo is the AuthInfo of a mutual TLS connection with a cosigner.
+type CosignerTLSInfo struct {
+	credentials.TLSInfo
+
+	// ShardID is the shard ID of the cosigner on the other end of the connection.
+	ShardID int
+}
+
+// cleartextInfo is the AuthInfo of a cleartext connection accepted in permissive and enabled modes.
+type cleartextInfo struct {
+	credentials.CommonAuthInfo
+}
+
+func (cleartextInfo) AuthType() string {
+	return "insecure"
+}
+
+var _ credentials.TransportCredentials = &cosignerTLSCredentials{}
+
+// cosignerTLSCredentials are the gRPC transport credentials of the p2p connections between cosigners.
+// Certificates are verified against the cosigner CA, and the shard ID of the certificate
+// of the other end must be a cosigner shard ID, or the shard ID of the cosigner that was dialed.
+type cosignerTLSCredentials struct {
+	mode string
+	tls  credentials.TransportCredentials
+
+	// peers maps the p2p host:port of the cosigners to their shard IDs.
+	peers map[string]int
+	ids   map[int]struct{}
+}
+
+// NewCosignerTLSCredentials returns the gRPC transport credentials of the p2p connections between cosigners
+// for the TLS mode, with the PEM encoded certificate and key of this cosigner and the certificate of the cosigner CA.
+func NewCosignerTLSCredentials(
+	mode string,
+	certPEM, keyPEM, caPEM []byte,
+	cosigners CosignersConfig,
+) (credentials.TransportCredentials, error) {
+	if mode == "" || mode == TLSModeDisabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cosigner TLS certificate: %w", err)
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("invalid cosigner TLS CA certificate")
+	}
+
+	c := &cosignerTLSCredentials{
+		mode:  mode,
+		peers: make(map[string]int, len(cosigners)),
+		ids:   make(map[int]struct{}, len(cosigners)),
+	}
+	for _, cosigner := range cosigners {
+		c.peers[p2pURLToRaftAddress(cosigner.P2PAddr)] = cosigner.ShardID
+		c.ids[cosigner.ShardID] = struct{}{}
+	}
+
+	c.tls = credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS13,
+		ClientAuth:   tls.RequireAnyClientCert,
+		// cosigners are identified by shard ID rather than host name,
+		// so the certificate chain is verified in verifyPeerCertificate instead.
+		InsecureSkipVerify: true, //nolint:gosec
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyCosignerTLSCertificate(roots, rawCerts)
+		},
+	})
+
+	return c, nil
+}
+
+// verifyCosignerTLSCertificate verifies that the certificate chain was issued by the cosigner CA.
+func verifyCosignerTLSCertificate(roots *x509.CertPool, rawCerts [][]byte) error {
+	if len(rawCerts) == 0 {
+		return errors.New("no cosigner certificate")
+	}
+	certs := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs[i] = cert
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
+
+// authorize returns the AuthInfo of the mutual TLS connection if the shard ID of the certificate of the other end
+// is the expected shard ID, or any cosigner shard ID if expected is zero. Only the server accepts any cosigner.
+func (c *cosignerTLSCredentials) authorize(authInfo credentials.AuthInfo, expected int) (CosignerTLSInfo, error) {
+	info, ok := authInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return CosignerTLSInfo{}, errors.New("no cosigner certificate")
+	}
+	shardID, err := cosignerTLSShardID(info.State.PeerCertificates[0])
+	if err != nil {
+		return CosignerTLSInfo{}, err
+	}
+	if expected != 0 && shardID != expected {
+		return CosignerTLSInfo{}, fmt.Errorf("expected certificate of cosigner %d, got cosigner %d", expected, shardID)
+	}
+	if _, ok := c.ids[shardID]; !ok {
+		return CosignerTLSInfo{}, fmt.Errorf("cosigner %d is not a configured cosigner", shardID)
+	}
+	return CosignerTLSInfo{TLSInfo: info, ShardID: shardID}, nil
+}
+
+// ClientHandshake dials cleartext in permissive mode, and mutual TLS otherwise.
+func (c *cosignerTLSCredentials) ClientHandshake(
+	ctx context.Context,
+	authority string,
+	rawConn net.Conn,
+) (net.Conn, credentials.AuthInfo, error) {
+	if c.mode == TLSModePermissive {
+		return rawConn, cleartextInfo{credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}}, nil
+	}
+
+	conn, authInfo, err := c.tls.ClientHandshake(ctx, authority, rawConn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the authority is the address of the dialed cosigner. An address that is not configured for
+	// a cosigner is refused, so that the certificate of any cosigner is never accepted for it.
+	expected, ok := c.peers[authority]
+	if !ok {
+		conn.Close()
+		return nil, nil, fmt.Errorf("no cosigner is configured for address %s", authority)
+	}
+
+	info, err := c.authorize(authInfo, expected)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, info, nil
+}
+
+// ServerHandshake only accepts mutual TLS in strict mode, and both mutual TLS and cleartext otherwise.
+func (c *cosignerTLSCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if c.mode != TLSModeStrict {
+		conn := &peekedConn{Conn: rawConn, r: bufio.NewReader(rawConn)}
+		first, err := conn.r.Peek(1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if first[0] != tlsRecordTypeHandshake {
+			return conn, cleartextInfo{credentials.CommonAuthInfo{SecurityLevel: credentials.NoSecurity}}, nil
+		}
+		rawConn = conn
+	}
+
+	conn, authInfo, err := c.tls.ServerHandshake(rawConn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := c.authorize(authInfo, 0)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	return conn, info, nil
+}
+
+func (c *cosignerTLSCredentials) Info() credentials.ProtocolInfo {
+	return c.tls.Info()
+}
+
+func (c *cosignerTLSCredentials) Clone() credentials.TransportCredentials {
+	return &cosignerTLSCredentials{
+		mode:  c.mode,
+		tls:   c.tls.Clone(),
+		peers: c.peers,
+		ids:   c.ids,
+	}
+}
+
+// OverrideServerName is a no-op, cosigners are identified by shard ID rather than server name.
+func (c *cosignerTLSCredentials) OverrideServerName(string) error {
+	return nil
+}
+
+// peekedConn is a net.Conn of which the first bytes have been peeked.
+type peekedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *peekedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
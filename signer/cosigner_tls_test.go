@@ -0,0 +1,199 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+)
+
+// startTLSTestServer serves the health service with the transport credentials,
+// and sends the AuthInfo of every request to the returned channel.
+func startTLSTestServer(t *testing.T, creds credentials.TransportCredentials) (string, <-chan credentials.AuthInfo) {
+	sock, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	authInfos := make(chan credentials.AuthInfo, 1)
+	server := grpc.NewServer(grpc.Creds(creds), grpc.UnaryInterceptor(
+		func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			p, _ := peer.FromContext(ctx)
+			authInfos <- p.AuthInfo
+			return handler(ctx, req)
+		},
+	))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+
+	go func() {
+		_ = server.Serve(sock)
+	}()
+	t.Cleanup(server.Stop)
+
+	return sock.Addr().String(), authInfos
+}
+
+func checkTLSTestServer(address string, creds credentials.TransportCredentials) error {
+	conn, err := grpc.Dial(address, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestCosignerTLSCredentials(t *testing.T) {
+	keys, err := CreateCosignerTLSCerts(3)
+	require.NoError(t, err)
+
+	otherKeys, err := CreateCosignerTLSCerts(3)
+	require.NoError(t, err)
+
+	// the server is cosigner 1 on a random port
+	serverCreds := func(mode string) credentials.TransportCredentials {
+		creds, err := NewCosignerTLSCredentials(mode, keys[0].Cert, keys[0].Key, keys[0].CA, CosignersConfig{
+			{ShardID: 1, P2PAddr: "tcp://127.0.0.1:2222"},
+			{ShardID: 2, P2PAddr: "tcp://127.0.0.1:2223"},
+			{ShardID: 3, P2PAddr: "tcp://127.0.0.1:2224"},
+		})
+		require.NoError(t, err)
+		return creds
+	}
+
+	// the client is cosigner 2, or a cosigner of another cluster, which expects cosigner dialedID at the address
+	clientCreds := func(mode string, key CosignerTLSKey, address string, dialedID int) credentials.TransportCredentials {
+		creds, err := NewCosignerTLSCredentials(mode, key.Cert, key.Key, key.CA, CosignersConfig{
+			{ShardID: dialedID, P2PAddr: "tcp://" + address},
+			{ShardID: key.ID, P2PAddr: "tcp://127.0.0.1:2223"},
+		})
+		require.NoError(t, err)
+		return creds
+	}
+
+	tcs := []struct {
+		name       string
+		serverMode string
+		client     func(address string) credentials.TransportCredentials
+		expectErr  bool
+		expectTLS  bool
+	}{
+		{
+			name:       "strict",
+			serverMode: TLSModeStrict,
+			client: func(address string) credentials.TransportCredentials {
+				return clientCreds(TLSModeStrict, keys[1], address, 1)
+			},
+			expectTLS: true,
+		},
+		{
+			name:       "strict server rejects cleartext",
+			serverMode: TLSModeStrict,
+			client: func(string) credentials.TransportCredentials {
+				return insecure.NewCredentials()
+			},
+			expectErr: true,
+		},
+		{
+			name:       "strict server rejects permissive client",
+			serverMode: TLSModeStrict,
+			client: func(address string) credentials.TransportCredentials {
+				return clientCreds(TLSModePermissive, keys[1], address, 1)
+			},
+			expectErr: true,
+		},
+		{
+			name:       "enabled server accepts cleartext",
+			serverMode: TLSModeEnabled,
+			client: func(string) credentials.TransportCredentials {
+				return insecure.NewCredentials()
+			},
+		},
+		{
+			name:       "permissive server accepts mutual TLS",
+			serverMode: TLSModePermissive,
+			client: func(address string) credentials.TransportCredentials {
+				return clientCreds(TLSModeEnabled, keys[1], address, 1)
+			},
+			expectTLS: true,
+		},
+		{
+			name:       "client rejects server with the certificate of another cosigner",
+			serverMode: TLSModeStrict,
+			client: func(address string) credentials.TransportCredentials {
+				return clientCreds(TLSModeStrict, keys[1], address, 3)
+			},
+			expectErr: true,
+		},
+		{
+			name:       "server rejects client of another CA",
+			serverMode: TLSModeStrict,
+			client: func(address string) credentials.TransportCredentials {
+				key := otherKeys[1]
+				key.CA = keys[1].CA
+				return clientCreds(TLSModeStrict, key, address, 1)
+			},
+			expectErr: true,
+		},
+		{
+			name:       "client rejects server at an address of no cosigner",
+			serverMode: TLSModeStrict,
+			client: func(string) credentials.TransportCredentials {
+				return clientCreds(TLSModeStrict, keys[1], "127.0.0.1:1", 1)
+			},
+			expectErr: true,
+		},
+		{
+			name:       "client rejects server of another CA",
+			serverMode: TLSModeStrict,
+			client: func(address string) credentials.TransportCredentials {
+				return clientCreds(TLSModeStrict, otherKeys[1], address, 1)
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			address, authInfos := startTLSTestServer(t, serverCreds(tc.serverMode))
+
+			err := checkTLSTestServer(address, tc.client(address))
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			authInfo := <-authInfos
+			if !tc.expectTLS {
+				require.Equal(t, "insecure", authInfo.AuthType())
+				return
+			}
+			info, ok := authInfo.(CosignerTLSInfo)
+			require.True(t, ok, fmt.Sprintf("unexpected auth info %T", authInfo))
+			require.Equal(t, 2, info.ShardID)
+		})
+	}
+}
+
+func TestCosignerTLSCredentialsDisabled(t *testing.T) {
+	for _, mode := range []string{"", TLSModeDisabled} {
+		creds, err := NewCosignerTLSCredentials(mode, nil, nil, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, insecure.NewCredentials().Info(), creds.Info())
+	}
+
+	_, err := NewCosignerTLSCredentials(TLSModeStrict, nil, nil, nil, nil)
+	require.Error(t, err)
+}
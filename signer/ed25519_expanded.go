@@ -0,0 +1,165 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"crypto/subtle"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	cometcrypto "github.com/cometbft/cometbft/crypto"
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometjson "github.com/cometbft/cometbft/libs/json"
+)
+
+const (
+	// PrivKeyEd25519ExpandedName is the amino name of expanded Ed25519 private keys, which only horcrux can load.
+	// CometBFT can not load them, as its Ed25519 private keys must hold the seed.
+	PrivKeyEd25519ExpandedName = "horcrux/PrivKeyEd25519Expanded"
+	KeyTypeEd25519Expanded     = "ed25519-expanded"
+
+	privKeyEd25519ExpandedSize = 64
+)
+
+func init() {
+	cometjson.RegisterType(PrivKeyEd25519Expanded{}, PrivKeyEd25519ExpandedName)
+}
+
+// PrivKeyEd25519Expanded is an Ed25519 private key in expanded form: the 32 byte secret scalar
+// followed by the 32 byte nonce prefix. It produces standard Ed25519 signatures.
+//
+// A key combined from cosigner shards can only be represented in this form,
+// because the shards are shares of the secret scalar, not of the seed it was derived from.
+type PrivKeyEd25519Expanded []byte
+
+var _ cometcrypto.PrivKey = PrivKeyEd25519Expanded{}
+
+// NewPrivKeyEd25519Expanded creates an expanded Ed25519 private key from the canonical encoding of the secret scalar.
+// The nonce prefix is derived deterministically from the secret scalar.
+func NewPrivKeyEd25519Expanded(scalar []byte) (PrivKeyEd25519Expanded, error) {
+	if _, err := edwards25519.NewScalar().SetCanonicalBytes(scalar); err != nil {
+		return nil, fmt.Errorf("invalid secret scalar: %w", err)
+	}
+
+	h := sha512.New()
+	h.Write([]byte(PrivKeyEd25519ExpandedName))
+	h.Write(scalar)
+	prefix := h.Sum(nil)[:32]
+
+	key := make(PrivKeyEd25519Expanded, 0, privKeyEd25519ExpandedSize)
+	key = append(key, scalar...)
+	return append(key, prefix...), nil
+}
+
+func (privKey PrivKeyEd25519Expanded) scalar() (*edwards25519.Scalar, error) {
+	if len(privKey) != privKeyEd25519ExpandedSize {
+		return nil, fmt.Errorf("invalid expanded ed25519 private key length: %d", len(privKey))
+	}
+	return edwards25519.NewScalar().SetCanonicalBytes(privKey[:32])
+}
+
+// Bytes returns the secret scalar followed by the nonce prefix.
+func (privKey PrivKeyEd25519Expanded) Bytes() []byte {
+	return []byte(privKey)
+}
+
+// Sign produces an Ed25519 signature of msg as specified in RFC 8032,
+// using the expanded secret scalar and nonce prefix.
+func (privKey PrivKeyEd25519Expanded) Sign(msg []byte) ([]byte, error) {
+	s, err := privKey.scalar()
+	if err != nil {
+		return nil, err
+	}
+
+	pubKey := new(edwards25519.Point).ScalarBaseMult(s).Bytes()
+
+	h := sha512.New()
+	h.Write(privKey[32:])
+	h.Write(msg)
+	r, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	R := new(edwards25519.Point).ScalarBaseMult(r).Bytes()
+
+	h.Reset()
+	h.Write(R)
+	h.Write(pubKey)
+	h.Write(msg)
+	k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	S := edwards25519.NewScalar().MultiplyAdd(k, s, r)
+
+	return append(R, S.Bytes()...), nil
+}
+
+// PubKey returns the Ed25519 public key of the secret scalar.
+func (privKey PrivKeyEd25519Expanded) PubKey() cometcrypto.PubKey {
+	s, err := privKey.scalar()
+	if err != nil {
+		panic(err)
+	}
+	return cometcryptoed25519.PubKey(new(edwards25519.Point).ScalarBaseMult(s).Bytes())
+}
+
+// Equals compares the private keys in constant time.
+func (privKey PrivKeyEd25519Expanded) Equals(other cometcrypto.PrivKey) bool {
+	if otherKey, ok := other.(PrivKeyEd25519Expanded); ok {
+		return subtle.ConstantTimeCompare(privKey, otherKey) == 1
+	}
+	return false
+}
+
+// Type returns the key type.
+func (privKey PrivKeyEd25519Expanded) Type() string {
+	return KeyTypeEd25519Expanded
+}
+
+// CombineCosignerEd25519Shards reconstructs the full Ed25519 private key from at least the threshold number
+// of cosigner shards with lagrange interpolation, and verifies it against the public key of the shards.
+func CombineCosignerEd25519Shards(shards []CosignerEd25519Key) (PrivKeyEd25519Expanded, error) {
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("no shards to combine")
+	}
+
+	pubKey := shards[0].PubKey
+	ids := make([]int, len(shards))
+	for i, shard := range shards {
+		if shard.ID <= 0 {
+			return nil, fmt.Errorf("invalid shard ID: %d", shard.ID)
+		}
+		for _, id := range ids[:i] {
+			if id == shard.ID {
+				return nil, fmt.Errorf("duplicate shard ID: %d", shard.ID)
+			}
+		}
+		if shard.PubKey == nil || !bytes.Equal(shard.PubKey.Bytes(), pubKey.Bytes()) {
+			return nil, fmt.Errorf("shard %d has a different public key", shard.ID)
+		}
+		ids[i] = shard.ID
+	}
+
+	secret := edwards25519.NewScalar()
+	for _, shard := range shards {
+		s, err := edwards25519.NewScalar().SetCanonicalBytes(shard.PrivateShard)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private shard %d: %w", shard.ID, err)
+		}
+		lambda, err := lagrangeCoefficient(shard.ID, ids, 0)
+		if err != nil {
+			return nil, err
+		}
+		secret.MultiplyAdd(lambda, s, secret)
+	}
+
+	if !bytes.Equal(new(edwards25519.Point).ScalarBaseMult(secret).Bytes(), pubKey.Bytes()) {
+		return nil, fmt.Errorf("combined key does not match the public key, fewer than the threshold number of shards " +
+			"were provided or a shard is invalid")
+	}
+
+	return NewPrivKeyEd25519Expanded(secret.Bytes())
+}
@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"testing"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometjson "github.com/cometbft/cometbft/libs/json"
+	cometprivval "github.com/cometbft/cometbft/privval"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCombineCosignerEd25519Shards(t *testing.T) {
+	privateKey := cometcryptoed25519.GenPrivKey()
+	pv := cometprivval.FilePVKey{
+		Address: privateKey.PubKey().Address(),
+		PubKey:  privateKey.PubKey(),
+		PrivKey: privateKey,
+	}
+
+	shards := CreateCosignerEd25519Shards(pv, 3, 5)
+
+	_, err := CombineCosignerEd25519Shards(shards[:2])
+	require.Error(t, err)
+
+	_, err = CombineCosignerEd25519Shards([]CosignerEd25519Key{shards[0], shards[1], shards[1]})
+	require.Error(t, err)
+
+	combined, err := CombineCosignerEd25519Shards([]CosignerEd25519Key{shards[4], shards[0], shards[2]})
+	require.NoError(t, err)
+	require.Equal(t, pv.PubKey, combined.PubKey())
+
+	// the expanded key produces standard ed25519 signatures for the original public key
+	msg := []byte("hello")
+	sig, err := combined.Sign(msg)
+	require.NoError(t, err)
+	require.True(t, pv.PubKey.VerifySignature(msg, sig))
+
+	// the combined key survives a json round trip and can be re-sharded
+	bz, err := cometjson.Marshal(cometprivval.FilePVKey{
+		Address: combined.PubKey().Address(),
+		PubKey:  combined.PubKey(),
+		PrivKey: combined,
+	})
+	require.NoError(t, err)
+
+	var decoded cometprivval.FilePVKey
+	require.NoError(t, cometjson.Unmarshal(bz, &decoded))
+	require.True(t, combined.Equals(decoded.PrivKey))
+
+	reshared := CreateCosignerEd25519Shards(decoded, 2, 3)
+	recombined, err := CombineCosignerEd25519Shards(reshared[1:])
+	require.NoError(t, err)
+	require.True(t, combined.Equals(recombined))
+}
@@ -0,0 +1,104 @@
+package signer
+
+import (
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChainHaltHeightEffective(t *testing.T) {
+	tcs := []struct {
+		name       string
+		haltHeight ChainHaltHeight
+		config     int64
+		expected   int64
+	}{
+		{name: "no halt height", expected: 0},
+		{name: "config", config: 100, expected: 100},
+		{name: "runtime", haltHeight: ChainHaltHeight{HaltHeight: 100}, expected: 100},
+		{name: "lower of both", haltHeight: ChainHaltHeight{HaltHeight: 90}, config: 100, expected: 90},
+		{name: "config lifted", haltHeight: ChainHaltHeight{LiftedHeight: 100}, config: 100, expected: 0},
+		{
+			name:       "runtime lifted",
+			haltHeight: ChainHaltHeight{HaltHeight: 90, LiftedHeight: 90},
+			config:     100,
+			expected:   100,
+		},
+		{
+			name:       "next upgrade after lift",
+			haltHeight: ChainHaltHeight{HaltHeight: 100, LiftedHeight: 100},
+			config:     200,
+			expected:   200,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.haltHeight.Effective(tc.config))
+		})
+	}
+}
+
+type staticHaltHeights map[string]ChainHaltHeight
+
+func (h staticHaltHeights) HaltHeight(chainID string) (ChainHaltHeight, error) {
+	return h[chainID], nil
+}
+
+func TestThresholdValidatorHaltHeight(t *testing.T) {
+	pv := &ThresholdValidator{
+		config: &RuntimeConfig{
+			Config: Config{Chains: ChainsConfig{{ChainID: testChainID, HaltHeight: 100}}},
+		},
+	}
+
+	haltHeight, err := pv.HaltHeight(testChainID)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), haltHeight)
+
+	pv.SetHaltHeights(staticHaltHeights{
+		testChainID: {LiftedHeight: 100},
+		"chain-2":   {HaltHeight: 50},
+	})
+
+	haltHeight, err = pv.HaltHeight(testChainID)
+	require.NoError(t, err)
+	require.Zero(t, haltHeight)
+
+	haltHeight, err = pv.HaltHeight("chain-2")
+	require.NoError(t, err)
+	require.Equal(t, int64(50), haltHeight)
+
+	require.NoError(t, checkHaltHeight("chain-2", 50, haltHeight))
+	var haltHeightErr *HaltHeightError
+	require.ErrorAs(t, checkHaltHeight("chain-2", 51, haltHeight), &haltHeightErr)
+	require.NoError(t, checkHaltHeight("chain-2", 51, 0))
+}
+
+func TestLocalCosignerHaltHeight(t *testing.T) {
+	cosigner := NewLocalCosigner(
+		cometlog.NewNopLogger(),
+		&RuntimeConfig{
+			Config: Config{Chains: ChainsConfig{{ChainID: testChainID, HaltHeight: 100}}},
+		},
+		nil,
+		"",
+	)
+
+	// the cosigner refuses to sign its part above the halt height, whatever the leader decided
+	_, err := cosigner.sign(CosignerSignRequest{
+		ChainID:   testChainID,
+		SignBytes: testVoteSignBytes(101, 0, stepPrevote, []byte("block hash"), time.Now()),
+	})
+	var haltHeightErr *HaltHeightError
+	require.ErrorAs(t, err, &haltHeightErr)
+
+	cosigner.SetHaltHeights(staticHaltHeights{testChainID: {HaltHeight: 50}})
+	_, err = cosigner.sign(CosignerSignRequest{
+		ChainID:   testChainID,
+		SignBytes: testVoteSignBytes(51, 0, stepPrevote, []byte("block hash"), time.Now()),
+	})
+	require.ErrorAs(t, err, &haltHeightErr)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/cometbft/cometbft/libs/protoio"
 	cometprotoprivval "github.com/cometbft/cometbft/proto/tendermint/privval"
+	"github.com/cosmos/gogoproto/proto"
 )
 
 // ReadMsg reads a message from an io.Reader
@@ -18,8 +19,8 @@ func ReadMsg(reader io.Reader, maxReadSize int) (msg cometprotoprivval.Message,
 }
 
 // WriteMsg writes a message to an io.Writer
-func WriteMsg(writer io.Writer, msg cometprotoprivval.Message) (err error) {
+func WriteMsg(writer io.Writer, msg proto.Message) (err error) {
 	protoWriter := protoio.NewDelimitedWriter(writer)
-	_, err = protoWriter.WriteMsg(&msg)
+	_, err = protoWriter.WriteMsg(msg)
 	return err
 }
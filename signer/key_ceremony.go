@@ -0,0 +1,311 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	"github.com/strangelove-ventures/horcrux/v3/signer/proto"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	keyCeremonyRetryInterval = 1 * time.Second
+	keyCeremonyPollInterval  = 100 * time.Millisecond
+)
+
+// KeyCeremony runs interactive key protocols between cosigners so that key shards
+// can be managed without the full private key ever existing in a single place.
+// Deals are exchanged over the cosigner gRPC transport and are authenticated
+// and encrypted with the cosigner ECIES keys.
+type KeyCeremony struct {
+	logger   cometlog.Logger
+	security *CosignerSecurityECIES
+	creds    credentials.TransportCredentials
+	address  string
+	peers    map[int]proto.CosignerClient
+
+	mu       sync.Mutex
+	sessions map[string]*keyCeremonySession
+
+	server *grpc.Server
+}
+
+type keyCeremonySession struct {
+	sources map[int]struct{}
+	deals   map[int]receivedDeal
+}
+
+type receivedDeal struct {
+	deal CosignerDeal
+
+	// share is the decrypted key share of the deal.
+	share []byte
+}
+
+// NewKeyCeremony returns a new KeyCeremony between the provided cosigners, over p2p connections
+// with the transport credentials of the cosigners, e.g. from CosignerTransportCredentials.
+// The cosigners must include the cosigner identified by the security.
+func NewKeyCeremony(
+	logger cometlog.Logger,
+	security *CosignerSecurityECIES,
+	cosigners CosignersConfig,
+	creds credentials.TransportCredentials,
+) (*KeyCeremony, error) {
+	c := &KeyCeremony{
+		logger:   logger,
+		security: security,
+		creds:    creds,
+		peers:    make(map[int]proto.CosignerClient, len(cosigners)-1),
+		sessions: make(map[string]*keyCeremonySession),
+	}
+
+	for _, cosigner := range cosigners {
+		if cosigner.ShardID == security.GetID() {
+			c.address = cosigner.P2PAddr
+			continue
+		}
+		client, err := getGRPCClient(cosigner.P2PAddr, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize client for cosigner %d: %w", cosigner.ShardID, err)
+		}
+		c.peers[cosigner.ShardID] = client
+	}
+
+	if c.address == "" {
+		return nil, fmt.Errorf("cosigner config does not exist for our shard ID %d", security.GetID())
+	}
+
+	return c, nil
+}
+
+// Start listens for deals from the other cosigners on our P2P address.
+func (c *KeyCeremony) Start() error {
+	host := p2pURLToRaftAddress(c.address)
+	_, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return fmt.Errorf("failed to parse local address: %s, %v", host, err)
+	}
+	sock, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+	if err != nil {
+		return err
+	}
+
+	c.logger.Info("Key ceremony listening", "port", port)
+
+	c.server = grpc.NewServer(grpc.Creds(c.creds))
+	proto.RegisterCosignerServer(c.server, &keyCeremonyGRPCServer{ceremony: c})
+
+	go func() {
+		if err := c.server.Serve(sock); err != nil {
+			c.logger.Error("Key ceremony server stopped", "error", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops listening for deals.
+func (c *KeyCeremony) Stop() {
+	if c.server != nil {
+		c.server.GracefulStop()
+	}
+}
+
+// ID returns the ID of our cosigner.
+func (c *KeyCeremony) ID() int {
+	return c.security.GetID()
+}
+
+// peerIDs returns the sorted IDs of the other cosigners in the ceremony.
+func (c *KeyCeremony) peerIDs() []int {
+	ids := make([]int, 0, len(c.peers))
+	for id := range c.peers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// ids returns the sorted IDs of all cosigners in the ceremony, including our own.
+func (c *KeyCeremony) ids() []int {
+	ids := append(c.peerIDs(), c.ID())
+	sort.Ints(ids)
+	return ids
+}
+
+// open registers a session so that deals for it will be accepted from the source cosigners.
+func (c *KeyCeremony) open(session string, sources []int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := &keyCeremonySession{
+		sources: make(map[int]struct{}, len(sources)),
+		deals:   make(map[int]receivedDeal, len(sources)),
+	}
+	for _, id := range sources {
+		s.sources[id] = struct{}{}
+	}
+	c.sessions[session] = s
+}
+
+// receive verifies, decrypts and stores a deal from another cosigner.
+func (c *KeyCeremony) receive(deal CosignerDeal) error {
+	c.mu.Lock()
+	s, ok := c.sessions[deal.Session]
+	c.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown key ceremony session: %s", deal.Session)
+	}
+
+	if _, ok := s.sources[deal.SourceID]; !ok {
+		return fmt.Errorf("unexpected deal from cosigner %d for session %s", deal.SourceID, deal.Session)
+	}
+
+	share, err := c.security.DecryptAndVerifyDeal(deal)
+	if err != nil {
+		return fmt.Errorf("failed to verify deal from cosigner %d: %w", deal.SourceID, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if existing, ok := s.deals[deal.SourceID]; ok {
+		// retries of the same deal are accepted, but a cosigner may not change its deal.
+		if !dealsEqual(existing.deal, deal) {
+			return fmt.Errorf("conflicting deals from cosigner %d for session %s", deal.SourceID, deal.Session)
+		}
+		return nil
+	}
+
+	s.deals[deal.SourceID] = receivedDeal{deal: deal, share: share}
+
+	c.logger.Debug("Received deal", "session", deal.Session, "cosigner", deal.SourceID)
+
+	return nil
+}
+
+func dealsEqual(a, b CosignerDeal) bool {
+	if !bytes.Equal(a.Share, b.Share) || len(a.Commitments) != len(b.Commitments) {
+		return false
+	}
+	for i := range a.Commitments {
+		if !bytes.Equal(a.Commitments[i], b.Commitments[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// wait blocks until deals have been received from all source cosigners of the session.
+func (c *KeyCeremony) wait(ctx context.Context, session string) (map[int]receivedDeal, error) {
+	c.mu.Lock()
+	s, ok := c.sessions[session]
+	c.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown key ceremony session: %s", session)
+	}
+
+	ticker := time.NewTicker(keyCeremonyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		c.mu.Lock()
+		if len(s.deals) == len(s.sources) {
+			deals := make(map[int]receivedDeal, len(s.deals))
+			for id, d := range s.deals {
+				deals[id] = d
+			}
+			c.mu.Unlock()
+			return deals, nil
+		}
+		var missing []int
+		for id := range s.sources {
+			if _, ok := s.deals[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			sort.Ints(missing)
+			return nil, fmt.Errorf("timed out waiting for deals from cosigners %v for session %s: %w",
+				missing, session, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// send deals to the destination cosigners, encrypting each share for its destination.
+// If shares does not contain a share for a destination, only the commitments are sent.
+func (c *KeyCeremony) send(
+	ctx context.Context,
+	session string,
+	destinations []int,
+	commitments [][]byte,
+	shares map[int][]byte,
+) error {
+	var eg errgroup.Group
+	for _, id := range destinations {
+		id := id
+		eg.Go(func() error {
+			client, ok := c.peers[id]
+			if !ok {
+				return fmt.Errorf("unknown cosigner: %d", id)
+			}
+
+			deal, err := c.security.EncryptAndSignDeal(id, session, commitments, shares[id])
+			if err != nil {
+				return err
+			}
+
+			for {
+				_, err := client.Deal(ctx, deal.toProto())
+				if err == nil {
+					return nil
+				}
+
+				c.logger.Debug("Failed to send deal, retrying", "session", session, "cosigner", id, "error", err)
+
+				select {
+				case <-ctx.Done():
+					return fmt.Errorf("failed to send deal to cosigner %d for session %s: %w", id, session, err)
+				case <-time.After(keyCeremonyRetryInterval):
+				}
+			}
+		})
+	}
+	return eg.Wait()
+}
+
+var _ proto.CosignerServer = &keyCeremonyGRPCServer{}
+
+// keyCeremonyGRPCServer serves the cosigner gRPC service while a key ceremony is running.
+// Only deals are accepted.
+type keyCeremonyGRPCServer struct {
+	ceremony *KeyCeremony
+	proto.UnimplementedCosignerServer
+}
+
+func (rpc *keyCeremonyGRPCServer) Deal(
+	_ context.Context,
+	req *proto.DealRequest,
+) (*proto.DealResponse, error) {
+	if err := rpc.ceremony.receive(CosignerDealFromProto(req)); err != nil {
+		rpc.ceremony.logger.Error("Rejected deal", "session", req.Session, "cosigner", req.SourceID, "error", err)
+		return nil, err
+	}
+	return &proto.DealResponse{}, nil
+}
+
+func (rpc *keyCeremonyGRPCServer) Ping(context.Context, *proto.PingRequest) (*proto.PingResponse, error) {
+	return &proto.PingResponse{}, nil
+}
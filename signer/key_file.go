@@ -0,0 +1,248 @@
+package signer
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/cometbft/cometbft/libs/tempfile"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	keyFileKDFScrypt               = "scrypt"
+	keyFileCipherXChaCha20Poly1305 = "xchacha20-poly1305"
+
+	keyFileScryptN       = 1 << 17
+	keyFileScryptR       = 8
+	keyFileScryptP       = 1
+	keyFileScryptSaltLen = 32
+)
+
+// keyFileAdditionalData binds the ciphertext to the envelope format version.
+var keyFileAdditionalData = []byte("horcrux-encrypted-key-file-v1")
+
+// ErrNoKeyPassphrase is returned when an encrypted key file is read without a passphrase provider.
+var ErrNoKeyPassphrase = errors.New("key file is encrypted, but no passphrase was provided")
+
+// EncryptedKeyFile is the on disk envelope format for a passphrase encrypted key file.
+// The encryption key is derived from the passphrase with scrypt,
+// and the key file contents are encrypted with XChaCha20-Poly1305.
+type EncryptedKeyFile struct {
+	KDF        string          `json:"kdf"`
+	KDFParams  ScryptKDFParams `json:"kdfParams"`
+	Cipher     string          `json:"cipher"`
+	Nonce      []byte          `json:"nonce"`
+	Ciphertext []byte          `json:"ciphertext"`
+}
+
+// ScryptKDFParams are the scrypt parameters used to derive the encryption key.
+type ScryptKDFParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+}
+
+// KeyPassphraseProvider returns the passphrase used to decrypt encrypted key files.
+type KeyPassphraseProvider func() ([]byte, error)
+
+var (
+	keyPassphraseMu       sync.Mutex
+	keyPassphraseProvider KeyPassphraseProvider
+	newKeyFilePassphrase  []byte
+)
+
+// SetKeyPassphraseProvider sets the provider for the passphrase of encrypted key files.
+// The provider is only called when an encrypted key file is read.
+func SetKeyPassphraseProvider(provider KeyPassphraseProvider) {
+	keyPassphraseMu.Lock()
+	defer keyPassphraseMu.Unlock()
+	keyPassphraseProvider = provider
+}
+
+// SetNewKeyFilePassphrase sets the passphrase that key files are encrypted with when they are created,
+// e.g. by the key ceremonies or shards encrypt. If nil, new key files are written in plaintext.
+func SetNewKeyFilePassphrase(passphrase []byte) {
+	keyPassphraseMu.Lock()
+	defer keyPassphraseMu.Unlock()
+	newKeyFilePassphrase = passphrase
+}
+
+func keyPassphrase() ([]byte, error) {
+	keyPassphraseMu.Lock()
+	defer keyPassphraseMu.Unlock()
+	if keyPassphraseProvider == nil {
+		return nil, ErrNoKeyPassphrase
+	}
+	return keyPassphraseProvider()
+}
+
+// IsEncryptedKeyFile returns true if the key file contents are an encrypted key file envelope.
+func IsEncryptedKeyFile(bz []byte) bool {
+	var envelope EncryptedKeyFile
+	if err := json.Unmarshal(bz, &envelope); err != nil {
+		return false
+	}
+	return envelope.KDF != "" && envelope.Cipher != "" && len(envelope.Ciphertext) > 0
+}
+
+// EncryptKeyFileBytes encrypts key file contents with the passphrase into an encrypted key file envelope.
+func EncryptKeyFileBytes(plaintext, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase must not be empty")
+	}
+
+	envelope := EncryptedKeyFile{
+		KDF: keyFileKDFScrypt,
+		KDFParams: ScryptKDFParams{
+			N:    keyFileScryptN,
+			R:    keyFileScryptR,
+			P:    keyFileScryptP,
+			Salt: make([]byte, keyFileScryptSaltLen),
+		},
+		Cipher: keyFileCipherXChaCha20Poly1305,
+		Nonce:  make([]byte, chacha20poly1305.NonceSizeX),
+	}
+
+	if _, err := rand.Read(envelope.KDFParams.Salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(envelope.Nonce); err != nil {
+		return nil, err
+	}
+
+	aead, err := envelope.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope.Ciphertext = aead.Seal(nil, envelope.Nonce, plaintext, keyFileAdditionalData)
+
+	return json.MarshalIndent(&envelope, "", "  ")
+}
+
+// DecryptKeyFileBytes decrypts an encrypted key file envelope with the passphrase.
+func DecryptKeyFileBytes(bz, passphrase []byte) ([]byte, error) {
+	var envelope EncryptedKeyFile
+	if err := json.Unmarshal(bz, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid encrypted key file: %w", err)
+	}
+
+	aead, err := envelope.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(envelope.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce length: %d", len(envelope.Nonce))
+	}
+
+	plaintext, err := aead.Open(nil, envelope.Nonce, envelope.Ciphertext, keyFileAdditionalData)
+	if err != nil {
+		return nil, errors.New("failed to decrypt key file, the passphrase may be incorrect")
+	}
+
+	return plaintext, nil
+}
+
+func (envelope *EncryptedKeyFile) aead(passphrase []byte) (cipher.AEAD, error) {
+	if envelope.KDF != keyFileKDFScrypt {
+		return nil, fmt.Errorf("unsupported key derivation function: %s", envelope.KDF)
+	}
+	if envelope.Cipher != keyFileCipherXChaCha20Poly1305 {
+		return nil, fmt.Errorf("unsupported cipher: %s", envelope.Cipher)
+	}
+
+	p := envelope.KDFParams
+	key, err := scrypt.Key(passphrase, p.Salt, p.N, p.R, p.P, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	return chacha20poly1305.NewX(key)
+}
+
+// readKeyFile reads a key file, decrypting it with the key passphrase if it is encrypted.
+func readKeyFile(file string) ([]byte, error) {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if !IsEncryptedKeyFile(bz) {
+		return bz, nil
+	}
+
+	passphrase, err := keyPassphrase()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file (%s): %w", file, err)
+	}
+
+	bz, err = DecryptKeyFileBytes(bz, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file (%s): %w", file, err)
+	}
+
+	return bz, nil
+}
+
+// writeKeyFile writes a new key file, encrypted with the new key file passphrase if it is set,
+// so that the plaintext key never touches the disk.
+func writeKeyFile(file string, bz []byte) error {
+	keyPassphraseMu.Lock()
+	passphrase := newKeyFilePassphrase
+	keyPassphraseMu.Unlock()
+
+	if passphrase != nil {
+		var err error
+		if bz, err = EncryptKeyFileBytes(bz, passphrase); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(file, bz, 0600)
+}
+
+// EncryptKeyFile encrypts a plaintext key file in place with the passphrase.
+func EncryptKeyFile(file string, passphrase []byte) error {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if IsEncryptedKeyFile(bz) {
+		return fmt.Errorf("key file is already encrypted: %s", file)
+	}
+
+	encrypted, err := EncryptKeyFileBytes(bz, passphrase)
+	if err != nil {
+		return err
+	}
+
+	return tempfile.WriteFileAtomic(file, encrypted, 0600)
+}
+
+// DecryptKeyFile decrypts an encrypted key file in place with the passphrase.
+func DecryptKeyFile(file string, passphrase []byte) error {
+	bz, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	if !IsEncryptedKeyFile(bz) {
+		return fmt.Errorf("key file is not encrypted: %s", file)
+	}
+
+	decrypted, err := DecryptKeyFileBytes(bz, passphrase)
+	if err != nil {
+		return err
+	}
+
+	return tempfile.WriteFileAtomic(file, decrypted, 0600)
+}
@@ -0,0 +1,111 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometprivval "github.com/cometbft/cometbft/privval"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptedKeyFileRoundTrip(t *testing.T) {
+	plaintext := []byte(`{"test":"key"}`)
+	passphrase := []byte("correct horse battery staple")
+
+	encrypted, err := EncryptKeyFileBytes(plaintext, passphrase)
+	require.NoError(t, err)
+	require.True(t, IsEncryptedKeyFile(encrypted))
+	require.False(t, IsEncryptedKeyFile(plaintext))
+	require.NotContains(t, string(encrypted), string(plaintext))
+
+	decrypted, err := DecryptKeyFileBytes(encrypted, passphrase)
+	require.NoError(t, err)
+	require.Equal(t, plaintext, decrypted)
+
+	_, err = DecryptKeyFileBytes(encrypted, []byte("wrong passphrase"))
+	require.Error(t, err)
+
+	_, err = EncryptKeyFileBytes(plaintext, nil)
+	require.Error(t, err)
+}
+
+func TestLoadEncryptedCosignerEd25519Key(t *testing.T) {
+	t.Cleanup(func() { SetKeyPassphraseProvider(nil) })
+
+	privateKey := cometcryptoed25519.GenPrivKey()
+	keys := CreateCosignerEd25519Shards(cometprivval.FilePVKey{
+		Address: privateKey.PubKey().Address(),
+		PubKey:  privateKey.PubKey(),
+		PrivKey: privateKey,
+	}, 2, 3)
+
+	file := filepath.Join(t.TempDir(), "test_shard.json")
+	require.NoError(t, WriteCosignerEd25519ShardFile(keys[0], file))
+
+	passphrase := []byte("passphrase")
+	require.NoError(t, EncryptKeyFile(file, passphrase))
+	require.Error(t, EncryptKeyFile(file, passphrase))
+
+	bz, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.True(t, IsEncryptedKeyFile(bz))
+
+	SetKeyPassphraseProvider(nil)
+	_, err = LoadCosignerEd25519Key(file)
+	require.ErrorIs(t, err, ErrNoKeyPassphrase)
+
+	SetKeyPassphraseProvider(func() ([]byte, error) { return []byte("wrong"), nil })
+	_, err = LoadCosignerEd25519Key(file)
+	require.Error(t, err)
+
+	SetKeyPassphraseProvider(func() ([]byte, error) { return passphrase, nil })
+	key, err := LoadCosignerEd25519Key(file)
+	require.NoError(t, err)
+	require.Equal(t, keys[0], key)
+
+	require.NoError(t, DecryptKeyFile(file, passphrase))
+	SetKeyPassphraseProvider(nil)
+	key, err = LoadCosignerEd25519Key(file)
+	require.NoError(t, err)
+	require.Equal(t, keys[0], key)
+}
+
+func TestWriteEncryptedNewKeyFiles(t *testing.T) {
+	t.Cleanup(func() {
+		SetNewKeyFilePassphrase(nil)
+		SetKeyPassphraseProvider(nil)
+	})
+
+	passphrase := []byte("passphrase")
+	SetNewKeyFilePassphrase(passphrase)
+	SetKeyPassphraseProvider(func() ([]byte, error) { return passphrase, nil })
+
+	keys, err := CreateCosignerECIESShards(2)
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "ecies_keys.json")
+	require.NoError(t, WriteCosignerECIESShardFile(keys[0], file))
+
+	bz, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.True(t, IsEncryptedKeyFile(bz))
+
+	key, err := LoadCosignerECIESKey(file)
+	require.NoError(t, err)
+	require.Equal(t, keys[0].ID, key.ID)
+
+	// a new connection key is encrypted too, and loaded again on the next start
+	connKeyFile := filepath.Join(t.TempDir(), ConnectionKeyFile)
+	connKey, err := LoadOrGenConnectionKey(connKeyFile)
+	require.NoError(t, err)
+
+	bz, err = os.ReadFile(connKeyFile)
+	require.NoError(t, err)
+	require.True(t, IsEncryptedKeyFile(bz))
+
+	loaded, err := LoadOrGenConnectionKey(connKeyFile)
+	require.NoError(t, err)
+	require.Equal(t, connKey.ID(), loaded.ID())
+}
@@ -1,14 +1,16 @@
 package signer
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
+	"filippo.io/edwards25519"
 	cometcrypto "github.com/cometbft/cometbft/crypto"
-	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
 	cometlog "github.com/cometbft/cometbft/libs/log"
 	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
@@ -34,6 +36,9 @@ type LocalCosigner struct {
 	nonces map[uuid.UUID]*NoncesWithExpiration
 	// protects the nonces map
 	noncesMu sync.RWMutex
+
+	slashingProtection *SlashingProtectionDB
+	haltHeights        HaltHeights
 }
 
 func NewLocalCosigner(
@@ -51,12 +56,44 @@ func NewLocalCosigner(
 	}
 }
 
+// SetSlashingProtection sets the slashing protection database which is consulted before signing,
+// and which records every partial signature. It must be called before signing.
+func (cosigner *LocalCosigner) SetSlashingProtection(db *SlashingProtectionDB) {
+	cosigner.slashingProtection = db
+}
+
+// SetHaltHeights sets the runtime halt heights of the chains, which are consulted in addition to
+// the haltHeight of the chains config, so that a cosigner refuses to sign above the halt height
+// even if the leader does not. It must be called before signing.
+func (cosigner *LocalCosigner) SetHaltHeights(haltHeights HaltHeights) {
+	cosigner.haltHeights = haltHeights
+}
+
 type ChainState struct {
 	// lastSignState stores the last sign state for an HRS we have fully signed
 	// incremented whenever we are asked to sign an HRS
 	lastSignState *SignState
 	// signer generates nonces, combines nonces, signs, and verifies signatures.
 	signer ThresholdSigner
+	// rotatedSigner is the signer of the new key of the scheduled key rotation of the chain, if any,
+	// which signs from the activation height of keyRotation on.
+	rotatedSigner ThresholdSigner
+	keyRotation   *KeyRotation
+}
+
+// signerForHeight returns the signer of the key that signs the height.
+func (ccs *ChainState) signerForHeight(height int64) ThresholdSigner {
+	if ccs.rotatedSigner != nil && ccs.keyRotation.Active(height) {
+		return ccs.rotatedSigner
+	}
+	return ccs.signer
+}
+
+// nextHeight returns the height after the last signed height, which is the next height to sign.
+func (ccs *ChainState) nextHeight() int64 {
+	ccs.lastSignState.mu.RLock()
+	defer ccs.lastSignState.mu.RUnlock()
+	return ccs.lastSignState.Height + 1
 }
 
 // StartNoncePruner periodically prunes nonces that have expired.
@@ -110,6 +147,95 @@ func (cosigner *LocalCosigner) combinedNonces(myID int, threshold uint8, uuid uu
 	return combinedNonces, nil
 }
 
+// withOwnNonce returns the nonces of a sign request with the nonce commitments of this cosigner, which are not
+// part of the request. They are signed like the nonces of the other cosigners, so that the leader can verify them.
+func (cosigner *LocalCosigner) withOwnNonce(uuid uuid.UUID, nonces CosignerNonces) (CosignerNonces, error) {
+	cosigner.noncesMu.RLock()
+	meta, ok := cosigner.nonces[uuid]
+	cosigner.noncesMu.RUnlock()
+	if !ok {
+		return nil, errors.New("no metadata at HRS")
+	}
+
+	id := cosigner.GetID()
+	commitments := meta.Nonces[id-1].Commitments
+
+	msg, err := nonceDigestMessage(id, nil, nil, commitments)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := cosigner.security.Sign(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(slices.Clone(nonces), CosignerNonce{
+		SourceID:      id,
+		DestinationID: id,
+		Commitments:   commitments,
+		Signature:     sig,
+	}), nil
+}
+
+// frostNonces returns the nonce commitments of all cosigners that participate in the signature,
+// and the nonces of this cosigner. The nonces are removed, because a FROST nonce that is used
+// for more than one signature reveals the key shard.
+func (cosigner *LocalCosigner) frostNonces(myID int, threshold uint8, uuid uuid.UUID) ([]Nonce, error) {
+	cosigner.noncesMu.Lock()
+	defer cosigner.noncesMu.Unlock()
+
+	nonces, ok := cosigner.nonces[uuid]
+	if !ok {
+		return nil, errors.New("no metadata at HRS")
+	}
+
+	frostNonces := make([]Nonce, 0, threshold)
+
+	for i, c := range nonces.Nonces {
+		if len(c.PubKey) == 0 {
+			continue
+		}
+
+		nonce := Nonce{
+			ID:     i + 1,
+			PubKey: c.PubKey,
+		}
+
+		if nonce.ID == myID {
+			if len(c.Shares) < myID || len(c.Shares[myID-1]) == 0 {
+				return nil, fmt.Errorf("nonces for %s have already been used", uuid)
+			}
+			nonce.Share = c.Shares[myID-1]
+			nonces.Nonces[i].Shares = nil
+		}
+
+		frostNonces = append(frostNonces, nonce)
+	}
+
+	return frostNonces, nil
+}
+
+// frost returns whether the cluster signs with FROST.
+func (cosigner *LocalCosigner) frost() bool {
+	return cosigner.config.Config.ThresholdModeConfig.SignScheme == SignSchemeFROST
+}
+
+// frostNonceSecret returns the secret signing share that hedges the FROST nonces of this cosigner.
+// Nonces are generated before the chain of the sign request is known, so the key shard of any
+// loaded chain is used. Before any chain is loaded, the nonces are only derived from randomness.
+func (cosigner *LocalCosigner) frostNonceSecret() *edwards25519.Scalar {
+	secret := edwards25519.NewScalar()
+	cosigner.chainState.Range(func(_, value any) bool {
+		signer, ok := value.(*ChainState).signer.(*ThresholdSignerFROST)
+		if !ok {
+			return true
+		}
+		secret = signer.privateKeyShard
+		return false
+	})
+	return secret
+}
+
 // Save updates the high watermark height/round/step (HRS) if it is greater
 // than the current high watermark. A mutex is used to avoid concurrent state updates.
 // The disk write is scheduled in a separate goroutine which will perform an atomic write.
@@ -158,7 +284,8 @@ func (cosigner *LocalCosigner) getChainState(chainID string) (*ChainState, error
 	return ccs, nil
 }
 
-// GetPubKey returns public key of the validator.
+// GetPubKey returns public key of the validator for the next height to sign,
+// which is the new key once the activation height of a scheduled key rotation is reached.
 // Implements Cosigner interface
 func (cosigner *LocalCosigner) GetPubKey(chainID string) (cometcrypto.PubKey, error) {
 	if err := cosigner.LoadSignStateIfNecessary(chainID); err != nil {
@@ -170,22 +297,85 @@ func (cosigner *LocalCosigner) GetPubKey(chainID string) (cometcrypto.PubKey, er
 		return nil, err
 	}
 
-	return cometcryptoed25519.PubKey(ccs.signer.PubKey()), nil
+	return thresholdPubKey(ccs.signerForHeight(ccs.nextHeight()).PubKey()), nil
 }
 
-// CombineSignatures combines partial signatures into a full signature.
-func (cosigner *LocalCosigner) CombineSignatures(chainID string, signatures []PartialSignature) ([]byte, error) {
+// CombineSignatures combines partial signatures for the height into a full signature.
+func (cosigner *LocalCosigner) CombineSignatures(
+	chainID string,
+	height int64,
+	signatures []PartialSignature,
+) ([]byte, error) {
 	ccs, err := cosigner.getChainState(chainID)
 	if err != nil {
 		return nil, err
 	}
 
-	return ccs.signer.CombineSignatures(signatures)
+	return ccs.signerForHeight(height).CombineSignatures(signatures)
+}
+
+// VerifyPartialSignature validates a partial signature against the public key shard
+// of the cosigner that produced it, given the nonces of all cosigners that participated in the signature.
+func (cosigner *LocalCosigner) VerifyPartialSignature(
+	chainID string,
+	height int64,
+	payload []byte,
+	nonces *CosignerUUIDNonces,
+	signature PartialSignature,
+) error {
+	ccs, err := cosigner.getChainState(chainID)
+	if err != nil {
+		return err
+	}
+
+	return ccs.signerForHeight(height).VerifyPartialSignature(payload, nonces, signature)
+}
+
+// VerifyNonces verifies that the nonces which the cosigner with the ID signed with were signed by their
+// source cosigners, so that a cosigner can not pick the nonces that its partial signature is verified against.
+// FROST nonce commitments are public and not signed by their source cosigners.
+func (cosigner *LocalCosigner) VerifyNonces(id int, nonces CosignerNonces) error {
+	if cosigner.frost() {
+		return nil
+	}
+
+	sources := make(map[int]struct{}, len(nonces))
+	for _, nonce := range nonces {
+		if _, ok := sources[nonce.SourceID]; ok {
+			return fmt.Errorf("more than one nonce of cosigner %d", nonce.SourceID)
+		}
+		sources[nonce.SourceID] = struct{}{}
+		if nonce.DestinationID != id {
+			return fmt.Errorf("nonce of cosigner %d is for cosigner %d", nonce.SourceID, nonce.DestinationID)
+		}
+		msg, err := nonceDigestMessage(nonce.SourceID, nonce.PubKey, nonce.Share, nonce.Commitments)
+		if err != nil {
+			return err
+		}
+		if err := cosigner.security.Verify(nonce.SourceID, msg, nonce.Signature); err != nil {
+			return fmt.Errorf("invalid nonce signature of cosigner %d: %w", nonce.SourceID, err)
+		}
+	}
+
+	return nil
 }
 
-// VerifySignature validates a signed payload against the public key.
+// VerifySignature validates a signed payload against the public key of the next height to sign.
 // Implements Cosigner interface
 func (cosigner *LocalCosigner) VerifySignature(chainID string, payload, signature []byte) bool {
+	pubKey, err := cosigner.GetPubKey(chainID)
+	if err != nil {
+		return false
+	}
+
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+
+	return pubKey.VerifySignature(payload, sig)
+}
+
+// VerifySignatureForHeight validates a signed payload against the public key of the key that signs the height.
+func (cosigner *LocalCosigner) VerifySignatureForHeight(chainID string, height int64, payload, signature []byte) bool {
 	if err := cosigner.LoadSignStateIfNecessary(chainID); err != nil {
 		return false
 	}
@@ -198,7 +388,7 @@ func (cosigner *LocalCosigner) VerifySignature(chainID string, payload, signatur
 	sig := make([]byte, len(signature))
 	copy(sig, signature)
 
-	return cometcryptoed25519.PubKey(ccs.signer.PubKey()).VerifySignature(payload, sig)
+	return thresholdPubKey(ccs.signerForHeight(height).PubKey()).VerifySignature(payload, sig)
 }
 
 // Sign the sign request using the cosigner's shard
@@ -209,12 +399,20 @@ func (cosigner *LocalCosigner) sign(req CosignerSignRequest) (CosignerSignRespon
 
 	res := CosignerSignResponse{}
 
-	ccs, err := cosigner.getChainState(chainID)
+	hrst, hasVoteExtensions, err := verifySignPayload(chainID, req.SignBytes, req.VoteExtensionSignBytes)
 	if err != nil {
 		return res, err
 	}
 
-	hrst, hasVoteExtensions, err := verifySignPayload(chainID, req.SignBytes, req.VoteExtensionSignBytes)
+	haltHeight, err := effectiveHaltHeight(cosigner.config, cosigner.haltHeights, chainID)
+	if err != nil {
+		return res, err
+	}
+	if err := checkHaltHeight(chainID, hrst.Height, haltHeight); err != nil {
+		return res, err
+	}
+
+	ccs, err := cosigner.getChainState(chainID)
 	if err != nil {
 		return res, err
 	}
@@ -228,10 +426,40 @@ func (cosigner *LocalCosigner) sign(req CosignerSignRequest) (CosignerSignRespon
 	}
 
 	if existingSignature != nil {
-		res.Signature = existingSignature
+		res.Signature = existingSignature.Signature
+		res.VoteExtensionSignature = existingSignature.VoteExtensionSignature
+		res.Nonces = existingSignature.Nonces
+		res.VoteExtensionNonces = existingSignature.VoteExtensionNonces
+		res.Existing = true
 		return res, nil
 	}
 
+	// The sign state only knows the latest signed block, so consult the full signing history as well.
+	if cosigner.slashingProtection != nil {
+		existing, err := cosigner.slashingProtection.ExistingSignature(
+			slashingProtectionCosigner, chainID, hrst.HRSKey(), req.SignBytes,
+		)
+		if err != nil {
+			totalSlashingProtectionRefusals.WithLabelValues(chainID, slashingProtectionCosigner).Inc()
+			return res, fmt.Errorf("slashing protection: %w", err)
+		}
+		if existing != nil && !bytes.Equal(existing.SignBytes, req.SignBytes) {
+			// the leader can only combine partial signatures of its own sign bytes
+			return res, fmt.Errorf(
+				"slashing protection: already signed %d.%d.%d with a different timestamp",
+				hrst.Height, hrst.Round, hrst.Step,
+			)
+		}
+		if existing != nil {
+			res.Signature = existing.Signature
+			res.VoteExtensionSignature = existing.VoteExtensionSignature
+			res.Nonces = existing.Nonces
+			res.VoteExtensionNonces = existing.VoteExtensionNonces
+			res.Existing = true
+			return res, nil
+		}
+	}
+
 	defer func() {
 		cosigner.noncesMu.Lock()
 		delete(cosigner.nonces, req.UUID)
@@ -239,7 +467,12 @@ func (cosigner *LocalCosigner) sign(req CosignerSignRequest) (CosignerSignRespon
 		cosigner.noncesMu.Unlock()
 	}()
 
-	nonces, err := cosigner.combinedNonces(
+	combinedNonces := cosigner.combinedNonces
+	if cosigner.frost() {
+		combinedNonces = cosigner.frostNonces
+	}
+
+	nonces, err := combinedNonces(
 		cosigner.GetID(),
 		uint8(cosigner.config.Config.ThresholdModeConfig.Threshold),
 		req.UUID,
@@ -250,7 +483,7 @@ func (cosigner *LocalCosigner) sign(req CosignerSignRequest) (CosignerSignRespon
 
 	var voteExtNonces []Nonce
 	if hasVoteExtensions {
-		voteExtNonces, err = cosigner.combinedNonces(
+		voteExtNonces, err = combinedNonces(
 			cosigner.GetID(),
 			uint8(cosigner.config.Config.ThresholdModeConfig.Threshold),
 			req.VoteExtUUID,
@@ -260,18 +493,35 @@ func (cosigner *LocalCosigner) sign(req CosignerSignRequest) (CosignerSignRespon
 		}
 	}
 
+	// persist the nonces with the signatures, so that an existing signature can be verified by any leader
+	signNonces, voteExtSignNonces := req.Nonces, req.VoteExtensionNonces
+	if !cosigner.frost() {
+		signNonces, err = cosigner.withOwnNonce(req.UUID, req.Nonces)
+		if err != nil {
+			return res, err
+		}
+		if hasVoteExtensions {
+			voteExtSignNonces, err = cosigner.withOwnNonce(req.VoteExtUUID, req.VoteExtensionNonces)
+			if err != nil {
+				return res, err
+			}
+		}
+	}
+
 	var eg errgroup.Group
 
+	signer := ccs.signerForHeight(hrst.Height)
+
 	var sig, voteExtSig []byte
 	eg.Go(func() error {
 		var err error
-		sig, err = ccs.signer.Sign(nonces, req.SignBytes)
+		sig, err = signer.Sign(nonces, req.SignBytes)
 		return err
 	})
 	if hasVoteExtensions {
 		eg.Go(func() error {
 			var err error
-			voteExtSig, err = ccs.signer.Sign(voteExtNonces, req.VoteExtensionSignBytes)
+			voteExtSig, err = signer.Sign(voteExtNonces, req.VoteExtensionSignBytes)
 			return err
 		})
 	}
@@ -280,13 +530,33 @@ func (cosigner *LocalCosigner) sign(req CosignerSignRequest) (CosignerSignRespon
 		return res, err
 	}
 
+	// The partial signature is only released once it is persisted in the signing history.
+	if cosigner.slashingProtection != nil {
+		err := cosigner.slashingProtection.Record(slashingProtectionCosigner, chainID, SlashingProtectionRecord{
+			Height:                 hrst.Height,
+			Round:                  hrst.Round,
+			Step:                   hrst.Step,
+			SignBytes:              req.SignBytes,
+			Signature:              sig,
+			VoteExtensionSignature: voteExtSig,
+			Nonces:                 signNonces,
+			VoteExtensionNonces:    voteExtSignNonces,
+		})
+		if err != nil {
+			totalSlashingProtectionRefusals.WithLabelValues(chainID, slashingProtectionCosigner).Inc()
+			return res, fmt.Errorf("error recording signature for slashing protection: %w", err)
+		}
+	}
+
 	err = ccs.lastSignState.Save(SignStateConsensus{
 		Height:                 hrst.Height,
 		Round:                  hrst.Round,
 		Step:                   hrst.Step,
 		Signature:              sig,
 		SignBytes:              req.SignBytes,
-		VoteExtensionSignature: res.VoteExtensionSignature,
+		VoteExtensionSignature: voteExtSig,
+		Nonces:                 signNonces,
+		VoteExtensionNonces:    voteExtSignNonces,
 	}, &cosigner.pendingDiskWG)
 
 	if err != nil {
@@ -308,10 +578,16 @@ func (cosigner *LocalCosigner) generateNonces() ([]Nonces, error) {
 	total := len(cosigner.config.Config.ThresholdModeConfig.Cosigners)
 	meta := make([]Nonces, total)
 
-	nonces, err := GenerateNonces(
-		uint8(cosigner.config.Config.ThresholdModeConfig.Threshold),
-		uint8(total),
-	)
+	var nonces Nonces
+	var err error
+	if cosigner.frost() {
+		nonces, err = GenerateFROSTNonces(cosigner.GetID(), uint8(total), cosigner.frostNonceSecret())
+	} else {
+		nonces, err = GenerateNonces(
+			uint8(cosigner.config.Config.ThresholdModeConfig.Threshold),
+			uint8(total),
+		)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -335,17 +611,26 @@ func (cosigner *LocalCosigner) LoadSignStateIfNecessary(chainID string) error {
 		return err
 	}
 
-	var signer ThresholdSigner
-
-	signer, err = NewThresholdSignerSoft(cosigner.config, cosigner.GetID(), chainID)
+	signer, err := NewThresholdSigner(cosigner.config, cosigner.GetID(), chainID)
 	if err != nil {
 		return err
 	}
 
-	cosigner.chainState.Store(chainID, &ChainState{
+	ccs := &ChainState{
 		lastSignState: signState,
 		signer:        signer,
-	})
+		keyRotation:   cosigner.config.keyRotation(chainID),
+	}
+
+	if ccs.keyRotation != nil {
+		keyFile := cosigner.config.KeyFilePathRotation(chainID)
+		ccs.rotatedSigner, err = newThresholdSignerForKeyFile(cosigner.config, cosigner.GetID(), keyFile)
+		if err != nil {
+			return fmt.Errorf("error loading rotated key for chain id (%s): %w", chainID, err)
+		}
+	}
+
+	cosigner.chainState.Store(chainID, ccs)
 
 	return nil
 }
@@ -377,6 +662,18 @@ func (cosigner *LocalCosigner) GetNonces(
 				return err
 			}
 
+			if cosigner.frost() {
+				// FROST nonce commitments are public, so the same commitments are sent to all cosigners.
+				res[j] = &CosignerUUIDNonces{
+					UUID: u,
+					Nonces: []CosignerNonce{{
+						SourceID: id,
+						PubKey:   meta.Nonces[id-1].PubKey,
+					}},
+				}
+				return nil
+			}
+
 			var eg errgroup.Group
 
 			nonces := make([]CosignerNonce, total-1)
@@ -459,7 +756,9 @@ func (cosigner *LocalCosigner) getNonce(
 	id := cosigner.GetID()
 
 	ourCosignerMeta := meta.Nonces[id-1]
-	nonce, err := cosigner.security.EncryptAndSign(peerID, ourCosignerMeta.PubKey, ourCosignerMeta.Shares[peerID-1])
+	nonce, err := cosigner.security.EncryptAndSign(
+		peerID, ourCosignerMeta.PubKey, ourCosignerMeta.Shares[peerID-1], ourCosignerMeta.Commitments,
+	)
 	if err != nil {
 		return zero, err
 	}
@@ -469,19 +768,69 @@ func (cosigner *LocalCosigner) getNonce(
 
 const errUnexpectedState = "unexpected state, metadata does not exist for U:"
 
+// setFROSTNonce stores the nonce commitments of a cosigner that participates in the signature.
+func (cosigner *LocalCosigner) setFROSTNonce(uuid uuid.UUID, nonce CosignerNonce) error {
+	total := len(cosigner.config.Config.ThresholdModeConfig.Cosigners)
+	if nonce.SourceID <= 0 || nonce.SourceID > total {
+		return fmt.Errorf("invalid nonce source ID: %d", nonce.SourceID)
+	}
+	if len(nonce.PubKey) != frostNonceSize {
+		return fmt.Errorf("invalid nonce commitments length from cosigner %d: %d", nonce.SourceID, len(nonce.PubKey))
+	}
+
+	// protects the meta map
+	cosigner.noncesMu.Lock()
+	defer cosigner.noncesMu.Unlock()
+
+	n, ok := cosigner.nonces[uuid]
+	if !ok {
+		return fmt.Errorf(
+			"%s %s",
+			errUnexpectedState,
+			uuid,
+		)
+	}
+
+	if nonce.SourceID == cosigner.GetID() {
+		if !bytes.Equal(n.Nonces[nonce.SourceID-1].PubKey, nonce.PubKey) {
+			return fmt.Errorf("nonce commitments of cosigner %d do not match its nonces", nonce.SourceID)
+		}
+		return nil
+	}
+
+	n.Nonces[nonce.SourceID-1].PubKey = nonce.PubKey
+
+	return nil
+}
+
 // setNonce stores a nonce provided by another cosigner
 func (cosigner *LocalCosigner) setNonce(uuid uuid.UUID, nonce CosignerNonce) error {
+	if cosigner.frost() {
+		return cosigner.setFROSTNonce(uuid, nonce)
+	}
+
 	// Verify the source signature
 	if nonce.Signature == nil {
 		return errors.New("signature field is required")
 	}
 
 	noncePub, nonceShare, err := cosigner.security.DecryptAndVerify(
-		nonce.SourceID, nonce.PubKey, nonce.Share, nonce.Signature)
+		nonce.SourceID, nonce.PubKey, nonce.Share, nonce.Commitments, nonce.Signature)
 	if err != nil {
 		return err
 	}
 
+	// Refuse a nonce share that does not match the commitments of the source, so that the leader
+	// can attribute an invalid partial signature to the cosigner that produced it.
+	if len(nonce.Commitments) > 0 {
+		if !bytes.Equal(nonce.Commitments[0], noncePub) {
+			return fmt.Errorf("nonce commitments from cosigner %d do not match the nonce public key", nonce.SourceID)
+		}
+		if err := verifyVSSShare(cosigner.GetID(), nonceShare, nonce.Commitments); err != nil {
+			return fmt.Errorf("invalid nonce share from cosigner %d: %w", nonce.SourceID, err)
+		}
+	}
+
 	// protects the meta map
 	cosigner.noncesMu.Lock()
 	defer cosigner.noncesMu.Unlock()
@@ -515,6 +864,15 @@ func (cosigner *LocalCosigner) SetNoncesAndSign(
 		return nil, err
 	}
 
+	if cosigner.frost() {
+		// the commitments of all participants must include our own,
+		// so that all participants sign for the same group commitment.
+		if !req.Nonces.hasSource(cosigner.GetID()) ||
+			(req.VoteExtensionNonces != nil && !req.VoteExtensionNonces.hasSource(cosigner.GetID())) {
+			return nil, fmt.Errorf("nonce commitments do not include cosigner %d", cosigner.GetID())
+		}
+	}
+
 	var eg errgroup.Group
 
 	// setting nonces requires decrypting and verifying signature from each cosigner,
@@ -546,11 +904,13 @@ func (cosigner *LocalCosigner) SetNoncesAndSign(
 		UUID:      req.Nonces.UUID,
 		ChainID:   chainID,
 		SignBytes: req.SignBytes,
+		Nonces:    req.Nonces.Nonces,
 	}
 
 	if len(req.VoteExtensionSignBytes) > 0 {
 		cosignerReq.VoteExtensionSignBytes = req.VoteExtensionSignBytes
 		cosignerReq.VoteExtUUID = req.VoteExtensionNonces.UUID
+		cosignerReq.VoteExtensionNonces = req.VoteExtensionNonces.Nonces
 	}
 
 	res, err := cosigner.sign(cosignerReq)
@@ -226,7 +226,7 @@ func testLocalCosignerSign(t *testing.T, threshold, total uint8, security []Cosi
 		}
 	}
 
-	combinedSig, err := thresholdCosigners[0].CombineSignatures(testChainID, sigs)
+	combinedSig, err := thresholdCosigners[0].CombineSignatures(testChainID, vote.Height, sigs)
 	require.NoError(t, err)
 
 	require.True(t, pubKey.VerifySignature(signBytes, combinedSig))
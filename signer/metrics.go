@@ -274,6 +274,78 @@ var (
 		Help: "Total Times Combined Signature is Invalid",
 	})
 
+	totalInvalidPartialSignatures = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_invalid_partial_signatures",
+			Help: "Total Times a Cosigner Partial Signature is Invalid",
+		},
+		[]string{"chain_id", "shard_id"},
+	)
+
+	totalUnauthorizedCosignerRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_unauthorized_cosigner_requests",
+			Help: "Total Times a Cosigner gRPC Request is not Authenticated or not Authorized",
+		},
+		[]string{"method"},
+	)
+
+	totalSlashingProtectionRefusals = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_slashing_protection_refusals",
+			Help: "Total Times the Slashing Protection Database Refused to Sign",
+		},
+		[]string{"chain_id", "signer"},
+	)
+
+	totalRejectedChainRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_rejected_chain_requests",
+			Help: "Total Times a Request is Rejected as its Chain ID is not Allowed",
+		},
+		[]string{"chain_id", "node"},
+	)
+
+	totalSentryQuorumTimeouts = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_sentry_quorum_timeouts",
+			Help: "Total Times a Vote is not Signed as not Enough Sentries Requested it before the Timeout",
+		},
+		[]string{"chain_id"},
+	)
+
+	totalRejectedSentryHandshakes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_rejected_sentry_handshakes",
+			Help: "Total Times a Connection to a Chain Node is Dropped as its Node ID does not Match",
+		},
+		[]string{"node"},
+	)
+
+	totalConflictingSentryRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_conflicting_sentry_requests",
+			Help: "Total Times a Sentry Requests a Vote for a Different Block than Another Sentry at the Same HRS",
+		},
+		[]string{"chain_id", "sentry"},
+	)
+
+	totalQuarantinedSentryRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_error_total_quarantined_sentry_requests",
+			Help: "Total Times a Request is Rejected as its Sentry is Quarantined",
+		},
+		[]string{"sentry"},
+	)
+
+	totalCoalescedSignRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "signer_total_coalesced_sign_requests",
+			Help: "Total Times a Sign Request is Answered by an Identical Request in Flight",
+		},
+		[]string{"chain_id"},
+	)
+
 	totalInsufficientCosigners = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "signer_error_total_insufficient_cosigners",
 		Help: "Total Times Cosigners doesn't reach threshold",
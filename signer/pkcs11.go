@@ -0,0 +1,241 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+)
+
+// pkcs11Application is the application of the data objects that hold key shards in a PKCS#11 token.
+const pkcs11Application = "horcrux"
+
+// pkcs11Module is a loaded and initialized PKCS#11 module, shared by all shard stores that use it.
+// A module is initialized once per process, so it is only finalized when the last shard store is closed.
+type pkcs11Module struct {
+	ctx  *pkcs11.Ctx
+	refs int
+}
+
+var (
+	pkcs11ModulesMu sync.Mutex
+	pkcs11Modules   = make(map[string]*pkcs11Module)
+)
+
+// acquirePKCS11Module loads and initializes the PKCS#11 module at path, or returns the module if it is loaded.
+func acquirePKCS11Module(path string) (*pkcs11.Ctx, error) {
+	pkcs11ModulesMu.Lock()
+	defer pkcs11ModulesMu.Unlock()
+
+	if m, ok := pkcs11Modules[path]; ok {
+		m.refs++
+		return m.ctx, nil
+	}
+
+	ctx := pkcs11.New(path)
+	if ctx == nil {
+		return nil, fmt.Errorf("failed to load PKCS#11 module: %s", path)
+	}
+
+	if err := ctx.Initialize(); err != nil && !errors.Is(err, pkcs11.Error(pkcs11.CKR_CRYPTOKI_ALREADY_INITIALIZED)) {
+		ctx.Destroy()
+		return nil, fmt.Errorf("failed to initialize PKCS#11 module: %w", err)
+	}
+
+	pkcs11Modules[path] = &pkcs11Module{ctx: ctx, refs: 1}
+	return ctx, nil
+}
+
+// releasePKCS11Module finalizes and unloads the PKCS#11 module at path when it is no longer used.
+func releasePKCS11Module(path string) error {
+	pkcs11ModulesMu.Lock()
+	defer pkcs11ModulesMu.Unlock()
+
+	m, ok := pkcs11Modules[path]
+	if !ok {
+		return nil
+	}
+	m.refs--
+	if m.refs > 0 {
+		return nil
+	}
+	delete(pkcs11Modules, path)
+
+	err := m.ctx.Finalize()
+	m.ctx.Destroy()
+	return err
+}
+
+// PKCS11ShardStore is PIN-protected storage for key shards in a PKCS#11 token, such as an HSM.
+// The key shards are private data objects, which can only be read after logging in with the user PIN.
+//
+// It is not hardware-backed signing: PKCS#11 has no vendor-neutral mechanism for the scalar arithmetic of
+// threshold signatures, so nothing is computed inside the token. The key shards are read into memory
+// and sign in horcrux, so the token only protects them at rest.
+// The session is not safe for concurrent use, so all operations are serialized.
+type PKCS11ShardStore struct {
+	modulePath string
+	ctx        *pkcs11.Ctx
+	session    pkcs11.SessionHandle
+	mu         sync.Mutex
+}
+
+// OpenPKCS11ShardStore loads the PKCS#11 module of the token and logs in to the slot with the user PIN.
+func OpenPKCS11ShardStore(cfg *PKCS11ShardStorageConfig) (*PKCS11ShardStore, error) {
+	pin, err := cfg.PIN()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, err := acquirePKCS11Module(cfg.ModulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := openPKCS11Session(ctx, cfg.Slot, pin)
+	if err != nil {
+		_ = releasePKCS11Module(cfg.ModulePath)
+		return nil, err
+	}
+	store.modulePath = cfg.ModulePath
+
+	return store, nil
+}
+
+func openPKCS11Session(ctx *pkcs11.Ctx, slot uint, pin string) (*PKCS11ShardStore, error) {
+	// key shards are only protected by the token if they can not be read without the PIN.
+	info, err := ctx.GetTokenInfo(slot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get info of PKCS#11 token on slot %d: %w", slot, err)
+	}
+	if info.Flags&pkcs11.CKF_LOGIN_REQUIRED == 0 {
+		return nil, fmt.Errorf("PKCS#11 token on slot %d does not require logging in, refusing to use it", slot)
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PKCS#11 session on slot %d: %w", slot, err)
+	}
+
+	if err := ctx.Login(session, pkcs11.CKU_USER, pin); err != nil &&
+		!errors.Is(err, pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN)) {
+		_ = ctx.CloseSession(session)
+		return nil, fmt.Errorf("failed to log in to PKCS#11 token on slot %d: %w", slot, err)
+	}
+
+	return &PKCS11ShardStore{
+		ctx:     ctx,
+		session: session,
+	}, nil
+}
+
+// Close closes the session with the token, and finalizes and unloads the PKCS#11 module
+// if no other shard store uses it.
+func (t *PKCS11ShardStore) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return errors.Join(t.ctx.CloseSession(t.session), releasePKCS11Module(t.modulePath))
+}
+
+func (t *PKCS11ShardStore) findObject(label string) (pkcs11.ObjectHandle, bool, error) {
+	if err := t.ctx.FindObjectsInit(t.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_DATA),
+		pkcs11.NewAttribute(pkcs11.CKA_APPLICATION, pkcs11Application),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+	}); err != nil {
+		return 0, false, err
+	}
+
+	objects, _, err := t.ctx.FindObjects(t.session, 2)
+	if finalErr := t.ctx.FindObjectsFinal(t.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	switch len(objects) {
+	case 0:
+		return 0, false, nil
+	case 1:
+		return objects[0], true, nil
+	default:
+		return 0, false, fmt.Errorf("multiple key shard objects with label %s", label)
+	}
+}
+
+// ReadCosignerKey reads the key shard with the label from the token, of the key type of the key shard.
+// Key shard objects that can be read without logging in to the token are refused.
+func (t *PKCS11ShardStore) ReadCosignerKey(label string) (CosignerKey, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	object, ok, err := t.findObject(label)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("no key shard object with label %s in PKCS#11 token", label)
+	}
+
+	attrs, err := t.ctx.GetAttributeValue(t.session, object, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, nil),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	value := attrs[1].Value
+	defer zeroBytes(value)
+
+	if !bytes.Equal(attrs[0].Value, []byte{pkcs11.CK_TRUE}) {
+		return nil, fmt.Errorf(
+			"key shard object with label %s can be read without logging in to PKCS#11 token, refusing to use it", label)
+	}
+
+	key, err := unmarshalCosignerKey(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key shard object with label %s: %w", label, err)
+	}
+
+	return key, nil
+}
+
+// WriteCosignerKey stores the key shard in the token as a private data object with the label.
+func (t *PKCS11ShardStore) WriteCosignerKey(label string, key CosignerKey) error {
+	value, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	defer zeroBytes(value)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok, err := t.findObject(label); err != nil {
+		return err
+	} else if ok {
+		return fmt.Errorf("key shard object with label %s already exists in PKCS#11 token", label)
+	}
+
+	_, err = t.ctx.CreateObject(t.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_DATA),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_MODIFIABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_APPLICATION, pkcs11Application),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, label),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, value),
+	})
+	return err
+}
+
+func zeroBytes(bz []byte) {
+	for i := range bz {
+		bz[i] = 0
+	}
+}
@@ -0,0 +1,194 @@
+package signer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometprivval "github.com/cometbft/cometbft/privval"
+	"github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testPKCS11SOPIN   = "1234"
+	testPKCS11UserPIN = "5678"
+)
+
+// envRequireSoftHSM fails the PKCS#11 tests instead of skipping them if SoftHSMv2 is not installed,
+// so that CI does not pass without running them.
+const envRequireSoftHSM = "HORCRUX_REQUIRE_SOFTHSM"
+
+// softHSMModule returns the path of the SoftHSMv2 module, or skips the test if it is not installed.
+// The test fails instead if HORCRUX_REQUIRE_SOFTHSM is set.
+func softHSMModule(t *testing.T) string {
+	candidates := []string{
+		os.Getenv("SOFTHSM2_MODULE"),
+		"/usr/lib/softhsm/libsofthsm2.so",
+		"/usr/local/lib/softhsm/libsofthsm2.so",
+		"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+		"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+	}
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		if _, err := os.Stat(c); err == nil {
+			return c
+		}
+	}
+	if os.Getenv(envRequireSoftHSM) != "" {
+		t.Fatalf("SoftHSMv2 is not installed but %s is set, set SOFTHSM2_MODULE to the path of libsofthsm2.so", envRequireSoftHSM)
+	}
+	t.Skip("SoftHSMv2 is not installed, set SOFTHSM2_MODULE to the path of libsofthsm2.so")
+	return ""
+}
+
+// initSoftHSMToken initializes a SoftHSMv2 token in a temporary directory and returns its slot ID.
+func initSoftHSMToken(t *testing.T, module string) uint {
+	tokenDir := t.TempDir()
+	conf := filepath.Join(tokenDir, "softhsm2.conf")
+	require.NoError(t, os.WriteFile(conf, []byte(fmt.Sprintf(
+		"directories.tokendir = %s\nobjectstore.backend = file\nlog.level = ERROR\n", tokenDir,
+	)), 0600))
+	t.Setenv("SOFTHSM2_CONF", conf)
+
+	ctx := pkcs11.New(module)
+	require.NotNil(t, ctx)
+	require.NoError(t, ctx.Initialize())
+
+	slots, err := ctx.GetSlotList(false)
+	require.NoError(t, err)
+	require.NotEmpty(t, slots)
+	require.NoError(t, ctx.InitToken(slots[0], testPKCS11SOPIN, "horcrux"))
+
+	// SoftHSMv2 moves an initialized token to a new slot
+	slots, err = ctx.GetSlotList(true)
+	require.NoError(t, err)
+	var slot uint
+	for _, s := range slots {
+		info, err := ctx.GetTokenInfo(s)
+		require.NoError(t, err)
+		if info.Label == "horcrux" {
+			slot = s
+		}
+	}
+
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Login(session, pkcs11.CKU_SO, testPKCS11SOPIN))
+	require.NoError(t, ctx.InitPIN(session, testPKCS11UserPIN))
+	require.NoError(t, ctx.Logout(session))
+	require.NoError(t, ctx.CloseSession(session))
+
+	return slot
+}
+
+func TestThresholdSignerKeyFromPKCS11ShardStore(t *testing.T) {
+	module := softHSMModule(t)
+	slot := initSoftHSMToken(t, module)
+	t.Setenv(envPKCS11PIN, testPKCS11UserPIN)
+
+	const threshold, total = 2, 3
+
+	privateKey := cometcryptoed25519.GenPrivKey()
+	keys := CreateCosignerEd25519Shards(cometprivval.FilePVKey{
+		Address: privateKey.PubKey().Address(),
+		PubKey:  privateKey.PubKey(),
+		PrivKey: privateKey,
+	}, threshold, total)
+
+	config := &RuntimeConfig{
+		Config: Config{
+			ThresholdModeConfig: &ThresholdModeConfig{
+				Threshold: threshold,
+				Cosigners: CosignersConfig{{ShardID: 1}, {ShardID: 2}, {ShardID: 3}},
+				PKCS11ShardStorage: &PKCS11ShardStorageConfig{
+					ModulePath: module,
+					Slot:       slot,
+				},
+			},
+		},
+	}
+
+	store, err := OpenPKCS11ShardStore(config.Config.ThresholdModeConfig.PKCS11ShardStorage)
+	require.NoError(t, err)
+	label := config.Config.ThresholdModeConfig.PKCS11ShardStorage.KeyLabelFor(testChainID)
+	require.NoError(t, store.WriteCosignerKey(label, &keys[0]))
+	require.Error(t, store.WriteCosignerKey(label, &keys[0]))
+
+	// the module stays initialized until the last shard store that uses it is closed
+	store2, err := OpenPKCS11ShardStore(config.Config.ThresholdModeConfig.PKCS11ShardStorage)
+	require.NoError(t, err)
+	require.NoError(t, store2.Close())
+	_, err = store.ReadCosignerKey(label)
+	require.NoError(t, err)
+
+	// a key shard object that can be read without logging in is refused
+	value, err := keys[0].MarshalJSON()
+	require.NoError(t, err)
+	_, err = store.ctx.CreateObject(store.session, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_DATA),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_APPLICATION, pkcs11Application),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, "public_shard"),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE, value),
+	})
+	require.NoError(t, err)
+	_, err = store.ReadCosignerKey("public_shard")
+	require.ErrorContains(t, err, "without logging in")
+
+	require.NoError(t, store.Close())
+
+	_, err = NewThresholdSigner(config, 1, testChainID2)
+	require.Error(t, err)
+
+	_, err = NewThresholdSigner(config, 2, testChainID)
+	require.Error(t, err)
+
+	hsmSigner, err := NewThresholdSigner(config, 1, testChainID)
+	require.NoError(t, err)
+	require.Equal(t, privateKey.PubKey().Bytes(), hsmSigner.PubKey())
+
+	signers := []ThresholdSigner{hsmSigner, newThresholdSignerSoft(config, keys[1])}
+
+	allNonces := make([]Nonces, len(signers))
+	uuidNonces := &CosignerUUIDNonces{}
+	for i := range signers {
+		allNonces[i], err = GenerateNonces(threshold, total)
+		require.NoError(t, err)
+		uuidNonces.Nonces = append(uuidNonces.Nonces, CosignerNonce{
+			SourceID:      i + 1,
+			DestinationID: 2 - i,
+			PubKey:        allNonces[i].PubKey,
+			Commitments:   allNonces[i].Commitments,
+		})
+	}
+
+	msg := []byte("hello PKCS#11")
+
+	sigs := make([]PartialSignature, len(signers))
+	for i, s := range signers {
+		nonces := make([]Nonce, len(allNonces))
+		for j, n := range allNonces {
+			nonces[j] = Nonce{
+				ID:     j + 1,
+				Share:  n.Shares[i],
+				PubKey: n.PubKey,
+			}
+		}
+
+		sig, err := s.Sign(nonces, msg)
+		require.NoError(t, err)
+
+		sigs[i] = PartialSignature{ID: i + 1, Signature: sig}
+		require.NoError(t, hsmSigner.VerifyPartialSignature(msg, uuidNonces, sigs[i]))
+	}
+
+	combined, err := hsmSigner.CombineSignatures(sigs)
+	require.NoError(t, err)
+	require.True(t, privateKey.PubKey().VerifySignature(msg, combined))
+}
@@ -114,6 +114,7 @@ func (m *Block) GetTimestamp() int64 {
 type SignBlockRequest struct {
 	ChainID string `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
 	Block   *Block `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+	Sentry  string `protobuf:"bytes,3,opt,name=sentry,proto3" json:"sentry,omitempty"`
 }
 
 func (m *SignBlockRequest) Reset()         { *m = SignBlockRequest{} }
@@ -163,6 +164,13 @@ func (m *SignBlockRequest) GetBlock() *Block {
 	return nil
 }
 
+func (m *SignBlockRequest) GetSentry() string {
+	if m != nil {
+		return m.Sentry
+	}
+	return ""
+}
+
 type SignBlockResponse struct {
 	Signature        []byte `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
 	VoteExtSignature []byte `protobuf:"bytes,2,opt,name=vote_ext_signature,json=voteExtSignature,proto3" json:"vote_ext_signature,omitempty"`
@@ -224,11 +232,12 @@ func (m *SignBlockResponse) GetTimestamp() int64 {
 }
 
 type Nonce struct {
-	SourceID      int32  `protobuf:"varint,1,opt,name=sourceID,proto3" json:"sourceID,omitempty"`
-	DestinationID int32  `protobuf:"varint,2,opt,name=destinationID,proto3" json:"destinationID,omitempty"`
-	PubKey        []byte `protobuf:"bytes,3,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
-	Share         []byte `protobuf:"bytes,4,opt,name=share,proto3" json:"share,omitempty"`
-	Signature     []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	SourceID      int32    `protobuf:"varint,1,opt,name=sourceID,proto3" json:"sourceID,omitempty"`
+	DestinationID int32    `protobuf:"varint,2,opt,name=destinationID,proto3" json:"destinationID,omitempty"`
+	PubKey        []byte   `protobuf:"bytes,3,opt,name=pubKey,proto3" json:"pubKey,omitempty"`
+	Share         []byte   `protobuf:"bytes,4,opt,name=share,proto3" json:"share,omitempty"`
+	Signature     []byte   `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	Commitments   [][]byte `protobuf:"bytes,6,rep,name=commitments,proto3" json:"commitments,omitempty"`
 }
 
 func (m *Nonce) Reset()         { *m = Nonce{} }
@@ -299,6 +308,13 @@ func (m *Nonce) GetSignature() []byte {
 	return nil
 }
 
+func (m *Nonce) GetCommitments() [][]byte {
+	if m != nil {
+		return m.Commitments
+	}
+	return nil
+}
+
 type UUIDNonce struct {
 	Uuid   []byte   `protobuf:"bytes,1,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	Nonces []*Nonce `protobuf:"bytes,2,rep,name=nonces,proto3" json:"nonces,omitempty"`
@@ -520,11 +536,14 @@ func (m *SetNoncesAndSignRequest) GetChainID() string {
 }
 
 type SetNoncesAndSignResponse struct {
-	Timestamp          int64  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	NoncePublic        []byte `protobuf:"bytes,2,opt,name=noncePublic,proto3" json:"noncePublic,omitempty"`
-	Signature          []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
-	VoteExtNoncePublic []byte `protobuf:"bytes,4,opt,name=voteExtNoncePublic,proto3" json:"voteExtNoncePublic,omitempty"`
-	VoteExtSignature   []byte `protobuf:"bytes,5,opt,name=voteExtSignature,proto3" json:"voteExtSignature,omitempty"`
+	Timestamp          int64    `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	NoncePublic        []byte   `protobuf:"bytes,2,opt,name=noncePublic,proto3" json:"noncePublic,omitempty"`
+	Signature          []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	VoteExtNoncePublic []byte   `protobuf:"bytes,4,opt,name=voteExtNoncePublic,proto3" json:"voteExtNoncePublic,omitempty"`
+	VoteExtSignature   []byte   `protobuf:"bytes,5,opt,name=voteExtSignature,proto3" json:"voteExtSignature,omitempty"`
+	Existing           bool     `protobuf:"varint,6,opt,name=existing,proto3" json:"existing,omitempty"`
+	Nonces             []*Nonce `protobuf:"bytes,7,rep,name=nonces,proto3" json:"nonces,omitempty"`
+	VoteExtNonces      []*Nonce `protobuf:"bytes,8,rep,name=voteExtNonces,proto3" json:"voteExtNonces,omitempty"`
 }
 
 func (m *SetNoncesAndSignResponse) Reset()         { *m = SetNoncesAndSignResponse{} }
@@ -595,6 +614,27 @@ func (m *SetNoncesAndSignResponse) GetVoteExtSignature() []byte {
 	return nil
 }
 
+func (m *SetNoncesAndSignResponse) GetExisting() bool {
+	if m != nil {
+		return m.Existing
+	}
+	return false
+}
+
+func (m *SetNoncesAndSignResponse) GetNonces() []*Nonce {
+	if m != nil {
+		return m.Nonces
+	}
+	return nil
+}
+
+func (m *SetNoncesAndSignResponse) GetVoteExtNonces() []*Nonce {
+	if m != nil {
+		return m.VoteExtNonces
+	}
+	return nil
+}
+
 type GetNoncesRequest struct {
 	Uuids [][]byte `protobuf:"bytes,1,rep,name=uuids,proto3" json:"uuids,omitempty"`
 }
@@ -860,6 +900,7 @@ func (m *GetLeaderResponse) GetLeader() int32 {
 }
 
 type PingRequest struct {
+	Nonce []byte `protobuf:"bytes,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
 }
 
 func (m *PingRequest) Reset()         { *m = PingRequest{} }
@@ -895,7 +936,16 @@ func (m *PingRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PingRequest proto.InternalMessageInfo
 
+func (m *PingRequest) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
 type PingResponse struct {
+	SecuritySchemes []string `protobuf:"bytes,1,rep,name=securitySchemes,proto3" json:"securitySchemes,omitempty"`
+	Signatures      [][]byte `protobuf:"bytes,2,rep,name=signatures,proto3" json:"signatures,omitempty"`
 }
 
 func (m *PingResponse) Reset()         { *m = PingResponse{} }
@@ -931,6 +981,324 @@ func (m *PingResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PingResponse proto.InternalMessageInfo
 
+func (m *PingResponse) GetSecuritySchemes() []string {
+	if m != nil {
+		return m.SecuritySchemes
+	}
+	return nil
+}
+
+func (m *PingResponse) GetSignatures() [][]byte {
+	if m != nil {
+		return m.Signatures
+	}
+	return nil
+}
+
+type DealRequest struct {
+	Session       string   `protobuf:"bytes,1,opt,name=session,proto3" json:"session,omitempty"`
+	SourceID      int32    `protobuf:"varint,2,opt,name=sourceID,proto3" json:"sourceID,omitempty"`
+	DestinationID int32    `protobuf:"varint,3,opt,name=destinationID,proto3" json:"destinationID,omitempty"`
+	Commitments   [][]byte `protobuf:"bytes,4,rep,name=commitments,proto3" json:"commitments,omitempty"`
+	Share         []byte   `protobuf:"bytes,5,opt,name=share,proto3" json:"share,omitempty"`
+	Signature     []byte   `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *DealRequest) Reset()         { *m = DealRequest{} }
+func (m *DealRequest) String() string { return proto.CompactTextString(m) }
+func (*DealRequest) ProtoMessage()    {}
+func (*DealRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b7a1f695b94b848a, []int{16}
+}
+func (m *DealRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DealRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DealRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DealRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DealRequest.Merge(m, src)
+}
+func (m *DealRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *DealRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DealRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DealRequest proto.InternalMessageInfo
+
+func (m *DealRequest) GetSession() string {
+	if m != nil {
+		return m.Session
+	}
+	return ""
+}
+
+func (m *DealRequest) GetSourceID() int32 {
+	if m != nil {
+		return m.SourceID
+	}
+	return 0
+}
+
+func (m *DealRequest) GetDestinationID() int32 {
+	if m != nil {
+		return m.DestinationID
+	}
+	return 0
+}
+
+func (m *DealRequest) GetCommitments() [][]byte {
+	if m != nil {
+		return m.Commitments
+	}
+	return nil
+}
+
+func (m *DealRequest) GetShare() []byte {
+	if m != nil {
+		return m.Share
+	}
+	return nil
+}
+
+func (m *DealRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type DealResponse struct {
+}
+
+func (m *DealResponse) Reset()         { *m = DealResponse{} }
+func (m *DealResponse) String() string { return proto.CompactTextString(m) }
+func (*DealResponse) ProtoMessage()    {}
+func (*DealResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b7a1f695b94b848a, []int{17}
+}
+func (m *DealResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *DealResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_DealResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *DealResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DealResponse.Merge(m, src)
+}
+func (m *DealResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *DealResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DealResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DealResponse proto.InternalMessageInfo
+
+type SetHaltHeightRequest struct {
+	ChainID    string `protobuf:"bytes,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	HaltHeight int64  `protobuf:"varint,2,opt,name=haltHeight,proto3" json:"haltHeight,omitempty"`
+	Lift       bool   `protobuf:"varint,3,opt,name=lift,proto3" json:"lift,omitempty"`
+}
+
+func (m *SetHaltHeightRequest) Reset()         { *m = SetHaltHeightRequest{} }
+func (m *SetHaltHeightRequest) String() string { return proto.CompactTextString(m) }
+func (*SetHaltHeightRequest) ProtoMessage()    {}
+func (*SetHaltHeightRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b7a1f695b94b848a, []int{18}
+}
+func (m *SetHaltHeightRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SetHaltHeightRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SetHaltHeightRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SetHaltHeightRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetHaltHeightRequest.Merge(m, src)
+}
+func (m *SetHaltHeightRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *SetHaltHeightRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetHaltHeightRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetHaltHeightRequest proto.InternalMessageInfo
+
+func (m *SetHaltHeightRequest) GetChainID() string {
+	if m != nil {
+		return m.ChainID
+	}
+	return ""
+}
+
+func (m *SetHaltHeightRequest) GetHaltHeight() int64 {
+	if m != nil {
+		return m.HaltHeight
+	}
+	return 0
+}
+
+func (m *SetHaltHeightRequest) GetLift() bool {
+	if m != nil {
+		return m.Lift
+	}
+	return false
+}
+
+type SetHaltHeightResponse struct {
+	HaltHeight int64 `protobuf:"varint,1,opt,name=haltHeight,proto3" json:"haltHeight,omitempty"`
+}
+
+func (m *SetHaltHeightResponse) Reset()         { *m = SetHaltHeightResponse{} }
+func (m *SetHaltHeightResponse) String() string { return proto.CompactTextString(m) }
+func (*SetHaltHeightResponse) ProtoMessage()    {}
+func (*SetHaltHeightResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b7a1f695b94b848a, []int{19}
+}
+func (m *SetHaltHeightResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SetHaltHeightResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SetHaltHeightResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SetHaltHeightResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetHaltHeightResponse.Merge(m, src)
+}
+func (m *SetHaltHeightResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *SetHaltHeightResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetHaltHeightResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetHaltHeightResponse proto.InternalMessageInfo
+
+func (m *SetHaltHeightResponse) GetHaltHeight() int64 {
+	if m != nil {
+		return m.HaltHeight
+	}
+	return 0
+}
+
+type ClearSentryQuarantineRequest struct {
+	Sentry string `protobuf:"bytes,1,opt,name=sentry,proto3" json:"sentry,omitempty"`
+}
+
+func (m *ClearSentryQuarantineRequest) Reset()         { *m = ClearSentryQuarantineRequest{} }
+func (m *ClearSentryQuarantineRequest) String() string { return proto.CompactTextString(m) }
+func (*ClearSentryQuarantineRequest) ProtoMessage()    {}
+func (*ClearSentryQuarantineRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b7a1f695b94b848a, []int{20}
+}
+func (m *ClearSentryQuarantineRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ClearSentryQuarantineRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ClearSentryQuarantineRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ClearSentryQuarantineRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClearSentryQuarantineRequest.Merge(m, src)
+}
+func (m *ClearSentryQuarantineRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ClearSentryQuarantineRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClearSentryQuarantineRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClearSentryQuarantineRequest proto.InternalMessageInfo
+
+func (m *ClearSentryQuarantineRequest) GetSentry() string {
+	if m != nil {
+		return m.Sentry
+	}
+	return ""
+}
+
+type ClearSentryQuarantineResponse struct {
+}
+
+func (m *ClearSentryQuarantineResponse) Reset()         { *m = ClearSentryQuarantineResponse{} }
+func (m *ClearSentryQuarantineResponse) String() string { return proto.CompactTextString(m) }
+func (*ClearSentryQuarantineResponse) ProtoMessage()    {}
+func (*ClearSentryQuarantineResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b7a1f695b94b848a, []int{21}
+}
+func (m *ClearSentryQuarantineResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ClearSentryQuarantineResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ClearSentryQuarantineResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ClearSentryQuarantineResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClearSentryQuarantineResponse.Merge(m, src)
+}
+func (m *ClearSentryQuarantineResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ClearSentryQuarantineResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClearSentryQuarantineResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClearSentryQuarantineResponse proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*Block)(nil), "strangelove.horcrux.Block")
 	proto.RegisterType((*SignBlockRequest)(nil), "strangelove.horcrux.SignBlockRequest")
@@ -948,6 +1316,12 @@ func init() {
 	proto.RegisterType((*GetLeaderResponse)(nil), "strangelove.horcrux.GetLeaderResponse")
 	proto.RegisterType((*PingRequest)(nil), "strangelove.horcrux.PingRequest")
 	proto.RegisterType((*PingResponse)(nil), "strangelove.horcrux.PingResponse")
+	proto.RegisterType((*DealRequest)(nil), "strangelove.horcrux.DealRequest")
+	proto.RegisterType((*DealResponse)(nil), "strangelove.horcrux.DealResponse")
+	proto.RegisterType((*SetHaltHeightRequest)(nil), "strangelove.horcrux.SetHaltHeightRequest")
+	proto.RegisterType((*SetHaltHeightResponse)(nil), "strangelove.horcrux.SetHaltHeightResponse")
+	proto.RegisterType((*ClearSentryQuarantineRequest)(nil), "strangelove.horcrux.ClearSentryQuarantineRequest")
+	proto.RegisterType((*ClearSentryQuarantineResponse)(nil), "strangelove.horcrux.ClearSentryQuarantineResponse")
 }
 
 func init() {
@@ -955,60 +1329,77 @@ func init() {
 }
 
 var fileDescriptor_b7a1f695b94b848a = []byte{
-	// 841 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x4f, 0x6f, 0xe3, 0x54,
-	0x10, 0x8f, 0x13, 0x3b, 0x9b, 0x4c, 0x5a, 0x94, 0x7d, 0xac, 0x16, 0xaf, 0x85, 0xa2, 0xf0, 0x04,
-	0x28, 0x82, 0x6d, 0x82, 0xb2, 0x12, 0xbd, 0xd2, 0x52, 0x04, 0x55, 0x01, 0x15, 0xa7, 0xbd, 0xa0,
-	0xaa, 0x95, 0xe3, 0xbc, 0xc6, 0x16, 0xa9, 0x9d, 0xfa, 0x3d, 0x87, 0xf6, 0xc0, 0x77, 0xe0, 0xc2,
-	0x07, 0xe1, 0x5b, 0x70, 0xec, 0x81, 0x43, 0x6f, 0xa0, 0xf6, 0x8b, 0xac, 0xde, 0x1f, 0x3b, 0xb6,
-	0xe3, 0xb4, 0x3d, 0xf4, 0x14, 0xcf, 0xf8, 0x37, 0x6f, 0xe6, 0x37, 0xf3, 0x9b, 0x17, 0x03, 0xa6,
-	0x2c, 0x72, 0x82, 0x29, 0x99, 0x85, 0x0b, 0x32, 0xf0, 0xc2, 0xc8, 0x8d, 0xe2, 0xab, 0x81, 0x1b,
-	0x52, 0x7f, 0x1a, 0x90, 0xa8, 0x3f, 0x8f, 0x42, 0x16, 0xa2, 0x0f, 0x33, 0x98, 0xbe, 0xc2, 0xe0,
-	0xbf, 0x35, 0x30, 0x76, 0x67, 0xa1, 0xfb, 0x1b, 0x7a, 0x0d, 0x75, 0x8f, 0xf8, 0x53, 0x8f, 0x99,
-	0x5a, 0x57, 0xeb, 0xd5, 0x6c, 0x65, 0xa1, 0x57, 0x60, 0x44, 0x61, 0x1c, 0x4c, 0xcc, 0xaa, 0x70,
-	0x4b, 0x03, 0x21, 0xd0, 0x29, 0x23, 0x73, 0xb3, 0xd6, 0xd5, 0x7a, 0x86, 0x2d, 0x9e, 0xd1, 0xc7,
-	0xd0, 0xe4, 0x09, 0x77, 0xaf, 0x19, 0xa1, 0xa6, 0xde, 0xd5, 0x7a, 0x1b, 0xf6, 0xd2, 0x81, 0xbe,
-	0x80, 0xf6, 0x22, 0x64, 0xe4, 0xbb, 0x2b, 0x36, 0x4a, 0x41, 0x86, 0x00, 0xad, 0xf8, 0xf9, 0x49,
-	0xcc, 0xbf, 0x20, 0x94, 0x39, 0x17, 0x73, 0xb3, 0x2e, 0xf2, 0x2e, 0x1d, 0xf8, 0x14, 0xda, 0x02,
-	0xca, 0xcb, 0xb6, 0xc9, 0x65, 0x4c, 0x28, 0x43, 0x26, 0xbc, 0x70, 0x3d, 0xc7, 0x0f, 0xf6, 0xf7,
-	0x44, 0xf9, 0x4d, 0x3b, 0x31, 0xd1, 0x57, 0x60, 0x8c, 0x39, 0x52, 0xd4, 0xdf, 0x1a, 0x5a, 0xfd,
-	0x92, 0x36, 0xf4, 0xe5, 0x59, 0x12, 0x88, 0xff, 0x80, 0x97, 0x99, 0xf3, 0xe9, 0x3c, 0x0c, 0x28,
-	0x49, 0xc8, 0x39, 0x2c, 0x8e, 0x88, 0x48, 0xa1, 0xc8, 0x09, 0x07, 0x7a, 0x0b, 0x88, 0x93, 0x38,
-	0x23, 0x57, 0xec, 0x6c, 0x09, 0xab, 0xae, 0xd0, 0x93, 0xe8, 0x1c, 0xbd, 0x5a, 0x91, 0xde, 0x5f,
-	0x1a, 0x18, 0x3f, 0x87, 0x81, 0x4b, 0x90, 0x05, 0x0d, 0x1a, 0xc6, 0x91, 0x4b, 0x14, 0x2b, 0xc3,
-	0x4e, 0x6d, 0xf4, 0x29, 0x6c, 0x4e, 0x08, 0x65, 0x7e, 0xe0, 0x30, 0x3f, 0xe4, 0xb4, 0xab, 0x02,
-	0x90, 0x77, 0xf2, 0xa1, 0xce, 0xe3, 0xf1, 0x01, 0xb9, 0x16, 0x69, 0x36, 0x6c, 0x65, 0xf1, 0xa1,
-	0x52, 0xcf, 0x89, 0x88, 0x1a, 0x93, 0x34, 0xf2, 0x1c, 0x8d, 0x02, 0x47, 0x3c, 0x82, 0xe6, 0xf1,
-	0xf1, 0xfe, 0x9e, 0x2c, 0x0d, 0x81, 0x1e, 0xc7, 0xfe, 0x44, 0x75, 0x42, 0x3c, 0xa3, 0x21, 0xd4,
-	0x03, 0xfe, 0x92, 0x9a, 0xd5, 0x6e, 0x6d, 0x6d, 0xab, 0x45, 0xbc, 0xad, 0x90, 0xf8, 0x1c, 0xf4,
-	0x1f, 0xec, 0xd1, 0xd1, 0xf3, 0xa8, 0x6f, 0xd9, 0x54, 0xbd, 0xd8, 0xd4, 0xdb, 0x2a, 0x7c, 0x34,
-	0x22, 0x4c, 0x24, 0xa7, 0x3b, 0xc1, 0x84, 0x0f, 0x23, 0xd1, 0xce, 0x33, 0x71, 0x41, 0x5b, 0xa0,
-	0x7b, 0x11, 0x65, 0xa2, 0xaa, 0xd6, 0xf0, 0x4d, 0x69, 0x04, 0x27, 0x6b, 0x0b, 0xd8, 0x23, 0xeb,
-	0xd2, 0x85, 0x96, 0xd2, 0xcd, 0x31, 0xaf, 0x4d, 0x4e, 0x23, 0xeb, 0x42, 0xdf, 0xc0, 0xa6, 0x32,
-	0x25, 0x2b, 0xb3, 0xfe, 0x68, 0xa5, 0xf9, 0x80, 0xd2, 0x95, 0x7c, 0xb1, 0x66, 0x25, 0x33, 0x0b,
-	0xd6, 0xc8, 0x2d, 0x18, 0xfe, 0x57, 0x03, 0x73, 0xb5, 0xb5, 0xcb, 0xb5, 0x59, 0x4e, 0x45, 0x2b,
-	0x4c, 0x85, 0x93, 0x14, 0xbd, 0x3b, 0x8c, 0xc7, 0x33, 0xdf, 0x55, 0xfb, 0x92, 0x75, 0xe5, 0x25,
-	0x59, 0x2b, 0xae, 0x5d, 0x5f, 0xae, 0x5d, 0xc2, 0x48, 0x1d, 0x23, 0x7b, 0x59, 0xf2, 0xa6, 0x40,
-	0x38, 0xab, 0xf3, 0x15, 0x3f, 0xee, 0x41, 0xfb, 0xfb, 0x84, 0x55, 0xa2, 0x94, 0x57, 0x60, 0x70,
-	0x75, 0x50, 0x53, 0xeb, 0xd6, 0xf8, 0xda, 0x08, 0x03, 0x1f, 0xc0, 0xcb, 0x0c, 0x52, 0x11, 0xff,
-	0x3a, 0x15, 0x90, 0x26, 0xc6, 0xd2, 0x29, 0x1d, 0x4b, 0xba, 0x50, 0xe9, 0x42, 0x6c, 0xc3, 0x9b,
-	0xa3, 0xc8, 0x09, 0xe8, 0x39, 0x89, 0x7e, 0x24, 0xce, 0x84, 0x44, 0xd4, 0xf3, 0xe7, 0x49, 0x7e,
-	0x0b, 0x1a, 0x33, 0xe1, 0x4c, 0xaf, 0xb9, 0xd4, 0xc6, 0xa7, 0x60, 0x95, 0x05, 0xaa, 0x72, 0x1e,
-	0x88, 0xe4, 0x57, 0x89, 0x7c, 0xde, 0x99, 0x4c, 0x22, 0x42, 0xa9, 0x98, 0x43, 0xd3, 0xce, 0x3b,
-	0x31, 0x12, 0xfd, 0x90, 0x47, 0xab, 0x7a, 0xf0, 0x97, 0x82, 0x79, 0xe2, 0x53, 0xa9, 0x5e, 0x43,
-	0x5d, 0x46, 0xaa, 0x3b, 0x4b, 0x59, 0x78, 0x13, 0x5a, 0x87, 0x7e, 0x30, 0x4d, 0x62, 0x3f, 0x80,
-	0x0d, 0x69, 0xca, 0xb0, 0xe1, 0x7f, 0x3a, 0x34, 0xbe, 0x55, 0xff, 0x58, 0xe8, 0x04, 0x9a, 0xe9,
-	0x15, 0x8c, 0x3e, 0x2b, 0x6d, 0x5d, 0xf1, 0x2f, 0xc0, 0xfa, 0xfc, 0x31, 0x98, 0x4c, 0x84, 0x2b,
-	0xe8, 0x12, 0xda, 0x45, 0xc1, 0xa2, 0xb7, 0xe5, 0xd1, 0xe5, 0x57, 0x86, 0xb5, 0xf5, 0x44, 0x74,
-	0x9a, 0xf2, 0x04, 0x9a, 0xa9, 0x46, 0xd6, 0x10, 0x2a, 0xaa, 0x6d, 0x0d, 0xa1, 0x15, 0xa9, 0xe1,
-	0x0a, 0xfa, 0x1d, 0xd0, 0xea, 0xec, 0x51, 0xbf, 0x34, 0x7e, 0xad, 0xba, 0xac, 0xc1, 0x93, 0xf1,
-	0x05, 0x5a, 0xf2, 0xd5, 0x7a, 0x5a, 0x39, 0xd1, 0xac, 0xa7, 0x95, 0xd7, 0x11, 0xae, 0xa0, 0x9f,
-	0x40, 0xe7, 0x12, 0x41, 0xdd, 0xd2, 0x88, 0x8c, 0x98, 0xac, 0x4f, 0x1e, 0x40, 0x24, 0xc7, 0xed,
-	0xfe, 0xf2, 0xcf, 0x5d, 0x47, 0xbb, 0xb9, 0xeb, 0x68, 0xff, 0xdf, 0x75, 0xb4, 0x3f, 0xef, 0x3b,
-	0x95, 0x9b, 0xfb, 0x4e, 0xe5, 0xf6, 0xbe, 0x53, 0xf9, 0x75, 0x7b, 0xea, 0x33, 0x2f, 0x1e, 0xf7,
-	0xdd, 0xf0, 0x62, 0x90, 0x39, 0x68, 0x6b, 0x41, 0x02, 0x7e, 0x17, 0xd0, 0xf4, 0x93, 0x6a, 0xf1,
-	0x6e, 0x20, 0x15, 0x3a, 0x10, 0xdf, 0x54, 0xe3, 0xba, 0xf8, 0x79, 0xf7, 0x3e, 0x00, 0x00, 0xff,
-	0xff, 0x61, 0xe1, 0x3e, 0xbf, 0x80, 0x09, 0x00, 0x00,
+	// 1112 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x57, 0x4d, 0x6f, 0xdb, 0x46,
+	0x13, 0x36, 0x25, 0x51, 0x96, 0x46, 0xf2, 0xfb, 0xda, 0x5b, 0x27, 0x65, 0x88, 0x54, 0x55, 0xd8,
+	0xd6, 0x50, 0xd3, 0x58, 0x6a, 0x15, 0x20, 0xbe, 0x36, 0x8e, 0x8b, 0x3a, 0x48, 0x5b, 0x24, 0x54,
+	0x0c, 0x14, 0x45, 0xd0, 0x80, 0xa2, 0xd6, 0x22, 0x51, 0x89, 0x54, 0xb8, 0x4b, 0xd5, 0x3e, 0x14,
+	0xe8, 0x4f, 0xe8, 0xbd, 0xbf, 0xa2, 0xa7, 0x1e, 0x7b, 0xed, 0x31, 0xc7, 0x1c, 0x0b, 0x1b, 0xe8,
+	0xef, 0x28, 0xf6, 0x83, 0xd4, 0x92, 0xa2, 0x2c, 0x07, 0xc8, 0xc9, 0x9c, 0xd1, 0xec, 0xcc, 0x3c,
+	0xf3, 0xf1, 0xec, 0x1a, 0x2c, 0x42, 0x23, 0x27, 0x18, 0xe3, 0x49, 0x38, 0xc7, 0x3d, 0x2f, 0x8c,
+	0xdc, 0x28, 0x3e, 0xeb, 0xb9, 0x21, 0xf1, 0xc7, 0x01, 0x8e, 0xba, 0xb3, 0x28, 0xa4, 0x21, 0x7a,
+	0x4f, 0xb1, 0xe9, 0x4a, 0x1b, 0xeb, 0x0f, 0x0d, 0xf4, 0xc3, 0x49, 0xe8, 0xfe, 0x84, 0x6e, 0x42,
+	0xd5, 0xc3, 0xfe, 0xd8, 0xa3, 0x86, 0xd6, 0xd6, 0x3a, 0x65, 0x5b, 0x4a, 0x68, 0x17, 0xf4, 0x28,
+	0x8c, 0x83, 0x91, 0x51, 0xe2, 0x6a, 0x21, 0x20, 0x04, 0x15, 0x42, 0xf1, 0xcc, 0x28, 0xb7, 0xb5,
+	0x8e, 0x6e, 0xf3, 0x6f, 0x74, 0x1b, 0xea, 0x2c, 0xe0, 0xe1, 0x39, 0xc5, 0xc4, 0xa8, 0xb4, 0xb5,
+	0x4e, 0xd3, 0x5e, 0x28, 0xd0, 0x5d, 0xd8, 0x9e, 0x87, 0x14, 0x7f, 0x75, 0x46, 0x07, 0xa9, 0x91,
+	0xce, 0x8d, 0x96, 0xf4, 0xcc, 0x13, 0xf5, 0xa7, 0x98, 0x50, 0x67, 0x3a, 0x33, 0xaa, 0x3c, 0xee,
+	0x42, 0x61, 0xcd, 0x61, 0x9b, 0x9b, 0xb2, 0xb4, 0x6d, 0xfc, 0x2a, 0xc6, 0x84, 0x22, 0x03, 0x36,
+	0x5d, 0xcf, 0xf1, 0x83, 0xc7, 0x47, 0x3c, 0xfd, 0xba, 0x9d, 0x88, 0xe8, 0x73, 0xd0, 0x87, 0xcc,
+	0x92, 0xe7, 0xdf, 0xe8, 0x9b, 0xdd, 0x82, 0x32, 0x74, 0x85, 0x2f, 0x7d, 0x98, 0x54, 0x82, 0xe0,
+	0x80, 0x46, 0xe7, 0x1c, 0x5d, 0xdd, 0x96, 0x92, 0xf5, 0x0b, 0xec, 0x28, 0x71, 0xc9, 0x2c, 0x0c,
+	0x08, 0x4e, 0x40, 0x3b, 0x34, 0x8e, 0xb0, 0xa1, 0x2d, 0x40, 0x73, 0x05, 0xba, 0x07, 0x88, 0x81,
+	0x7b, 0x89, 0xcf, 0xe8, 0xcb, 0x85, 0x59, 0x69, 0x09, 0xb6, 0xb0, 0xce, 0xc0, 0x2e, 0xe7, 0x61,
+	0xff, 0xa9, 0x81, 0xfe, 0x5d, 0x18, 0xb8, 0x18, 0x99, 0x50, 0x23, 0x61, 0x1c, 0xb9, 0x58, 0xa2,
+	0xd5, 0xed, 0x54, 0x46, 0x1f, 0xc3, 0xd6, 0x08, 0x13, 0xea, 0x07, 0x0e, 0xf5, 0x43, 0x56, 0x8e,
+	0x12, 0x37, 0xc8, 0x2a, 0x19, 0xc4, 0x59, 0x3c, 0x7c, 0x82, 0x05, 0xc4, 0xa6, 0x2d, 0x25, 0xd6,
+	0x6c, 0xe2, 0x39, 0x11, 0x96, 0xed, 0x13, 0x42, 0x16, 0xa3, 0x9e, 0xc7, 0xd8, 0x86, 0x86, 0x1b,
+	0x4e, 0xa7, 0x3e, 0x9d, 0xe2, 0x80, 0x12, 0xa3, 0xda, 0x2e, 0x77, 0x9a, 0xb6, 0xaa, 0xb2, 0x06,
+	0x50, 0x3f, 0x39, 0x79, 0x7c, 0x24, 0x92, 0x47, 0x50, 0x89, 0x63, 0x7f, 0x24, 0x6b, 0xc5, 0xbf,
+	0x51, 0x1f, 0xaa, 0x01, 0xfb, 0x91, 0x18, 0xa5, 0x76, 0x79, 0x65, 0x93, 0xf8, 0x79, 0x5b, 0x5a,
+	0x5a, 0xa7, 0x50, 0x39, 0xb6, 0x07, 0xcf, 0xdf, 0xcd, 0xdc, 0x2e, 0xca, 0x5e, 0xc9, 0x97, 0xfd,
+	0x4d, 0x09, 0xde, 0x1f, 0x60, 0xca, 0x83, 0x93, 0x87, 0xc1, 0x88, 0xb5, 0x2b, 0x99, 0xba, 0x77,
+	0x84, 0x05, 0xed, 0x43, 0xc5, 0x8b, 0x08, 0xe5, 0x59, 0x35, 0xfa, 0xb7, 0x0a, 0x4f, 0x30, 0xb0,
+	0x36, 0x37, 0x5b, 0xb3, 0x68, 0x6d, 0x68, 0xc8, 0xc9, 0x3a, 0x61, 0xb9, 0x89, 0x7e, 0xa9, 0x2a,
+	0xf4, 0x25, 0x6c, 0x49, 0x51, 0xa0, 0x32, 0xaa, 0x6b, 0x33, 0xcd, 0x1e, 0x28, 0x5c, 0xe6, 0xcd,
+	0x15, 0xcb, 0xac, 0xac, 0x66, 0x2d, 0xb3, 0x9a, 0xd6, 0xbf, 0x25, 0x30, 0x96, 0x4b, 0xbb, 0x58,
+	0xac, 0x45, 0x57, 0xb4, 0x5c, 0x57, 0x18, 0x48, 0x5e, 0xbb, 0xa7, 0xf1, 0x70, 0xe2, 0xbb, 0x72,
+	0xa3, 0x54, 0x55, 0x76, 0x68, 0xcb, 0xf9, 0xa1, 0xed, 0x02, 0x52, 0x11, 0x49, 0x37, 0xa2, 0x96,
+	0x05, 0xbf, 0xe4, 0x00, 0xab, 0x9b, 0xb0, 0xa4, 0x67, 0xeb, 0x89, 0xcf, 0x7c, 0xb6, 0x6e, 0x63,
+	0x4e, 0x5e, 0x35, 0x3b, 0x95, 0x95, 0xe9, 0xd8, 0xbc, 0xf6, 0x74, 0x2c, 0xb5, 0xab, 0xf6, 0x96,
+	0xed, 0xb2, 0x3a, 0xb0, 0xfd, 0x75, 0x52, 0xe7, 0x64, 0x76, 0x77, 0x41, 0x67, 0xf3, 0x4a, 0x0c,
+	0x8d, 0x2f, 0xac, 0x10, 0xac, 0x27, 0xb0, 0xa3, 0x58, 0xca, 0x56, 0x3c, 0x48, 0x93, 0xd6, 0x78,
+	0xe4, 0x56, 0x61, 0xe4, 0x74, 0xc5, 0xd3, 0x15, 0x3d, 0x80, 0x5b, 0xcf, 0x23, 0x27, 0x20, 0xa7,
+	0x38, 0xfa, 0x06, 0x3b, 0x23, 0x1c, 0x11, 0xcf, 0x9f, 0x25, 0xf1, 0x4d, 0xa8, 0x4d, 0xb8, 0x32,
+	0xa5, 0xec, 0x54, 0xb6, 0x7e, 0x04, 0xb3, 0xe8, 0xa0, 0x4c, 0xe7, 0x8a, 0x93, 0x8c, 0xfe, 0xc4,
+	0xf7, 0xc3, 0xd1, 0x28, 0xc2, 0x84, 0xf0, 0xc9, 0xa8, 0xdb, 0x59, 0xa5, 0x85, 0x78, 0x3d, 0x84,
+	0x6b, 0x99, 0x8f, 0xf5, 0x19, 0xec, 0x28, 0x3a, 0x19, 0xea, 0x26, 0x54, 0xc5, 0x49, 0xc9, 0xb3,
+	0x52, 0xb2, 0x3e, 0x82, 0xc6, 0x53, 0x3f, 0x18, 0x2b, 0xb5, 0xe4, 0x90, 0x25, 0x11, 0x08, 0xc1,
+	0xfa, 0x1e, 0x9a, 0xc2, 0x48, 0x3a, 0xeb, 0xc0, 0xff, 0x09, 0x76, 0xe3, 0xc8, 0xa7, 0xe7, 0x03,
+	0xd7, 0xc3, 0x53, 0x59, 0xcf, 0xba, 0x9d, 0x57, 0xa3, 0x16, 0x40, 0x3a, 0xaa, 0x82, 0x47, 0x9a,
+	0xb6, 0xa2, 0xb1, 0xfe, 0xd2, 0xa0, 0x71, 0x84, 0x9d, 0x89, 0x72, 0xfb, 0x11, 0x4c, 0x88, 0x1f,
+	0x06, 0xc9, 0xed, 0x27, 0xc5, 0xcc, 0x55, 0x51, 0x5a, 0x77, 0x55, 0x94, 0x8b, 0xae, 0x8a, 0x1c,
+	0xbd, 0x57, 0x96, 0xe8, 0x7d, 0x71, 0x69, 0xe8, 0x2b, 0x2f, 0x8d, 0x6a, 0x6e, 0xff, 0xac, 0xff,
+	0x41, 0x53, 0x00, 0x10, 0xb5, 0xb1, 0x46, 0xb0, 0x3b, 0xc0, 0xf4, 0xd8, 0x99, 0xd0, 0x63, 0x4e,
+	0xdf, 0xeb, 0xef, 0xf5, 0x16, 0x80, 0x97, 0x9a, 0x4b, 0x92, 0x57, 0x34, 0x8c, 0x9b, 0x27, 0xfe,
+	0xa9, 0xe0, 0xd4, 0x9a, 0xcd, 0xbf, 0xad, 0x03, 0xb8, 0x91, 0x8b, 0x22, 0x5b, 0x93, 0x75, 0xa6,
+	0xe5, 0x9d, 0x59, 0x0f, 0xe0, 0xf6, 0xa3, 0x09, 0x76, 0xa2, 0x01, 0x7f, 0x09, 0x3c, 0x8b, 0x9d,
+	0xc8, 0x09, 0xa8, 0x1f, 0xe0, 0x24, 0xcd, 0xc5, 0x93, 0x41, 0xcb, 0x3c, 0x19, 0x3e, 0x84, 0x0f,
+	0x56, 0x9c, 0x13, 0x81, 0xfb, 0xbf, 0x6f, 0x42, 0xed, 0x91, 0x7c, 0xa7, 0xa1, 0x17, 0x50, 0x4f,
+	0x1f, 0x18, 0xe8, 0x93, 0xc2, 0x25, 0xcb, 0x3f, 0x7c, 0xcc, 0xbd, 0x75, 0x66, 0xb2, 0xc0, 0x1b,
+	0xe8, 0x15, 0x6c, 0xe7, 0xc9, 0x16, 0xdd, 0x2b, 0x3e, 0x5d, 0x7c, 0xdd, 0x99, 0xfb, 0xd7, 0xb4,
+	0x4e, 0x43, 0xbe, 0x80, 0x7a, 0xca, 0x26, 0x2b, 0x00, 0xe5, 0x79, 0xc9, 0xdc, 0x5b, 0x67, 0x96,
+	0x7a, 0xff, 0x19, 0xd0, 0x32, 0x4b, 0xa0, 0x6e, 0xe1, 0xf9, 0x95, 0x3c, 0x64, 0xf6, 0xae, 0x6d,
+	0x9f, 0x83, 0x25, 0x7e, 0x5a, 0x0d, 0x2b, 0x43, 0x2f, 0xe6, 0xde, 0x3a, 0xb3, 0xd4, 0xfb, 0xb7,
+	0x50, 0x61, 0xb4, 0x81, 0xda, 0x85, 0x27, 0x14, 0xda, 0x31, 0xef, 0x5c, 0x61, 0xa1, 0xba, 0x63,
+	0x9b, 0xb6, 0xc2, 0x9d, 0xc2, 0x22, 0xe6, 0x9d, 0x2b, 0x2c, 0x52, 0x77, 0x1e, 0x6c, 0x65, 0x56,
+	0x08, 0x7d, 0xba, 0x6a, 0x28, 0x96, 0x96, 0xd9, 0xbc, 0x7b, 0x1d, 0xd3, 0x34, 0xd2, 0xaf, 0x1a,
+	0xdc, 0x28, 0x5c, 0x1e, 0xf4, 0x45, 0xa1, 0x9f, 0xab, 0x16, 0xd4, 0xec, 0xbf, 0xcd, 0x91, 0x24,
+	0x85, 0xc3, 0x67, 0x7f, 0x5f, 0xb4, 0xb4, 0xd7, 0x17, 0x2d, 0xed, 0x9f, 0x8b, 0x96, 0xf6, 0xdb,
+	0x65, 0x6b, 0xe3, 0xf5, 0x65, 0x6b, 0xe3, 0xcd, 0x65, 0x6b, 0xe3, 0x87, 0x83, 0xb1, 0x4f, 0xbd,
+	0x78, 0xd8, 0x75, 0xc3, 0x69, 0x4f, 0xf1, 0xbc, 0x3f, 0xc7, 0x01, 0xa7, 0xe8, 0xf4, 0x9f, 0xb0,
+	0xf9, 0xfd, 0x9e, 0xd8, 0xee, 0x1e, 0xff, 0x2f, 0x6c, 0x58, 0xe5, 0x7f, 0xee, 0xff, 0x37, 0x00,
+	0x31, 0xbd, 0x41, 0x3b, 0xb2, 0x0d, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1029,6 +1420,9 @@ type CosignerClient interface {
 	TransferLeadership(ctx context.Context, in *TransferLeadershipRequest, opts ...grpc.CallOption) (*TransferLeadershipResponse, error)
 	GetLeader(ctx context.Context, in *GetLeaderRequest, opts ...grpc.CallOption) (*GetLeaderResponse, error)
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	Deal(ctx context.Context, in *DealRequest, opts ...grpc.CallOption) (*DealResponse, error)
+	SetHaltHeight(ctx context.Context, in *SetHaltHeightRequest, opts ...grpc.CallOption) (*SetHaltHeightResponse, error)
+	ClearSentryQuarantine(ctx context.Context, in *ClearSentryQuarantineRequest, opts ...grpc.CallOption) (*ClearSentryQuarantineResponse, error)
 }
 
 type cosignerClient struct {
@@ -1093,6 +1487,33 @@ func (c *cosignerClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *cosignerClient) Deal(ctx context.Context, in *DealRequest, opts ...grpc.CallOption) (*DealResponse, error) {
+	out := new(DealResponse)
+	err := c.cc.Invoke(ctx, "/strangelove.horcrux.Cosigner/Deal", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cosignerClient) SetHaltHeight(ctx context.Context, in *SetHaltHeightRequest, opts ...grpc.CallOption) (*SetHaltHeightResponse, error) {
+	out := new(SetHaltHeightResponse)
+	err := c.cc.Invoke(ctx, "/strangelove.horcrux.Cosigner/SetHaltHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cosignerClient) ClearSentryQuarantine(ctx context.Context, in *ClearSentryQuarantineRequest, opts ...grpc.CallOption) (*ClearSentryQuarantineResponse, error) {
+	out := new(ClearSentryQuarantineResponse)
+	err := c.cc.Invoke(ctx, "/strangelove.horcrux.Cosigner/ClearSentryQuarantine", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CosignerServer is the server API for Cosigner service.
 type CosignerServer interface {
 	SignBlock(context.Context, *SignBlockRequest) (*SignBlockResponse, error)
@@ -1101,6 +1522,9 @@ type CosignerServer interface {
 	TransferLeadership(context.Context, *TransferLeadershipRequest) (*TransferLeadershipResponse, error)
 	GetLeader(context.Context, *GetLeaderRequest) (*GetLeaderResponse, error)
 	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	Deal(context.Context, *DealRequest) (*DealResponse, error)
+	SetHaltHeight(context.Context, *SetHaltHeightRequest) (*SetHaltHeightResponse, error)
+	ClearSentryQuarantine(context.Context, *ClearSentryQuarantineRequest) (*ClearSentryQuarantineResponse, error)
 }
 
 // UnimplementedCosignerServer can be embedded to have forward compatible implementations.
@@ -1125,6 +1549,15 @@ func (*UnimplementedCosignerServer) GetLeader(ctx context.Context, req *GetLeade
 func (*UnimplementedCosignerServer) Ping(ctx context.Context, req *PingRequest) (*PingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
 }
+func (*UnimplementedCosignerServer) Deal(ctx context.Context, req *DealRequest) (*DealResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deal not implemented")
+}
+func (*UnimplementedCosignerServer) SetHaltHeight(ctx context.Context, req *SetHaltHeightRequest) (*SetHaltHeightResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetHaltHeight not implemented")
+}
+func (*UnimplementedCosignerServer) ClearSentryQuarantine(ctx context.Context, req *ClearSentryQuarantineRequest) (*ClearSentryQuarantineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ClearSentryQuarantine not implemented")
+}
 
 func RegisterCosignerServer(s grpc1.Server, srv CosignerServer) {
 	s.RegisterService(&_Cosigner_serviceDesc, srv)
@@ -1238,15 +1671,69 @@ func _Cosigner_Ping_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
-var _Cosigner_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "strangelove.horcrux.Cosigner",
-	HandlerType: (*CosignerServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "SignBlock",
-			Handler:    _Cosigner_SignBlock_Handler,
-		},
-		{
+func _Cosigner_Deal_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DealRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CosignerServer).Deal(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/strangelove.horcrux.Cosigner/Deal",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CosignerServer).Deal(ctx, req.(*DealRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cosigner_SetHaltHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetHaltHeightRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CosignerServer).SetHaltHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/strangelove.horcrux.Cosigner/SetHaltHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CosignerServer).SetHaltHeight(ctx, req.(*SetHaltHeightRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cosigner_ClearSentryQuarantine_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClearSentryQuarantineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CosignerServer).ClearSentryQuarantine(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/strangelove.horcrux.Cosigner/ClearSentryQuarantine",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CosignerServer).ClearSentryQuarantine(ctx, req.(*ClearSentryQuarantineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Cosigner_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "strangelove.horcrux.Cosigner",
+	HandlerType: (*CosignerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SignBlock",
+			Handler:    _Cosigner_SignBlock_Handler,
+		},
+		{
 			MethodName: "SetNoncesAndSign",
 			Handler:    _Cosigner_SetNoncesAndSign_Handler,
 		},
@@ -1266,6 +1753,18 @@ var _Cosigner_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Ping",
 			Handler:    _Cosigner_Ping_Handler,
 		},
+		{
+			MethodName: "Deal",
+			Handler:    _Cosigner_Deal_Handler,
+		},
+		{
+			MethodName: "SetHaltHeight",
+			Handler:    _Cosigner_SetHaltHeight_Handler,
+		},
+		{
+			MethodName: "ClearSentryQuarantine",
+			Handler:    _Cosigner_ClearSentryQuarantine_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "strangelove/horcrux/cosigner.proto",
@@ -1348,6 +1847,13 @@ func (m *SignBlockRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Sentry) > 0 {
+		i -= len(m.Sentry)
+		copy(dAtA[i:], m.Sentry)
+		i = encodeVarintCosigner(dAtA, i, uint64(len(m.Sentry)))
+		i--
+		dAtA[i] = 0x1a
+	}
 	if m.Block != nil {
 		{
 			size, err := m.Block.MarshalToSizedBuffer(dAtA[:i])
@@ -1432,6 +1938,15 @@ func (m *Nonce) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Commitments) > 0 {
+		for iNdEx := len(m.Commitments) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Commitments[iNdEx])
+			copy(dAtA[i:], m.Commitments[iNdEx])
+			i = encodeVarintCosigner(dAtA, i, uint64(len(m.Commitments[iNdEx])))
+			i--
+			dAtA[i] = 0x32
+		}
+	}
 	if len(m.Signature) > 0 {
 		i -= len(m.Signature)
 		copy(dAtA[i:], m.Signature)
@@ -1671,6 +2186,44 @@ func (m *SetNoncesAndSignResponse) MarshalToSizedBuffer(dAtA []byte) (int, error
 	_ = i
 	var l int
 	_ = l
+	if len(m.VoteExtNonces) > 0 {
+		for iNdEx := len(m.VoteExtNonces) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.VoteExtNonces[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintCosigner(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x42
+		}
+	}
+	if len(m.Nonces) > 0 {
+		for iNdEx := len(m.Nonces) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Nonces[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintCosigner(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x3a
+		}
+	}
+	if m.Existing {
+		i--
+		if m.Existing {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x30
+	}
 	if len(m.VoteExtSignature) > 0 {
 		i -= len(m.VoteExtSignature)
 		copy(dAtA[i:], m.VoteExtSignature)
@@ -1914,6 +2467,13 @@ func (m *PingRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Nonce) > 0 {
+		i -= len(m.Nonce)
+		copy(dAtA[i:], m.Nonce)
+		i = encodeVarintCosigner(dAtA, i, uint64(len(m.Nonce)))
+		i--
+		dAtA[i] = 0xa
+	}
 	return len(dAtA) - i, nil
 }
 
@@ -1937,96 +2497,330 @@ func (m *PingResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Signatures) > 0 {
+		for iNdEx := len(m.Signatures) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Signatures[iNdEx])
+			copy(dAtA[i:], m.Signatures[iNdEx])
+			i = encodeVarintCosigner(dAtA, i, uint64(len(m.Signatures[iNdEx])))
+			i--
+			dAtA[i] = 0x12
+		}
+	}
+	if len(m.SecuritySchemes) > 0 {
+		for iNdEx := len(m.SecuritySchemes) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.SecuritySchemes[iNdEx])
+			copy(dAtA[i:], m.SecuritySchemes[iNdEx])
+			i = encodeVarintCosigner(dAtA, i, uint64(len(m.SecuritySchemes[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintCosigner(dAtA []byte, offset int, v uint64) int {
-	offset -= sovCosigner(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *DealRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *Block) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *DealRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DealRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.Height != 0 {
-		n += 1 + sovCosigner(uint64(m.Height))
+	if len(m.Signature) > 0 {
+		i -= len(m.Signature)
+		copy(dAtA[i:], m.Signature)
+		i = encodeVarintCosigner(dAtA, i, uint64(len(m.Signature)))
+		i--
+		dAtA[i] = 0x32
 	}
-	if m.Round != 0 {
-		n += 1 + sovCosigner(uint64(m.Round))
+	if len(m.Share) > 0 {
+		i -= len(m.Share)
+		copy(dAtA[i:], m.Share)
+		i = encodeVarintCosigner(dAtA, i, uint64(len(m.Share)))
+		i--
+		dAtA[i] = 0x2a
 	}
-	if m.Step != 0 {
-		n += 1 + sovCosigner(uint64(m.Step))
+	if len(m.Commitments) > 0 {
+		for iNdEx := len(m.Commitments) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Commitments[iNdEx])
+			copy(dAtA[i:], m.Commitments[iNdEx])
+			i = encodeVarintCosigner(dAtA, i, uint64(len(m.Commitments[iNdEx])))
+			i--
+			dAtA[i] = 0x22
+		}
 	}
-	l = len(m.SignBytes)
-	if l > 0 {
-		n += 1 + l + sovCosigner(uint64(l))
+	if m.DestinationID != 0 {
+		i = encodeVarintCosigner(dAtA, i, uint64(m.DestinationID))
+		i--
+		dAtA[i] = 0x18
 	}
-	l = len(m.VoteExtSignBytes)
-	if l > 0 {
-		n += 1 + l + sovCosigner(uint64(l))
+	if m.SourceID != 0 {
+		i = encodeVarintCosigner(dAtA, i, uint64(m.SourceID))
+		i--
+		dAtA[i] = 0x10
 	}
-	if m.Timestamp != 0 {
-		n += 1 + sovCosigner(uint64(m.Timestamp))
+	if len(m.Session) > 0 {
+		i -= len(m.Session)
+		copy(dAtA[i:], m.Session)
+		i = encodeVarintCosigner(dAtA, i, uint64(len(m.Session)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SignBlockRequest) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *DealResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *DealResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *DealResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.ChainID)
-	if l > 0 {
-		n += 1 + l + sovCosigner(uint64(l))
-	}
-	if m.Block != nil {
-		l = m.Block.Size()
-		n += 1 + l + sovCosigner(uint64(l))
-	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *SignBlockResponse) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SetHaltHeightRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SetHaltHeightRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SetHaltHeightRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Signature)
-	if l > 0 {
-		n += 1 + l + sovCosigner(uint64(l))
+	if m.Lift {
+		i--
+		if m.Lift {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
 	}
-	l = len(m.VoteExtSignature)
-	if l > 0 {
-		n += 1 + l + sovCosigner(uint64(l))
+	if m.HaltHeight != 0 {
+		i = encodeVarintCosigner(dAtA, i, uint64(m.HaltHeight))
+		i--
+		dAtA[i] = 0x10
 	}
-	if m.Timestamp != 0 {
-		n += 1 + sovCosigner(uint64(m.Timestamp))
+	if len(m.ChainID) > 0 {
+		i -= len(m.ChainID)
+		copy(dAtA[i:], m.ChainID)
+		i = encodeVarintCosigner(dAtA, i, uint64(len(m.ChainID)))
+		i--
+		dAtA[i] = 0xa
 	}
-	return n
+	return len(dAtA) - i, nil
 }
 
-func (m *Nonce) Size() (n int) {
-	if m == nil {
-		return 0
+func (m *SetHaltHeightResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
+	return dAtA[:n], nil
+}
+
+func (m *SetHaltHeightResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SetHaltHeightResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.SourceID != 0 {
-		n += 1 + sovCosigner(uint64(m.SourceID))
+	if m.HaltHeight != 0 {
+		i = encodeVarintCosigner(dAtA, i, uint64(m.HaltHeight))
+		i--
+		dAtA[i] = 0x8
 	}
-	if m.DestinationID != 0 {
+	return len(dAtA) - i, nil
+}
+
+func (m *ClearSentryQuarantineRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClearSentryQuarantineRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ClearSentryQuarantineRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.Sentry) > 0 {
+		i -= len(m.Sentry)
+		copy(dAtA[i:], m.Sentry)
+		i = encodeVarintCosigner(dAtA, i, uint64(len(m.Sentry)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ClearSentryQuarantineResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ClearSentryQuarantineResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ClearSentryQuarantineResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintCosigner(dAtA []byte, offset int, v uint64) int {
+	offset -= sovCosigner(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *Block) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovCosigner(uint64(m.Height))
+	}
+	if m.Round != 0 {
+		n += 1 + sovCosigner(uint64(m.Round))
+	}
+	if m.Step != 0 {
+		n += 1 + sovCosigner(uint64(m.Step))
+	}
+	l = len(m.SignBytes)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	l = len(m.VoteExtSignBytes)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovCosigner(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *SignBlockRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ChainID)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	if m.Block != nil {
+		l = m.Block.Size()
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	l = len(m.Sentry)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	return n
+}
+
+func (m *SignBlockResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	l = len(m.VoteExtSignature)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovCosigner(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *Nonce) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.SourceID != 0 {
+		n += 1 + sovCosigner(uint64(m.SourceID))
+	}
+	if m.DestinationID != 0 {
 		n += 1 + sovCosigner(uint64(m.DestinationID))
 	}
 	l = len(m.PubKey)
@@ -2041,6 +2835,12 @@ func (m *Nonce) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovCosigner(uint64(l))
 	}
+	if len(m.Commitments) > 0 {
+		for _, b := range m.Commitments {
+			l = len(b)
+			n += 1 + l + sovCosigner(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2154,6 +2954,21 @@ func (m *SetNoncesAndSignResponse) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovCosigner(uint64(l))
 	}
+	if m.Existing {
+		n += 2
+	}
+	if len(m.Nonces) > 0 {
+		for _, e := range m.Nonces {
+			l = e.Size()
+			n += 1 + l + sovCosigner(uint64(l))
+		}
+	}
+	if len(m.VoteExtNonces) > 0 {
+		for _, e := range m.VoteExtNonces {
+			l = e.Size()
+			n += 1 + l + sovCosigner(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -2244,10 +3059,121 @@ func (m *PingRequest) Size() (n int) {
 	}
 	var l int
 	_ = l
+	l = len(m.Nonce)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
 	return n
 }
 
 func (m *PingResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.SecuritySchemes) > 0 {
+		for _, s := range m.SecuritySchemes {
+			l = len(s)
+			n += 1 + l + sovCosigner(uint64(l))
+		}
+	}
+	if len(m.Signatures) > 0 {
+		for _, b := range m.Signatures {
+			l = len(b)
+			n += 1 + l + sovCosigner(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *DealRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Session)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	if m.SourceID != 0 {
+		n += 1 + sovCosigner(uint64(m.SourceID))
+	}
+	if m.DestinationID != 0 {
+		n += 1 + sovCosigner(uint64(m.DestinationID))
+	}
+	if len(m.Commitments) > 0 {
+		for _, b := range m.Commitments {
+			l = len(b)
+			n += 1 + l + sovCosigner(uint64(l))
+		}
+	}
+	l = len(m.Share)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	return n
+}
+
+func (m *DealResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	return n
+}
+
+func (m *SetHaltHeightRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.ChainID)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	if m.HaltHeight != 0 {
+		n += 1 + sovCosigner(uint64(m.HaltHeight))
+	}
+	if m.Lift {
+		n += 2
+	}
+	return n
+}
+
+func (m *SetHaltHeightResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.HaltHeight != 0 {
+		n += 1 + sovCosigner(uint64(m.HaltHeight))
+	}
+	return n
+}
+
+func (m *ClearSentryQuarantineRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Sentry)
+	if l > 0 {
+		n += 1 + l + sovCosigner(uint64(l))
+	}
+	return n
+}
+
+func (m *ClearSentryQuarantineResponse) Size() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -2553,19 +3479,51 @@ func (m *SignBlockRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipCosigner(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthCosigner
-			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sentry", wireType)
 			}
-			iNdEx += skippy
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sentry = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCosigner(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
 		}
 	}
 
@@ -2880,6 +3838,38 @@ func (m *Nonce) Unmarshal(dAtA []byte) error {
 				m.Signature = []byte{}
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commitments", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Commitments = append(m.Commitments, make([]byte, postIndex-iNdEx))
+			copy(m.Commitments[len(m.Commitments)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCosigner(dAtA[iNdEx:])
@@ -3651,6 +4641,94 @@ func (m *SetNoncesAndSignResponse) Unmarshal(dAtA []byte) error {
 				m.VoteExtSignature = []byte{}
 			}
 			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Existing", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Existing = bool(v != 0)
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonces", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Nonces = append(m.Nonces, &Nonce{})
+			if err := m.Nonces[len(m.Nonces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field VoteExtNonces", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.VoteExtNonces = append(m.VoteExtNonces, &Nonce{})
+			if err := m.VoteExtNonces[len(m.VoteExtNonces)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCosigner(dAtA[iNdEx:])
@@ -4182,6 +5260,40 @@ func (m *PingRequest) Unmarshal(dAtA []byte) error {
 			return fmt.Errorf("proto: PingRequest: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nonce", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Nonce = append(m.Nonce[:0], dAtA[iNdEx:postIndex]...)
+			if m.Nonce == nil {
+				m.Nonce = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCosigner(dAtA[iNdEx:])
@@ -4232,6 +5344,662 @@ func (m *PingResponse) Unmarshal(dAtA []byte) error {
 			return fmt.Errorf("proto: PingResponse: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SecuritySchemes", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SecuritySchemes = append(m.SecuritySchemes, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signatures", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signatures = append(m.Signatures, make([]byte, postIndex-iNdEx))
+			copy(m.Signatures[len(m.Signatures)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCosigner(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DealRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCosigner
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DealRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DealRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Session", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Session = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SourceID", wireType)
+			}
+			m.SourceID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.SourceID |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DestinationID", wireType)
+			}
+			m.DestinationID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.DestinationID |= int32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Commitments", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Commitments = append(m.Commitments, make([]byte, postIndex-iNdEx))
+			copy(m.Commitments[len(m.Commitments)-1], dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Share", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Share = append(m.Share[:0], dAtA[iNdEx:postIndex]...)
+			if m.Share == nil {
+				m.Share = []byte{}
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCosigner(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *DealResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCosigner
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: DealResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: DealResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCosigner(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SetHaltHeightRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCosigner
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SetHaltHeightRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SetHaltHeightRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ChainID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ChainID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HaltHeight", wireType)
+			}
+			m.HaltHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HaltHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Lift", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Lift = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCosigner(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SetHaltHeightResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCosigner
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SetHaltHeightResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SetHaltHeightResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HaltHeight", wireType)
+			}
+			m.HaltHeight = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HaltHeight |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCosigner(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ClearSentryQuarantineRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCosigner
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClearSentryQuarantineRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClearSentryQuarantineRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sentry", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowCosigner
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sentry = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipCosigner(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthCosigner
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ClearSentryQuarantineResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowCosigner
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ClearSentryQuarantineResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ClearSentryQuarantineResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
 		default:
 			iNdEx = preIndex
 			skippy, err := skipCosigner(dAtA[iNdEx:])
@@ -28,6 +28,7 @@ import (
 	boltdb "github.com/hashicorp/raft-boltdb/v2"
 	"github.com/strangelove-ventures/horcrux/v3/signer/proto"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 )
@@ -62,6 +63,8 @@ type RaftStore struct {
 	logger             log.Logger
 	cosigner           *LocalCosigner
 	thresholdValidator *ThresholdValidator
+
+	transportCredentials credentials.TransportCredentials
 }
 
 // New returns a new Store.
@@ -87,6 +90,19 @@ func (s *RaftStore) SetThresholdValidator(thresholdValidator *ThresholdValidator
 	s.thresholdValidator = thresholdValidator
 }
 
+// SetTransportCredentials sets the gRPC transport credentials of the p2p connections between cosigners.
+// The connections are cleartext if no transport credentials are set.
+func (s *RaftStore) SetTransportCredentials(creds credentials.TransportCredentials) {
+	s.transportCredentials = creds
+}
+
+func (s *RaftStore) transportCreds() credentials.TransportCredentials {
+	if s.transportCredentials == nil {
+		return insecure.NewCredentials()
+	}
+	return s.transportCredentials
+}
+
 func (s *RaftStore) init() error {
 	host := p2pURLToRaftAddress(s.RaftBind)
 	_, port, err := net.SplitHostPort(host)
@@ -102,7 +118,17 @@ func (s *RaftStore) init() error {
 	if err != nil {
 		return err
 	}
-	grpcServer := grpc.NewServer()
+	peerIDs := make([]int, len(s.Cosigners))
+	for i, c := range s.Cosigners {
+		peerIDs[i] = c.GetID()
+	}
+	authMode := ""
+	if cfg := s.cosigner.config.Config.ThresholdModeConfig; cfg != nil {
+		authMode = cfg.AuthMode
+	}
+	auth := newCosignerAuthenticator(s.logger, s.cosigner.security, peerIDs, authMode)
+
+	grpcServer := grpc.NewServer(append(auth.ServerOptions(), grpc.Creds(s.transportCreds()))...)
 	proto.RegisterCosignerServer(grpcServer, NewCosignerGRPCServer(s.cosigner, s.thresholdValidator, s))
 	transportManager.Register(grpcServer)
 	leaderhealth.Setup(s.raft, grpcServer, []string{"Leader"})
@@ -164,10 +190,18 @@ func (s *RaftStore) Open() (*raftgrpctransport.Manager, error) {
 
 	raftAddress := raft.ServerAddress(p2pURLToRaftAddress(s.RaftBind))
 
-	// Setup Raft communication.
-	transportManager := raftgrpctransport.New(raftAddress, []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-	})
+	// Setup Raft communication. Raft requests are authenticated like Cosigner requests.
+	dialOptions := []grpc.DialOption{
+		grpc.WithTransportCredentials(s.transportCreds()),
+	}
+	if s.cosigner != nil {
+		peers := make(map[string]int, len(s.Cosigners))
+		for _, c := range s.Cosigners {
+			peers[p2pURLToRaftAddress(c.GetAddress())] = c.GetID()
+		}
+		dialOptions = append(dialOptions, raftAuthDialOptions(s.cosigner.security, peers)...)
+	}
+	transportManager := raftgrpctransport.New(raftAddress, dialOptions)
 
 	// Instantiate the Raft systems.
 	ra, err := raft.NewRaft(config, (*fsm)(s), logStore, stableStore, snapshots, transportManager.Transport())
@@ -2,6 +2,7 @@ package signer
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"net/url"
 	"time"
@@ -10,30 +11,42 @@ import (
 	"github.com/google/uuid"
 	"github.com/strangelove-ventures/horcrux/v3/signer/proto"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 )
 
 var _ Cosigner = &RemoteCosigner{}
 
 // RemoteCosigner uses CosignerGRPC to request signing from a remote cosigner
 type RemoteCosigner struct {
-	id      int
-	address string
+	id       int
+	address  string
+	security CosignerSecurity
 
 	client proto.CosignerClient
 }
 
-// NewRemoteCosigner returns a newly initialized RemoteCosigner
-func NewRemoteCosigner(id int, address string) (*RemoteCosigner, error) {
-	client, err := getGRPCClient(address)
+// NewRemoteCosigner returns a newly initialized RemoteCosigner.
+// Requests are sent with the transport credentials and are signed with the security of our cosigner.
+func NewRemoteCosigner(
+	id int,
+	address string,
+	creds credentials.TransportCredentials,
+	security CosignerSecurity,
+) (*RemoteCosigner, error) {
+	client, err := getGRPCClient(
+		address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUnaryInterceptor(CosignerAuthUnaryClientInterceptor(security, id)),
+	)
 	if err != nil {
 		return nil, err
 	}
 
 	cosigner := &RemoteCosigner{
-		id:      id,
-		address: address,
-		client:  client,
+		id:       id,
+		address:  address,
+		security: security,
+		client:   client,
 	}
 
 	return cosigner, nil
@@ -63,7 +76,33 @@ func (cosigner *RemoteCosigner) VerifySignature(_ string, _, _ []byte) bool {
 	return false
 }
 
-func getGRPCClient(address string) (proto.CosignerClient, error) {
+// Ping checks that the remote cosigner is reachable, and negotiates the security scheme with it
+// if our cosigner holds the keys of several schemes. The advertised schemes must be signed
+// by the remote cosigner for the random nonce of the request.
+func (cosigner *RemoteCosigner) Ping(ctx context.Context) error {
+	nonce := make([]byte, pingNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	res, err := cosigner.client.Ping(ctx, &proto.PingRequest{Nonce: nonce})
+
+	if err != nil {
+		// the negotiated scheme is kept, falling back to all schemes would allow a downgrade
+		return err
+	}
+	if multi, ok := cosigner.security.(*CosignerSecurityMulti); ok {
+		return multi.SetSignedPeerSchemes(cosigner.id, nonce, res.SecuritySchemes, res.Signatures)
+	}
+	return nil
+}
+
+// negotiatesSecurity returns true if the security scheme with the remote cosigner is negotiated with Ping.
+func (cosigner *RemoteCosigner) negotiatesSecurity() bool {
+	_, ok := cosigner.security.(*CosignerSecurityMulti)
+	return ok
+}
+
+func getGRPCClient(address string, opts ...grpc.DialOption) (proto.CosignerClient, error) {
 	var grpcAddress string
 	url, err := url.Parse(address)
 	if err != nil {
@@ -71,7 +110,7 @@ func getGRPCClient(address string) (proto.CosignerClient, error) {
 	} else {
 		grpcAddress = url.Host
 	}
-	conn, err := grpc.Dial(grpcAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.Dial(grpcAddress, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +171,9 @@ func (cosigner *RemoteCosigner) SetNoncesAndSign(
 		Signature:                res.Signature,
 		VoteExtensionSignature:   res.VoteExtSignature,
 		VoteExtensionNoncePublic: res.VoteExtNoncePublic,
+		Existing:                 res.Existing,
+		Nonces:                   CosignerNoncesFromProto(res.Nonces),
+		VoteExtensionNonces:      CosignerNoncesFromProto(res.VoteExtNonces),
 	}, nil
 }
 
@@ -142,6 +184,7 @@ func (cosigner *RemoteCosigner) Sign(
 	res, err := cosigner.client.SignBlock(ctx, &proto.SignBlockRequest{
 		ChainID: req.ChainID,
 		Block:   req.Block.ToProto(),
+		Sentry:  req.Sentry,
 	})
 	if err != nil {
 		return nil, err
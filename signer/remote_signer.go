@@ -4,9 +4,10 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"strings"
 	"time"
 
-	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometcrypto "github.com/cometbft/cometbft/crypto"
 	cometcryptoencoding "github.com/cometbft/cometbft/crypto/encoding"
 	cometlog "github.com/cometbft/cometbft/libs/log"
 	cometnet "github.com/cometbft/cometbft/libs/net"
@@ -15,6 +16,7 @@ import (
 	cometprotocrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
 	cometprotoprivval "github.com/cometbft/cometbft/proto/tendermint/privval"
 	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/cosmos/gogoproto/proto"
 )
 
 const connRetrySec = 2
@@ -23,41 +25,61 @@ const connRetrySec = 2
 // with additional Stop method for safe shutdown.
 type PrivValidator interface {
 	Sign(ctx context.Context, chainID string, block Block) ([]byte, []byte, time.Time, error)
-	GetPubKey(ctx context.Context, chainID string) ([]byte, error)
+	GetPubKey(ctx context.Context, chainID string) (cometcrypto.PubKey, error)
 	Stop()
 }
 
+// remoteSignerHandler responds to the requests of a chain node over a privval connection,
+// whether horcrux dialed the chain node or the chain node dialed horcrux.
+type remoteSignerHandler struct {
+	logger cometlog.Logger
+
+	// address identifies the chain node in the chains allowlist, the logs and the metrics.
+	address string
+	// nodeID is the pinned or authenticated node ID of the chain node, if any.
+	nodeID  string
+	privVal PrivValidator
+	chains  *ChainAllowlist
+}
+
 // ReconnRemoteSigner dials using its dialer and responds to any
 // signature requests using its privVal.
 type ReconnRemoteSigner struct {
 	cometservice.BaseService
+	remoteSignerHandler
 
-	address string
-	privKey cometcryptoed25519.PrivKey
-	privVal PrivValidator
+	privKey cometcrypto.PrivKey
 
 	dialer net.Dialer
 
 	maxReadSize int
 }
 
-// NewReconnRemoteSigner return a ReconnRemoteSigner that will dial using the given
+// NewReconnRemoteSigner return a ReconnRemoteSigner that will dial the chain node using the given
 // dialer and respond to any signature requests over the connection
-// using the given privVal.
+// using the given privVal. Requests for chains that are not allowed by the chains allowlist are rejected.
+// The connection is authenticated with privKey, and dropped if the chain node does not match its pinned node ID.
 //
 // If the connection is broken, the ReconnRemoteSigner will attempt to reconnect.
 func NewReconnRemoteSigner(
-	address string,
+	node ChainNode,
 	logger cometlog.Logger,
 	privVal PrivValidator,
+	chains *ChainAllowlist,
+	privKey cometcrypto.PrivKey,
 	dialer net.Dialer,
 	maxReadSize int,
 ) *ReconnRemoteSigner {
 	rs := &ReconnRemoteSigner{
-		address:     address,
-		privVal:     privVal,
+		remoteSignerHandler: remoteSignerHandler{
+			logger:  logger,
+			address: node.PrivValAddr,
+			nodeID:  node.NodeID,
+			privVal: privVal,
+			chains:  chains,
+		},
 		dialer:      dialer,
-		privKey:     cometcryptoed25519.GenPrivKey(),
+		privKey:     privKey,
 		maxReadSize: maxReadSize,
 	}
 
@@ -92,6 +114,13 @@ func (rs *ReconnRemoteSigner) establishConnection(ctx context.Context) (net.Conn
 		return nil, fmt.Errorf("secret connection error: %w", err)
 	}
 
+	node := ChainNode{PrivValAddr: rs.address, NodeID: rs.nodeID}
+	if err := verifySentryIdentity(node, conn.RemotePubKey()); err != nil {
+		conn.Close()
+		totalRejectedSentryHandshakes.WithLabelValues(rs.address).Inc()
+		return nil, err
+	}
+
 	return conn, nil
 }
 
@@ -168,32 +197,38 @@ func (rs *ReconnRemoteSigner) loop(ctx context.Context) {
 	}
 }
 
-func (rs *ReconnRemoteSigner) handleRequest(req cometprotoprivval.Message) cometprotoprivval.Message {
+func (h *remoteSignerHandler) handleRequest(req cometprotoprivval.Message) proto.Message {
+	var res cometprotoprivval.Message
 	switch typedReq := req.Sum.(type) {
 	case *cometprotoprivval.Message_SignVoteRequest:
-		return rs.handleSignVoteRequest(typedReq.SignVoteRequest.ChainId, typedReq.SignVoteRequest.Vote)
+		res = h.handleSignVoteRequest(typedReq.SignVoteRequest.ChainId, typedReq.SignVoteRequest.Vote)
 	case *cometprotoprivval.Message_SignProposalRequest:
-		return rs.handleSignProposalRequest(typedReq.SignProposalRequest.ChainId, typedReq.SignProposalRequest.Proposal)
+		res = h.handleSignProposalRequest(typedReq.SignProposalRequest.ChainId, typedReq.SignProposalRequest.Proposal)
 	case *cometprotoprivval.Message_PubKeyRequest:
-		return rs.handlePubKeyRequest(typedReq.PubKeyRequest.ChainId)
+		return h.handlePubKeyRequest(typedReq.PubKeyRequest.ChainId)
 	case *cometprotoprivval.Message_PingRequest:
-		return rs.handlePingRequest()
+		res = h.handlePingRequest()
 	default:
-		rs.Logger.Error("Unknown request", "err", fmt.Errorf("%v", typedReq))
-		return cometprotoprivval.Message{}
+		h.logger.Error("Unknown request", "err", fmt.Errorf("%v", typedReq))
 	}
+	return &res
 }
 
-func (rs *ReconnRemoteSigner) handleSignVoteRequest(chainID string, vote *cometproto.Vote) cometprotoprivval.Message {
+func (h *remoteSignerHandler) handleSignVoteRequest(chainID string, vote *cometproto.Vote) cometprotoprivval.Message {
 	msgSum := &cometprotoprivval.Message_SignedVoteResponse{SignedVoteResponse: &cometprotoprivval.SignedVoteResponse{
 		Vote:  cometproto.Vote{},
 		Error: nil,
 	}}
 
+	if err := h.allowChain(chainID); err != nil {
+		msgSum.SignedVoteResponse.Error = getRemoteSignerError(err)
+		return cometprotoprivval.Message{Sum: msgSum}
+	}
+
 	sig, voteExtSig, timestamp, err := signAndTrack(
-		context.TODO(),
-		rs.Logger,
-		rs.privVal,
+		withSentry(context.TODO(), h.sentry()),
+		h.logger,
+		h.privVal,
 		chainID,
 		VoteToBlock(chainID, vote),
 	)
@@ -208,7 +243,7 @@ func (rs *ReconnRemoteSigner) handleSignVoteRequest(chainID string, vote *cometp
 	return cometprotoprivval.Message{Sum: msgSum}
 }
 
-func (rs *ReconnRemoteSigner) handleSignProposalRequest(
+func (h *remoteSignerHandler) handleSignProposalRequest(
 	chainID string,
 	proposal *cometproto.Proposal,
 ) cometprotoprivval.Message {
@@ -219,10 +254,15 @@ func (rs *ReconnRemoteSigner) handleSignProposalRequest(
 		},
 	}
 
+	if err := h.allowChain(chainID); err != nil {
+		msgSum.SignedProposalResponse.Error = getRemoteSignerError(err)
+		return cometprotoprivval.Message{Sum: msgSum}
+	}
+
 	signature, _, timestamp, err := signAndTrack(
-		context.TODO(),
-		rs.Logger,
-		rs.privVal,
+		withSentry(context.TODO(), h.sentry()),
+		h.logger,
+		h.privVal,
 		chainID,
 		ProposalToBlock(chainID, proposal),
 	)
@@ -236,40 +276,105 @@ func (rs *ReconnRemoteSigner) handleSignProposalRequest(
 	return cometprotoprivval.Message{Sum: msgSum}
 }
 
-func (rs *ReconnRemoteSigner) handlePubKeyRequest(chainID string) cometprotoprivval.Message {
-	totalPubKeyRequests.WithLabelValues(chainID).Inc()
+// handlePubKeyRequest responds with the public key of the validator for the chain.
+// BLS12-381 public keys are not supported by the CometBFT v0.38 protos, so they are
+// sent as a pubKeyResponseBLS12381 message, which is wire compatible with CometBFT v1.
+func (h *remoteSignerHandler) handlePubKeyRequest(chainID string) proto.Message {
 	msgSum := &cometprotoprivval.Message_PubKeyResponse{PubKeyResponse: &cometprotoprivval.PubKeyResponse{
 		PubKey: cometprotocrypto.PublicKey{},
 		Error:  nil,
 	}}
 
-	pubKey, err := rs.privVal.GetPubKey(context.TODO(), chainID)
+	if err := h.allowChain(chainID); err != nil {
+		msgSum.PubKeyResponse.Error = getRemoteSignerError(err)
+		return &cometprotoprivval.Message{Sum: msgSum}
+	}
+
+	// only counted for allowed chains, so that arbitrary chain IDs do not create metric series
+	totalPubKeyRequests.WithLabelValues(chainID).Inc()
+
+	pubKey, err := h.privVal.GetPubKey(context.TODO(), chainID)
 	if err != nil {
-		rs.Logger.Error(
+		h.logger.Error(
 			"Failed to get Pub Key",
 			"chain_id", chainID,
-			"node", rs.address,
+			"node", h.address,
 			"error", err,
 		)
 		msgSum.PubKeyResponse.Error = getRemoteSignerError(err)
-		return cometprotoprivval.Message{Sum: msgSum}
+		return &cometprotoprivval.Message{Sum: msgSum}
 	}
-	pk, err := cometcryptoencoding.PubKeyToProto(cometcryptoed25519.PubKey(pubKey))
+	if blsPubKey, ok := pubKey.(PubKeyBLS12381); ok {
+		return &pubKeyResponseBLS12381{pubKey: blsPubKey}
+	}
+	pk, err := cometcryptoencoding.PubKeyToProto(pubKey)
 	if err != nil {
-		rs.Logger.Error(
+		h.logger.Error(
 			"Failed to get Pub Key",
 			"chain_id", chainID,
-			"node", rs.address,
+			"node", h.address,
 			"error", err,
 		)
 		msgSum.PubKeyResponse.Error = getRemoteSignerError(err)
-		return cometprotoprivval.Message{Sum: msgSum}
+		return &cometprotoprivval.Message{Sum: msgSum}
 	}
 	msgSum.PubKeyResponse.PubKey = pk
-	return cometprotoprivval.Message{Sum: msgSum}
+	return &cometprotoprivval.Message{Sum: msgSum}
+}
+
+const (
+	// messageFieldPubKeyResponse is the field number of PubKeyResponse in the privval Message oneof.
+	messageFieldPubKeyResponse = 2
+	// pubKeyResponseFieldPubKey is the field number of the public key in PubKeyResponse.
+	pubKeyResponseFieldPubKey = 1
+)
+
+// pubKeyResponseBLS12381 is a privval Message with a PubKeyResponse holding a BLS12-381 public key,
+// which is encoded by hand as the CometBFT v0.38 protos predate BLS12-381 consensus keys.
+type pubKeyResponseBLS12381 struct {
+	pubKey PubKeyBLS12381
+}
+
+var _ proto.Message = &pubKeyResponseBLS12381{}
+
+func (m *pubKeyResponseBLS12381) Reset()      { *m = pubKeyResponseBLS12381{} }
+func (*pubKeyResponseBLS12381) ProtoMessage() {}
+
+func (m *pubKeyResponseBLS12381) String() string {
+	return fmt.Sprintf("PubKeyResponse{%v}", m.pubKey)
+}
+
+// Marshal encodes Message{PubKeyResponse{PubKey: PublicKey{Bls12381: pubKey}}}.
+func (m *pubKeyResponseBLS12381) Marshal() ([]byte, error) {
+	pubKeyResponse := appendProtoBytes(nil, pubKeyResponseFieldPubKey, marshalPubKeyProtoBLS12381(m.pubKey))
+	return appendProtoBytes(nil, messageFieldPubKeyResponse, pubKeyResponse), nil
 }
 
-func (rs *ReconnRemoteSigner) handlePingRequest() cometprotoprivval.Message {
+// sentry identifies the chain node in the sentry quorum and quarantine: its node ID if any,
+// so that the chain node counts once even if cosigners dial it at different addresses, or else its privValAddr.
+func (h *remoteSignerHandler) sentry() string {
+	if h.nodeID != "" {
+		return strings.ToLower(h.nodeID)
+	}
+	return h.address
+}
+
+// allowChain returns an error if requests for the chain are not allowed from the chain node.
+func (h *remoteSignerHandler) allowChain(chainID string) error {
+	err := h.chains.Allow(chainID, ChainNode{PrivValAddr: h.address, NodeID: h.nodeID})
+	if err != nil {
+		h.logger.Error(
+			"Rejecting request",
+			"chain_id", chainID,
+			"node", h.address,
+			"reason", err,
+		)
+		totalRejectedChainRequests.WithLabelValues(h.chains.metricChainID(chainID), h.address).Inc()
+	}
+	return err
+}
+
+func (h *remoteSignerHandler) handlePingRequest() cometprotoprivval.Message {
 	return cometprotoprivval.Message{
 		Sum: &cometprotoprivval.Message_PingResponse{
 			PingResponse: &cometprotoprivval.PingResponse{},
@@ -291,17 +396,29 @@ func StartRemoteSigners(
 	services []cometservice.Service,
 	logger cometlog.Logger,
 	privVal PrivValidator,
-	nodes []string,
+	chains *ChainAllowlist,
+	privKey cometcrypto.PrivKey,
+	nodes ChainNodes,
 	maxReadSize int,
 ) ([]cometservice.Service, error) {
 	var err error
 	go StartMetrics()
 	for _, node := range nodes {
+		if node.NodeID != "" {
+			// the stock CometBFT privval listener generates a new key on every start
+			logger.Error(
+				"Pinned nodeID of chain node only matches as long as its privval key persists, "+
+					"a stock CometBFT node is rejected after it restarts",
+				"address", node.PrivValAddr,
+				"node_id", node.NodeID,
+			)
+		}
+
 		// CometBFT requires a connection within 3 seconds of start or crashes
 		// A long timeout such as 30 seconds would cause the sentry to fail in loops
 		// Use a short timeout and dial often to connect within 3 second window
 		dialer := net.Dialer{Timeout: 2 * time.Second}
-		s := NewReconnRemoteSigner(node, logger, privVal, dialer, maxReadSize)
+		s := NewReconnRemoteSigner(node, logger, privVal, chains, privKey, dialer, maxReadSize)
 
 		err = s.Start()
 		if err != nil {
@@ -20,6 +20,7 @@ type RemoteSignerGRPCServer struct {
 	cometservice.BaseService
 
 	validator  PrivValidator
+	chains     *ChainAllowlist
 	logger     cometlog.Logger
 	listenAddr string
 
@@ -31,10 +32,12 @@ type RemoteSignerGRPCServer struct {
 func NewRemoteSignerGRPCServer(
 	logger cometlog.Logger,
 	validator PrivValidator,
+	chains *ChainAllowlist,
 	listenAddr string,
 ) *RemoteSignerGRPCServer {
 	s := &RemoteSignerGRPCServer{
 		validator:  validator,
+		chains:     chains,
 		logger:     logger,
 		listenAddr: listenAddr,
 	}
@@ -68,6 +71,11 @@ func (s *RemoteSignerGRPCServer) OnStop() {
 func (s *RemoteSignerGRPCServer) PubKey(ctx context.Context, req *proto.PubKeyRequest) (*proto.PubKeyResponse, error) {
 	chainID := req.ChainId
 
+	if err := s.allowChain(chainID); err != nil {
+		return nil, err
+	}
+
+	// only counted for allowed chains, so that arbitrary chain IDs do not create metric series
 	totalPubKeyRequests.WithLabelValues(chainID).Inc()
 
 	pubKey, err := s.validator.GetPubKey(ctx, chainID)
@@ -81,7 +89,7 @@ func (s *RemoteSignerGRPCServer) PubKey(ctx context.Context, req *proto.PubKeyRe
 	}
 
 	return &proto.PubKeyResponse{
-		PubKey: pubKey,
+		PubKey: pubKey.Bytes(),
 	}, nil
 }
 
@@ -91,6 +99,10 @@ func (s *RemoteSignerGRPCServer) Sign(
 ) (*proto.SignBlockResponse, error) {
 	chainID, block := req.ChainID, BlockFromProto(req.Block)
 
+	if err := s.allowChain(chainID); err != nil {
+		return nil, err
+	}
+
 	sig, voteExtSig, timestamp, err := signAndTrack(ctx, s.logger, s.validator, chainID, block)
 	if err != nil {
 		return nil, err
@@ -103,6 +115,22 @@ func (s *RemoteSignerGRPCServer) Sign(
 	}, nil
 }
 
+// allowChain returns an error if requests for the chain are not allowed.
+// gRPC requests are not received from a chain node, so the sentries of the chain are not checked.
+func (s *RemoteSignerGRPCServer) allowChain(chainID string) error {
+	err := s.chains.Allow(chainID, ChainNode{})
+	if err != nil {
+		s.logger.Error(
+			"Rejecting request",
+			"chain_id", chainID,
+			"node", s.listenAddr,
+			"reason", err,
+		)
+		totalRejectedChainRequests.WithLabelValues(s.chains.metricChainID(chainID), s.listenAddr).Inc()
+	}
+	return err
+}
+
 func signAndTrack(
 	ctx context.Context,
 	logger cometlog.Logger,
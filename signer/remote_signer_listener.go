@@ -0,0 +1,275 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	cometcrypto "github.com/cometbft/cometbft/crypto"
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometnet "github.com/cometbft/cometbft/libs/net"
+	cometservice "github.com/cometbft/cometbft/libs/service"
+	cometp2p "github.com/cometbft/cometbft/p2p"
+	cometp2pconn "github.com/cometbft/cometbft/p2p/conn"
+)
+
+const (
+	// listenerHandshakeTimeout is how long a chain node that dialed horcrux has to complete the secret connection.
+	listenerHandshakeTimeout = connRetrySec * time.Second
+
+	// listenerMinAcceptDelay and listenerMaxAcceptDelay bound the backoff after a failed accept,
+	// e.g. when horcrux runs out of file descriptors, so that the accept loop does not spin.
+	listenerMinAcceptDelay = 5 * time.Millisecond
+	listenerMaxAcceptDelay = time.Second
+
+	// listenerSocketMode is the file mode of a unix:// socket, which is not authenticated,
+	// so only the user horcrux runs as may connect to it.
+	listenerSocketMode = 0600
+)
+
+// RemoteSignerListener listens for privval connections from chain nodes that dial horcrux, e.g. from behind NAT,
+// and responds to any signature requests over the connections using its privVal, like ReconnRemoteSigner.
+// Connections to a tcp:// address are authenticated with privKey, and dropped if the chain node is not
+// in the allowed node IDs. Connections to a unix:// socket are not authenticated, so the socket is only
+// accessible to the user horcrux runs as. All connections are closed when the listener stops.
+type RemoteSignerListener struct {
+	cometservice.BaseService
+
+	listenAddr string
+	nodeIDs    []string
+	privKey    cometcrypto.PrivKey
+	privVal    PrivValidator
+	chains     *ChainAllowlist
+
+	maxReadSize int
+
+	listener net.Listener
+
+	// conns are the connections that are served, which are closed on stop. Nil once stopped.
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// NewRemoteSignerListener returns a RemoteSignerListener that listens on the listen address of the config.
+func NewRemoteSignerListener(
+	config PrivValListener,
+	logger cometlog.Logger,
+	privVal PrivValidator,
+	chains *ChainAllowlist,
+	privKey cometcrypto.PrivKey,
+	maxReadSize int,
+) *RemoteSignerListener {
+	l := &RemoteSignerListener{
+		listenAddr:  config.ListenAddr,
+		nodeIDs:     config.NodeIDs,
+		privKey:     privKey,
+		privVal:     privVal,
+		chains:      chains,
+		maxReadSize: maxReadSize,
+	}
+
+	l.BaseService = *cometservice.NewBaseService(logger, "RemoteSignerListener", l)
+	return l
+}
+
+// OnStart implements cmn.Service.
+func (l *RemoteSignerListener) OnStart() error {
+	proto, address := cometnet.ProtocolAndAddress(l.listenAddr)
+	if proto == "unix" {
+		// remove the socket left behind by a previous run
+		if err := os.Remove(address); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove unix socket (%s): %w", address, err)
+		}
+	}
+
+	listener, err := net.Listen(proto, address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on (%s): %w", l.listenAddr, err)
+	}
+	if proto == "unix" {
+		// the socket is created with the umask, restrict it before any chain node is served
+		if err := os.Chmod(address, listenerSocketMode); err != nil {
+			listener.Close()
+			return fmt.Errorf("failed to restrict permissions of unix socket (%s): %w", address, err)
+		}
+	}
+	l.listener = listener
+	l.conns = make(map[net.Conn]struct{})
+
+	l.Logger.Info("Listening for chain nodes", "address", l.listenAddr)
+
+	go l.acceptLoop()
+	return nil
+}
+
+// OnStop implements cmn.Service.
+func (l *RemoteSignerListener) OnStop() {
+	if err := l.listener.Close(); err != nil {
+		l.Logger.Error("Failed to close listener", "address", l.listenAddr, "err", err)
+	}
+
+	l.mu.Lock()
+	for conn := range l.conns {
+		conn.Close()
+	}
+	l.conns = nil
+	l.mu.Unlock()
+
+	l.privVal.Stop()
+}
+
+// track adds the connection to the served connections, and returns false if the listener is stopped.
+func (l *RemoteSignerListener) track(conn net.Conn) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conns == nil {
+		return false
+	}
+	l.conns[conn] = struct{}{}
+	return true
+}
+
+// untrack removes the connection from the served connections.
+func (l *RemoteSignerListener) untrack(conn net.Conn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.conns, conn)
+}
+
+func (l *RemoteSignerListener) acceptLoop() {
+	var delay time.Duration
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			if !l.IsRunning() {
+				return
+			}
+			if delay == 0 {
+				delay = listenerMinAcceptDelay
+			} else if delay *= 2; delay > listenerMaxAcceptDelay {
+				delay = listenerMaxAcceptDelay
+			}
+			l.Logger.Error("Failed to accept connection", "address", l.listenAddr, "err", err, "retry_in", delay)
+			select {
+			case <-l.Quit():
+				return
+			case <-time.After(delay):
+			}
+			continue
+		}
+		delay = 0
+		go l.serve(conn)
+	}
+}
+
+// serve responds to the requests of the chain node over the connection until it is broken.
+func (l *RemoteSignerListener) serve(netConn net.Conn) {
+	if !l.track(netConn) {
+		netConn.Close()
+		return
+	}
+	defer l.untrack(netConn)
+
+	conn, nodeID, err := l.authenticate(netConn)
+	if err != nil {
+		netConn.Close()
+		totalRejectedSentryHandshakes.WithLabelValues(l.listenAddr).Inc()
+		l.Logger.Error(
+			"Rejected connection from chain node",
+			"address", l.listenAddr,
+			"remote", netConn.RemoteAddr(),
+			"err", err,
+		)
+		return
+	}
+	defer conn.Close()
+
+	l.Logger.Info("Chain node connected", "address", l.listenAddr, "node_id", nodeID)
+
+	h := remoteSignerHandler{
+		logger:  l.Logger,
+		address: l.listenAddr,
+		nodeID:  nodeID,
+		privVal: l.privVal,
+		chains:  l.chains,
+	}
+
+	for l.IsRunning() {
+		req, err := ReadMsg(conn, l.maxReadSize)
+		if err != nil {
+			l.Logger.Error(
+				"Failed to read message from connection",
+				"address", l.listenAddr,
+				"node_id", nodeID,
+				"err", err,
+			)
+			return
+		}
+
+		// handleRequest handles request errors. We always send back a response
+		res := h.handleRequest(req)
+
+		if err := WriteMsg(conn, res); err != nil {
+			l.Logger.Error(
+				"Failed to write message to connection",
+				"address", l.listenAddr,
+				"node_id", nodeID,
+				"err", err,
+			)
+			return
+		}
+	}
+}
+
+// authenticate upgrades a tcp connection to a secret connection, and returns the node ID of the chain node
+// if it is allowed. Connections to a unix socket are returned as is, without a node ID.
+func (l *RemoteSignerListener) authenticate(netConn net.Conn) (net.Conn, string, error) {
+	if _, ok := netConn.(*net.UnixConn); ok {
+		return netConn, "", nil
+	}
+
+	if err := netConn.SetDeadline(time.Now().Add(listenerHandshakeTimeout)); err != nil {
+		return nil, "", err
+	}
+	conn, err := cometp2pconn.MakeSecretConnection(netConn, l.privKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("secret connection error: %w", err)
+	}
+	if err := netConn.SetDeadline(time.Time{}); err != nil {
+		return nil, "", err
+	}
+
+	nodeID := strings.ToLower(string(cometp2p.PubKeyToID(conn.RemotePubKey())))
+	for _, allowed := range l.nodeIDs {
+		if strings.EqualFold(allowed, nodeID) {
+			return conn, nodeID, nil
+		}
+	}
+	return nil, "", fmt.Errorf("node ID (%s) is not allowed", nodeID)
+}
+
+// StartRemoteSignerListeners starts a RemoteSignerListener for each of the listeners.
+func StartRemoteSignerListeners(
+	services []cometservice.Service,
+	logger cometlog.Logger,
+	privVal PrivValidator,
+	chains *ChainAllowlist,
+	privKey cometcrypto.PrivKey,
+	listeners PrivValListeners,
+	maxReadSize int,
+) ([]cometservice.Service, error) {
+	for _, listener := range listeners {
+		l := NewRemoteSignerListener(listener, logger, privVal, chains, privKey, maxReadSize)
+
+		if err := l.Start(); err != nil {
+			return nil, err
+		}
+
+		services = append(services, l)
+	}
+	return services, nil
+}
@@ -0,0 +1,183 @@
+package signer
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometp2p "github.com/cometbft/cometbft/p2p"
+	cometp2pconn "github.com/cometbft/cometbft/p2p/conn"
+	cometprivval "github.com/cometbft/cometbft/privval"
+	cometprotoprivval "github.com/cometbft/cometbft/proto/tendermint/privval"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteSignerListener(t *testing.T) {
+	signerKey := cometcryptoed25519.GenPrivKey()
+	allowedKey := cometcryptoed25519.GenPrivKey()
+
+	vote := cometprotoprivval.Message{Sum: &cometprotoprivval.Message_SignVoteRequest{
+		SignVoteRequest: &cometprotoprivval.SignVoteRequest{
+			ChainId: testChainID,
+			Vote:    &cometproto.Vote{Type: cometproto.PrevoteType, Height: 1},
+		},
+	}}
+
+	allowedNodeID := string(cometp2p.PubKeyToID(allowedKey.PubKey()))
+
+	// the chain only accepts requests from its sentries, which the chain node is by its node ID
+	chains := NewChainAllowlist(ChainsConfig{{ChainID: testChainID, Sentries: ChainNodes{{NodeID: allowedNodeID}}}})
+
+	listen := func(config PrivValListener) *RemoteSignerListener {
+		l := NewRemoteSignerListener(
+			config,
+			cometlog.NewNopLogger(),
+			new(countingPrivValidator),
+			chains,
+			signerKey,
+			1024*1024,
+		)
+		require.NoError(t, l.Start())
+		t.Cleanup(func() { _ = l.Stop() })
+		return l
+	}
+
+	requireSigned := func(conn net.Conn) {
+		require.NoError(t, WriteMsg(conn, &vote))
+		res, err := ReadMsg(conn, 1024*1024)
+		require.NoError(t, err)
+		require.Nil(t, res.GetSignedVoteResponse().Error)
+		require.Equal(t, []byte("signature"), res.GetSignedVoteResponse().Vote.Signature)
+	}
+
+	tcp := listen(PrivValListener{
+		ListenAddr: "tcp://127.0.0.1:0",
+		NodeIDs:    []string{allowedNodeID},
+	})
+
+	dial := func(key cometcryptoed25519.PrivKey) net.Conn {
+		netConn, err := net.Dial("tcp", tcp.listener.Addr().String())
+		require.NoError(t, err)
+		conn, err := cometp2pconn.MakeSecretConnection(netConn, key)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = conn.Close() })
+
+		// the chain node authenticates horcrux by its connection key
+		require.Equal(t, signerKey.PubKey(), conn.RemotePubKey())
+		return conn
+	}
+
+	// an allowed chain node is served over the secret connection
+	allowedConn := dial(allowedKey)
+	requireSigned(allowedConn)
+
+	// the connection of any other chain node is dropped
+	conn := dial(cometcryptoed25519.GenPrivKey())
+	_, err := ReadMsg(conn, 1024*1024)
+	require.Error(t, err)
+
+	// a unix socket is served without a secret connection, but has no node ID to match a sentry
+	socket := filepath.Join(t.TempDir(), "privval.sock")
+	listen(PrivValListener{ListenAddr: "unix://" + socket})
+
+	// only the user horcrux runs as may connect to the socket
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(listenerSocketMode), info.Mode().Perm())
+
+	unixConn, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	defer unixConn.Close()
+
+	require.NoError(t, WriteMsg(unixConn, &vote))
+	res, err := ReadMsg(unixConn, 1024*1024)
+	require.NoError(t, err)
+	require.NotNil(t, res.GetSignedVoteResponse().Error)
+
+	// stopping the listener closes the connections it serves
+	require.NoError(t, tcp.Stop())
+	_, err = ReadMsg(allowedConn, 1024*1024)
+	require.Error(t, err)
+}
+
+// TestRemoteSignerListenerRelay serves a stock CometBFT privval listener through a relay that dials both
+// horcrux and the priv_validator_laddr of the chain node, like socat on the sentry host.
+func TestRemoteSignerListenerRelay(t *testing.T) {
+	// stock CometBFT generates the key of its privval listener on every start
+	nodeKey := cometcryptoed25519.GenPrivKey()
+	nodeID := string(cometp2p.PubKeyToID(nodeKey.PubKey()))
+
+	listenerConfig := PrivValListener{ListenAddr: "tcp://127.0.0.1:0", NodeIDs: []string{nodeID}}
+	l := NewRemoteSignerListener(
+		listenerConfig,
+		cometlog.NewNopLogger(),
+		new(countingPrivValidator),
+		NewChainAllowlist(ChainsConfig{{ChainID: testChainID, Sentries: ChainNodes{{NodeID: nodeID}}}}),
+		cometcryptoed25519.GenPrivKey(),
+		1024*1024,
+	)
+	require.NoError(t, l.Start())
+	t.Cleanup(func() { _ = l.Stop() })
+
+	// startChainNode listens on priv_validator_laddr with the key, like a chain node
+	startChainNode := func(key cometcryptoed25519.PrivKey) (*cometprivval.SignerClient, string) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		endpoint := cometprivval.NewSignerListenerEndpoint(cometlog.NewNopLogger(), cometprivval.NewTCPListener(ln, key))
+		client, err := cometprivval.NewSignerClient(endpoint, testChainID)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = endpoint.Stop() })
+		return client, ln.Addr().String()
+	}
+
+	// relay dials both horcrux and the chain node and forwards between them
+	relay := func(chainNodeAddr string) {
+		horcruxConn, err := net.Dial("tcp", l.listener.Addr().String())
+		require.NoError(t, err)
+		nodeConn, err := net.Dial("tcp", chainNodeAddr)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			_ = horcruxConn.Close()
+			_ = nodeConn.Close()
+		})
+
+		go func() {
+			_, _ = io.Copy(horcruxConn, nodeConn)
+			_ = horcruxConn.Close()
+		}()
+		go func() {
+			_, _ = io.Copy(nodeConn, horcruxConn)
+			_ = nodeConn.Close()
+		}()
+	}
+
+	// the chain node is served through the relay
+	client, addr := startChainNode(nodeKey)
+	relay(addr)
+	require.NoError(t, client.WaitForConnection(5*time.Second))
+
+	vote := &cometproto.Vote{Type: cometproto.PrevoteType, Height: 1}
+	require.NoError(t, client.SignVote(testChainID, vote))
+	require.Equal(t, []byte("signature"), vote.Signature)
+
+	// after a restart, the chain node listens with a new key, which is no longer in the nodeIDs
+	counter := totalRejectedSentryHandshakes.WithLabelValues(listenerConfig.ListenAddr)
+	var before dto.Metric
+	require.NoError(t, counter.Write(&before))
+
+	_, addr = startChainNode(cometcryptoed25519.GenPrivKey())
+	relay(addr)
+
+	require.Eventually(t, func() bool {
+		var after dto.Metric
+		require.NoError(t, counter.Write(&after))
+		return after.GetCounter().GetValue() == before.GetCounter().GetValue()+1
+	}, 5*time.Second, 10*time.Millisecond)
+}
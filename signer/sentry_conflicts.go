@@ -0,0 +1,345 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/log"
+	"github.com/cometbft/cometbft/libs/protoio"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+)
+
+const (
+	// raftKeyQuarantinedSentryPrefix is the prefix of the raft keys of the quarantined sentries, followed by the sentry.
+	raftKeyQuarantinedSentryPrefix = "QuarantinedSentry/"
+
+	// sentryConflictsRetention is how long the blocks that the sentries requested votes for are remembered.
+	sentryConflictsRetention = time.Minute
+)
+
+// SentryConflict is a vote that a sentry requested for a different block than another sentry, or the sentry
+// itself, requested the vote for at the same height, round and step.
+type SentryConflict struct {
+	ChainID string `json:"chainID"`
+	Height  int64  `json:"height"`
+	Round   int64  `json:"round"`
+	Step    int8   `json:"step"`
+	// Sentry requested the vote for BlockID, and ConflictingSentry requested the vote for ConflictingBlockID.
+	// Which of them requested its vote first does not tell which of them misbehaves.
+	// ConflictingSentry is the sentry itself if it requested the vote for two different blocks.
+	Sentry             string `json:"sentry"`
+	BlockID            string `json:"blockID"`
+	ConflictingSentry  string `json:"conflictingSentry"`
+	ConflictingBlockID string `json:"conflictingBlockID"`
+}
+
+// SentryQuarantinedError is returned for a sign request from a sentry that is quarantined for a conflicting vote.
+type SentryQuarantinedError struct {
+	conflict SentryConflict
+}
+
+func (e *SentryQuarantinedError) Error() string {
+	c := e.conflict
+	return fmt.Sprintf("sentry (%s) is quarantined for requesting a %s for block (%s) for chain id (%s) "+
+		"at height %d round %d, which conflicts with block (%s) requested by sentry (%s)",
+		c.Sentry, signType(c.Step), c.BlockID, c.ChainID, c.Height, c.Round, c.ConflictingBlockID, c.ConflictingSentry)
+}
+
+// SentryQuarantine holds the sentries that are quarantined for conflicting votes.
+type SentryQuarantine interface {
+	// QuarantinedSentry returns the conflict the sentry is quarantined for, or nil if it is not quarantined.
+	QuarantinedSentry(sentry string) (*SentryConflict, error)
+	// QuarantineSentry quarantines the sentry of the conflict.
+	QuarantineSentry(conflict SentryConflict) error
+}
+
+var (
+	_ SentryQuarantine = (*RaftStore)(nil)
+	_ SentryQuarantine = (*LocalSentryQuarantine)(nil)
+)
+
+// QuarantinedSentry returns the conflict the sentry is quarantined for from the raft store.
+func (s *RaftStore) QuarantinedSentry(sentry string) (*SentryConflict, error) {
+	value, err := s.Get(raftKeyQuarantinedSentryPrefix + sentry)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	conflict := new(SentryConflict)
+	if err := json.Unmarshal([]byte(value), conflict); err != nil {
+		return nil, fmt.Errorf("invalid quarantine for sentry (%s): %w", sentry, err)
+	}
+	return conflict, nil
+}
+
+// QuarantineSentry replicates the quarantine of the sentry of the conflict to all cosigners.
+// Only the leader can set it.
+func (s *RaftStore) QuarantineSentry(conflict SentryConflict) error {
+	if conflict.Sentry == "" {
+		return fmt.Errorf("sentry cannot be empty")
+	}
+	return s.Emit(raftKeyQuarantinedSentryPrefix+conflict.Sentry, conflict)
+}
+
+// ClearQuarantinedSentry lifts the quarantine of the sentry on all cosigners. Only the leader can clear it.
+func (s *RaftStore) ClearQuarantinedSentry(sentry string) error {
+	return s.Delete(raftKeyQuarantinedSentryPrefix + sentry)
+}
+
+// LocalSentryQuarantine holds the quarantined sentries in memory, for single signer mode,
+// so sentries are quarantined until the signer restarts.
+type LocalSentryQuarantine struct {
+	mu        sync.Mutex
+	conflicts map[string]SentryConflict
+}
+
+func NewLocalSentryQuarantine() *LocalSentryQuarantine {
+	return &LocalSentryQuarantine{conflicts: make(map[string]SentryConflict)}
+}
+
+func (q *LocalSentryQuarantine) QuarantinedSentry(sentry string) (*SentryConflict, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	conflict, ok := q.conflicts[sentry]
+	if !ok {
+		return nil, nil
+	}
+	return &conflict, nil
+}
+
+func (q *LocalSentryQuarantine) QuarantineSentry(conflict SentryConflict) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.conflicts[conflict.Sentry] = conflict
+	return nil
+}
+
+type sentryConflictsKey struct {
+	chainID string
+	hrs     HRSKey
+}
+
+type sentryConflictsRequest struct {
+	sentry  string
+	blockID string
+}
+
+type sentryConflictsVote struct {
+	requests []sentryConflictsRequest
+	// quarantined are the sentries that were already quarantined for the vote.
+	quarantined map[string]struct{}
+	created     time.Time
+}
+
+// SentryMonitor detects sentries that request votes for conflicting blocks at the same height, round and step,
+// which may be an attempt to make the signer equivocate. Each conflict is counted for both sentries
+// and logged as an error. The order in which the requests arrived does not tell which sentry misbehaves,
+// so if quarantine is enabled, only a sentry that requested the vote for a different block than a majority
+// of the sentries that requested the vote is quarantined, so that a single compromised sentry can not quarantine
+// the honest ones. A sentry that requests the vote for two different blocks itself is always quarantined.
+// The sign requests of quarantined sentries are rejected until an operator has investigated and cleared them.
+type SentryMonitor struct {
+	logger     log.Logger
+	quarantine SentryQuarantine
+	enabled    bool
+
+	mu    sync.Mutex
+	votes map[sentryConflictsKey]*sentryConflictsVote
+}
+
+// NewSentryMonitor returns a SentryMonitor that quarantines the sentries of conflicts in the quarantine if enabled.
+func NewSentryMonitor(logger log.Logger, quarantine SentryQuarantine, enabled bool) *SentryMonitor {
+	return &SentryMonitor{
+		logger:     logger,
+		quarantine: quarantine,
+		enabled:    enabled,
+		votes:      make(map[sentryConflictsKey]*sentryConflictsVote),
+	}
+}
+
+// Check returns a SentryQuarantinedError if the sentry is quarantined. Requests that were not received
+// from a chain node, e.g. through the gRPC API, have no sentry and are never rejected.
+func (m *SentryMonitor) Check(sentry string) error {
+	if m == nil || sentry == "" {
+		return nil
+	}
+	conflict, err := m.quarantine.QuarantinedSentry(sentry)
+	if err != nil {
+		return err
+	}
+	if conflict != nil {
+		totalQuarantinedSentryRequests.WithLabelValues(sentry).Inc()
+		return &SentryQuarantinedError{conflict: *conflict}
+	}
+	return nil
+}
+
+// Record records the block that the sentry requested the vote for, and reports a conflict if another sentry,
+// or the sentry itself, requested the vote for a different block. If quarantine is enabled, the sentries
+// that disagree with the majority, and the sentries that requested two different blocks, are quarantined.
+// If the sentry of the request is quarantined, a SentryQuarantinedError is returned, so that the vote is not signed.
+// Votes for nil never conflict, as a sentry that does not see the block in time votes for nil.
+// Proposals are not recorded, as each sentry builds its own block.
+func (m *SentryMonitor) Record(chainID string, block Block, sentry string) error {
+	if m == nil || sentry == "" || block.Step == stepPropose {
+		return nil
+	}
+
+	blockID, err := voteBlockID(block.SignBytes)
+	if err != nil {
+		return err
+	}
+	if blockID == "" {
+		return nil
+	}
+
+	conflict, misbehaving := m.add(sentryConflictsKey{chainID: chainID, hrs: block.HRSKey()}, sentry, blockID)
+	if conflict != nil {
+		totalConflictingSentryRequests.WithLabelValues(chainID, conflict.Sentry).Inc()
+		if conflict.ConflictingSentry != conflict.Sentry {
+			totalConflictingSentryRequests.WithLabelValues(chainID, conflict.ConflictingSentry).Inc()
+		}
+		m.logger.Error(
+			"Sentries requested votes for conflicting blocks",
+			"chain_id", chainID,
+			"height", conflict.Height,
+			"round", conflict.Round,
+			"type", signType(conflict.Step),
+			"sentry", conflict.Sentry,
+			"block_id", conflict.BlockID,
+			"conflicting_sentry", conflict.ConflictingSentry,
+			"conflicting_block_id", conflict.ConflictingBlockID,
+			"quarantine", m.enabled,
+		)
+	}
+
+	if !m.enabled {
+		return nil
+	}
+	var quarantinedErr error
+	for _, c := range misbehaving {
+		if err := m.quarantine.QuarantineSentry(c); err != nil {
+			return fmt.Errorf("failed to quarantine sentry (%s): %w", c.Sentry, err)
+		}
+		m.logger.Error(
+			"Quarantined sentry",
+			"chain_id", chainID,
+			"sentry", c.Sentry,
+			"block_id", c.BlockID,
+			"conflicting_sentry", c.ConflictingSentry,
+			"conflicting_block_id", c.ConflictingBlockID,
+		)
+		if c.Sentry == sentry {
+			quarantinedErr = &SentryQuarantinedError{conflict: c}
+		}
+	}
+	return quarantinedErr
+}
+
+// add records the request of the vote, and returns the conflict it caused, if any, and the conflicts
+// of the sentries that turned out to misbehave and were not quarantined for the vote yet.
+func (m *SentryMonitor) add(key sentryConflictsKey, sentry, blockID string) (*SentryConflict, []SentryConflict) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for k, v := range m.votes {
+		if now.Sub(v.created) > sentryConflictsRetention {
+			delete(m.votes, k)
+		}
+	}
+
+	vote, ok := m.votes[key]
+	if !ok {
+		vote = &sentryConflictsVote{quarantined: make(map[string]struct{}), created: now}
+		m.votes[key] = vote
+	}
+
+	newConflict := func(sentry, blockID string, conflicting sentryConflictsRequest) SentryConflict {
+		return SentryConflict{
+			ChainID:            key.chainID,
+			Height:             key.hrs.Height,
+			Round:              key.hrs.Round,
+			Step:               key.hrs.Step,
+			Sentry:             sentry,
+			BlockID:            blockID,
+			ConflictingSentry:  conflicting.sentry,
+			ConflictingBlockID: conflicting.blockID,
+		}
+	}
+
+	var conflict *SentryConflict
+	for _, r := range vote.requests {
+		if r.sentry == sentry && r.blockID == blockID {
+			// the same vote requested again, e.g. forwarded by another cosigner
+			return nil, nil
+		}
+		if conflict == nil && r.blockID != blockID {
+			c := newConflict(sentry, blockID, r)
+			conflict = &c
+		}
+	}
+	vote.requests = append(vote.requests, sentryConflictsRequest{sentry: sentry, blockID: blockID})
+	if conflict == nil {
+		return nil, nil
+	}
+
+	var misbehaving []SentryConflict
+	quarantine := func(c SentryConflict) {
+		if _, ok := vote.quarantined[c.Sentry]; !ok {
+			vote.quarantined[c.Sentry] = struct{}{}
+			misbehaving = append(misbehaving, c)
+		}
+	}
+
+	// a sentry that requested the vote for two different blocks misbehaves, whatever the other sentries requested
+	first := make(map[string]sentryConflictsRequest)
+	equivocating := make(map[string]struct{})
+	for _, r := range vote.requests {
+		f, ok := first[r.sentry]
+		if !ok {
+			first[r.sentry] = r
+			continue
+		}
+		if f.blockID != r.blockID {
+			equivocating[r.sentry] = struct{}{}
+			quarantine(newConflict(r.sentry, r.blockID, f))
+		}
+	}
+
+	// otherwise, only a sentry that disagrees with the majority of the other sentries misbehaves
+	var requests []sentryConflictsRequest
+	counts := make(map[string]int)
+	for _, r := range vote.requests {
+		if _, ok := equivocating[r.sentry]; !ok && first[r.sentry] == r {
+			requests = append(requests, r)
+			counts[r.blockID]++
+		}
+	}
+	for _, majority := range requests {
+		if 2*counts[majority.blockID] <= len(requests) {
+			continue
+		}
+		for _, r := range requests {
+			if r.blockID != majority.blockID {
+				quarantine(newConflict(r.sentry, r.blockID, majority))
+			}
+		}
+		break
+	}
+
+	return conflict, misbehaving
+}
+
+// voteBlockID returns the hash of the block of the canonical vote, or an empty string for a vote for nil.
+func voteBlockID(signBytes []byte) (string, error) {
+	var vote cometproto.CanonicalVote
+	if err := protoio.UnmarshalDelimited(signBytes, &vote); err != nil {
+		return "", fmt.Errorf("failed to unmarshal sign bytes into vote: %w", err)
+	}
+	if vote.BlockID == nil || len(vote.BlockID.Hash) == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%X", vote.BlockID.Hash), nil
+}
@@ -0,0 +1,133 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+)
+
+func testVoteBlock(height int64, voteType cometproto.SignedMsgType, blockHash byte) Block {
+	var blockID cometproto.BlockID
+	if blockHash != 0 {
+		hash := make([]byte, 32)
+		hash[0] = blockHash
+		blockID = cometproto.BlockID{Hash: hash, PartSetHeader: cometproto.PartSetHeader{Total: 1, Hash: hash}}
+	}
+	return VoteToBlock(testChainID, &cometproto.Vote{
+		Height:    height,
+		Type:      voteType,
+		BlockID:   blockID,
+		Timestamp: time.Now(),
+	})
+}
+
+func TestSentryMonitor(t *testing.T) {
+	m := NewSentryMonitor(cometlog.NewNopLogger(), NewLocalSentryQuarantine(), false)
+
+	// sentries that agree, or vote for nil, do not conflict
+	require.NoError(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrevoteType, 1), "sentry-1"))
+	require.NoError(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrevoteType, 1), "sentry-2"))
+	require.NoError(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrevoteType, 0), "sentry-3"))
+
+	// proposals and other steps are not compared
+	proposal := ProposalToBlock(testChainID, &cometproto.Proposal{Height: 1, Type: cometproto.ProposalType})
+	require.NoError(t, m.Record(testChainID, proposal, "sentry-3"))
+	require.NoError(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrecommitType, 2), "sentry-3"))
+
+	// without quarantine, conflicts are only reported
+	require.NoError(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrevoteType, 2), "sentry-3"))
+	require.NoError(t, m.Check("sentry-3"))
+
+	m = NewSentryMonitor(cometlog.NewNopLogger(), NewLocalSentryQuarantine(), true)
+
+	// without a majority, it is not known which sentry misbehaves, so neither is quarantined
+	require.NoError(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrevoteType, 1), "sentry-1"))
+	require.NoError(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrevoteType, 2), "sentry-2"))
+	require.NoError(t, m.Check("sentry-1"))
+	require.NoError(t, m.Check("sentry-2"))
+
+	// only the sentry that disagrees with the majority is quarantined, regardless of which requested its vote first
+	require.NoError(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrevoteType, 1), "sentry-3"))
+	var quarantinedErr *SentryQuarantinedError
+	require.ErrorAs(t, m.Check("sentry-2"), &quarantinedErr)
+	require.Equal(t, "sentry-2", quarantinedErr.conflict.Sentry)
+	require.Equal(t, "sentry-1", quarantinedErr.conflict.ConflictingSentry)
+	require.NoError(t, m.Check("sentry-1"))
+	require.NoError(t, m.Check("sentry-3"))
+	require.NoError(t, m.Check(""))
+
+	// the sentry of the request is told that it is quarantined
+	require.NoError(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrecommitType, 1), "sentry-1"))
+	require.NoError(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrecommitType, 1), "sentry-3"))
+	require.ErrorAs(t, m.Record(testChainID, testVoteBlock(1, cometproto.PrecommitType, 2), "sentry-4"), &quarantinedErr)
+	require.Equal(t, "sentry-4", quarantinedErr.conflict.Sentry)
+
+	// a sentry that requests a vote for two different blocks is quarantined on its own
+	require.NoError(t, m.Record(testChainID, testVoteBlock(2, cometproto.PrevoteType, 1), "sentry-5"))
+	require.NoError(t, m.Record(testChainID, testVoteBlock(2, cometproto.PrevoteType, 1), "sentry-5"))
+	require.ErrorAs(t, m.Record(testChainID, testVoteBlock(2, cometproto.PrevoteType, 2), "sentry-5"), &quarantinedErr)
+	require.Equal(t, "sentry-5", quarantinedErr.conflict.Sentry)
+	require.Equal(t, "sentry-5", quarantinedErr.conflict.ConflictingSentry)
+	require.NoError(t, m.Check("sentry-1"))
+
+	// a nil monitor accepts all requests
+	var nilMonitor *SentryMonitor
+	require.NoError(t, nilMonitor.Check("sentry-2"))
+	require.NoError(t, nilMonitor.Record(testChainID, testVoteBlock(1, cometproto.PrevoteType, 3), "sentry-2"))
+}
+
+func TestThresholdValidatorSentryQuarantine(t *testing.T) {
+	cosigners, pubKey := getTestLocalCosigners(t, 2, 3)
+
+	leader := &MockLeader{id: 1}
+
+	validator := NewThresholdValidator(
+		cometlog.NewNopLogger(),
+		cosigners[0].config,
+		2,
+		time.Second,
+		1,
+		cosigners[0],
+		[]Cosigner{cosigners[1]},
+		leader,
+	)
+	defer validator.Stop()
+
+	leader.leader = validator
+
+	validator.SetSentryMonitor(NewSentryMonitor(cometlog.NewNopLogger(), NewLocalSentryQuarantine(), true))
+
+	ctx := context.Background()
+
+	require.NoError(t, validator.LoadSignStateIfNecessary(testChainID))
+
+	validator.nonceCache.LoadN(ctx, 1)
+
+	block := testVoteBlock(1, cometproto.PrevoteType, 1)
+	sig, _, _, err := validator.Sign(withSentry(ctx, "sentry-1"), testChainID, block)
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature(block.SignBytes, sig))
+
+	// the second sentry requests a prevote for another block at the same height and round, which is not signed
+	_, _, _, err = validator.Sign(withSentry(ctx, "sentry-2"), testChainID, testVoteBlock(1, cometproto.PrevoteType, 2))
+	require.Error(t, err)
+
+	// the third sentry agrees with the first, so the second sentry is quarantined
+	_, _, _, _ = validator.Sign(withSentry(ctx, "sentry-3"), testChainID, testVoteBlock(1, cometproto.PrevoteType, 1))
+
+	// the sentry of the conflict is not served anymore, while the other sentries are
+	var quarantinedErr *SentryQuarantinedError
+	_, _, _, err = validator.Sign(withSentry(ctx, "sentry-2"), testChainID, testVoteBlock(2, cometproto.PrevoteType, 1))
+	require.ErrorAs(t, err, &quarantinedErr)
+
+	validator.nonceCache.LoadN(ctx, 1)
+
+	block = testVoteBlock(2, cometproto.PrevoteType, 1)
+	sig, _, _, err = validator.Sign(withSentry(ctx, "sentry-1"), testChainID, block)
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature(block.SignBytes, sig))
+}
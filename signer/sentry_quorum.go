@@ -0,0 +1,152 @@
+package signer
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cometbft/cometbft/libs/protoio"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+)
+
+const (
+	// defaultSentryQuorumTimeout is how long a vote waits for the sentry quorum if the chain sets no timeout.
+	defaultSentryQuorumTimeout = time.Second
+
+	// sentryQuorumRetention is how long the sentries that requested a vote are remembered,
+	// so that requests from sentries that are late still find the quorum.
+	sentryQuorumRetention = time.Minute
+)
+
+type sentryContextKey struct{}
+
+// withSentry returns a context for a sign request received from the chain node with the privValAddr.
+func withSentry(ctx context.Context, sentry string) context.Context {
+	if sentry == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, sentryContextKey{}, sentry)
+}
+
+// sentryFromContext returns the privValAddr of the chain node the sign request was received from,
+// or an empty string if the request was not received from a chain node, e.g. through the gRPC API.
+func sentryFromContext(ctx context.Context) string {
+	sentry, _ := ctx.Value(sentryContextKey{}).(string)
+	return sentry
+}
+
+// SentryQuorumError is returned for a vote that not enough sentries requested before the timeout.
+type SentryQuorumError struct {
+	chainID   string
+	hrs       HRSKey
+	sentries  int
+	threshold int
+}
+
+func (e *SentryQuorumError) Error() string {
+	return fmt.Sprintf("only %d of %d sentries requested the same %s for chain id (%s) at height %d round %d",
+		e.sentries, e.threshold, signType(e.hrs.Step), e.chainID, e.hrs.Height, e.hrs.Round)
+}
+
+type sentryQuorumKey struct {
+	chainID string
+	hrs     HRSKey
+	digest  [sha256.Size]byte
+}
+
+type sentryQuorumVote struct {
+	sentries map[string]struct{}
+	created  time.Time
+	// reached is closed once the threshold number of sentries requested the vote.
+	reached chan struct{}
+}
+
+// sentryQuorum tracks which sentries requested each vote, so that votes are only signed
+// once a threshold number of distinct sentries requested the same vote.
+type sentryQuorum struct {
+	mu    sync.Mutex
+	votes map[sentryQuorumKey]*sentryQuorumVote
+}
+
+func newSentryQuorum() *sentryQuorum {
+	return &sentryQuorum{votes: make(map[sentryQuorumKey]*sentryQuorumVote)}
+}
+
+// Wait records that the sentry requested the vote of the block, and waits until the threshold number
+// of distinct sentries requested the same vote, or returns a SentryQuorumError after the timeout.
+// Votes are compared without their timestamp, which each sentry sets from its own clock,
+// and without the vote extension, which is not deterministic.
+func (q *sentryQuorum) Wait(
+	ctx context.Context,
+	chainID string,
+	block Block,
+	sentry string,
+	threshold int,
+	timeout time.Duration,
+) error {
+	signBytes, err := voteSignBytesWithoutTimestamp(block.SignBytes)
+	if err != nil {
+		return err
+	}
+	key := sentryQuorumKey{chainID: chainID, hrs: block.HRSKey(), digest: sha256.Sum256(signBytes)}
+
+	vote := q.add(key, sentry, threshold)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-vote.reached:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		q.mu.Lock()
+		sentries := len(vote.sentries)
+		q.mu.Unlock()
+		return &SentryQuorumError{chainID: chainID, hrs: key.hrs, sentries: sentries, threshold: threshold}
+	}
+}
+
+func (q *sentryQuorum) add(key sentryQuorumKey, sentry string, threshold int) *sentryQuorumVote {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	for k, v := range q.votes {
+		if now.Sub(v.created) > sentryQuorumRetention {
+			delete(q.votes, k)
+		}
+	}
+
+	vote, ok := q.votes[key]
+	if !ok {
+		vote = &sentryQuorumVote{
+			sentries: make(map[string]struct{}),
+			created:  now,
+			reached:  make(chan struct{}),
+		}
+		q.votes[key] = vote
+	}
+
+	if _, ok := vote.sentries[sentry]; !ok {
+		vote.sentries[sentry] = struct{}{}
+		if len(vote.sentries) == threshold {
+			close(vote.reached)
+		}
+	}
+
+	return vote
+}
+
+// voteSignBytesWithoutTimestamp returns the sign bytes of the canonical vote with its timestamp cleared.
+func voteSignBytesWithoutTimestamp(signBytes []byte) ([]byte, error) {
+	var vote cometproto.CanonicalVote
+	if err := protoio.UnmarshalDelimited(signBytes, &vote); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sign bytes into vote: %w", err)
+	}
+	vote.Timestamp = time.Time{}
+	return protoio.MarshalDelimited(&vote)
+}
@@ -0,0 +1,110 @@
+package signer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cometlog "github.com/cometbft/cometbft/libs/log"
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestSentryQuorum(t *testing.T) {
+	q := newSentryQuorum()
+	ctx := context.Background()
+
+	prevote := func(blockHash byte) Block {
+		hash := make([]byte, 32)
+		hash[0] = blockHash
+		return VoteToBlock(testChainID, &cometproto.Vote{
+			Height:    1,
+			Type:      cometproto.PrevoteType,
+			BlockID:   cometproto.BlockID{Hash: hash, PartSetHeader: cometproto.PartSetHeader{Total: 1, Hash: hash}},
+			Timestamp: time.Now(),
+		})
+	}
+
+	// a single sentry can not reach the quorum, even if it sends the vote more than once
+	var quorumErr *SentryQuorumError
+	require.ErrorAs(t, q.Wait(ctx, testChainID, prevote(1), "sentry-1", 2, 50*time.Millisecond), &quorumErr)
+	require.ErrorAs(t, q.Wait(ctx, testChainID, prevote(1), "sentry-1", 2, 50*time.Millisecond), &quorumErr)
+
+	// votes that only differ by timestamp count towards the same quorum
+	var eg errgroup.Group
+	eg.Go(func() error {
+		return q.Wait(ctx, testChainID, prevote(2), "sentry-1", 2, time.Second)
+	})
+	eg.Go(func() error {
+		return q.Wait(ctx, testChainID, prevote(2), "sentry-2", 2, time.Second)
+	})
+	require.NoError(t, eg.Wait())
+
+	// a late sentry finds the quorum already reached
+	require.NoError(t, q.Wait(ctx, testChainID, prevote(2), "sentry-3", 2, 50*time.Millisecond))
+
+	// a conflicting vote from another sentry does not count towards the quorum
+	require.ErrorAs(t, q.Wait(ctx, testChainID, prevote(3), "sentry-3", 2, 50*time.Millisecond), &quorumErr)
+}
+
+func TestThresholdValidatorSentryQuorum(t *testing.T) {
+	cosigners, pubKey := getTestLocalCosigners(t, 2, 3)
+
+	cosigners[0].config.Config.Chains = ChainsConfig{{
+		ChainID:      testChainID,
+		SentryQuorum: &SentryQuorum{Threshold: 2, Timeout: "100ms"},
+	}}
+
+	leader := &MockLeader{id: 1}
+
+	validator := NewThresholdValidator(
+		cometlog.NewNopLogger(),
+		cosigners[0].config,
+		2,
+		time.Second,
+		1,
+		cosigners[0],
+		[]Cosigner{cosigners[1]},
+		leader,
+	)
+	defer validator.Stop()
+
+	leader.leader = validator
+
+	ctx := context.Background()
+
+	require.NoError(t, validator.LoadSignStateIfNecessary(testChainID))
+
+	block := VoteToBlock(testChainID, &cometproto.Vote{Height: 1, Type: cometproto.PrevoteType, Timestamp: time.Now()})
+
+	_, _, _, err := validator.Sign(withSentry(ctx, "sentry-1"), testChainID, block)
+	var quorumErr *SentryQuorumError
+	require.ErrorAs(t, err, &quorumErr)
+
+	validator.nonceCache.LoadN(ctx, 1)
+
+	// the second sentry completes the quorum of the vote requested by the first sentry
+	sig, _, _, err := validator.Sign(withSentry(ctx, "sentry-2"), testChainID, block)
+	require.NoError(t, err)
+	require.True(t, pubKey.VerifySignature(block.SignBytes, sig))
+
+	// proposals do not wait for the quorum
+	validator.nonceCache.LoadN(ctx, 1)
+
+	proposal := ProposalToBlock(testChainID, &cometproto.Proposal{Height: 2, Type: cometproto.ProposalType})
+	_, _, _, err = validator.Sign(withSentry(ctx, "sentry-1"), testChainID, proposal)
+	require.NoError(t, err)
+
+	validator.nonceCache.LoadN(ctx, 1)
+
+	// votes through the gRPC API are rejected, unless they are explicitly allowed without a sentry
+	block = VoteToBlock(testChainID, &cometproto.Vote{Height: 2, Type: cometproto.PrevoteType, Timestamp: time.Now()})
+	_, _, _, err = validator.Sign(ctx, testChainID, block)
+	require.ErrorContains(t, err, "not requested by a chain node")
+
+	cosigners[0].config.Config.Chains[0].SentryQuorum.AllowWithoutSentry = true
+
+	_, _, _, err = validator.Sign(ctx, testChainID, block)
+	require.NoError(t, err)
+}
@@ -0,0 +1,35 @@
+package signer
+
+import "fmt"
+
+// loadThresholdSignerKeyFromPKCS11ShardStore reads the key shard of cosigner id for the chain from the
+// PKCS#11 token configured under thresholdMode.pkcs11ShardStorage.
+//
+// The token is only storage for the key shard at rest: PKCS#11 has no vendor-neutral mechanism for the scalar
+// arithmetic of threshold signatures, so the key shard is read into memory once and signs like a shard file.
+func loadThresholdSignerKeyFromPKCS11ShardStore(
+	config *RuntimeConfig,
+	id int,
+	chainID string,
+) (CosignerKey, error) {
+	cfg := config.Config.ThresholdModeConfig.PKCS11ShardStorage
+
+	store, err := OpenPKCS11ShardStore(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	label := cfg.KeyLabelFor(chainID)
+
+	key, err := store.ReadCosignerKey(label)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyThresholdSignerKey(key, id, fmt.Sprintf("PKCS#11 object %s", label)); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
@@ -0,0 +1,84 @@
+package signer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// signCoalescerKey identifies a sign request by its HRST and its full sign bytes,
+// so that only identical requests are coalesced.
+type signCoalescerKey struct {
+	chainID                string
+	hrst                   HRSTKey
+	signBytes              string
+	voteExtensionSignBytes string
+}
+
+// signCall is a signing process in flight, whose result is shared by all requests for the same block.
+type signCall struct {
+	done chan struct{}
+
+	signature              []byte
+	voteExtensionSignature []byte
+	timestamp              time.Time
+	err                    error
+}
+
+// signCoalescer coalesces identical sign requests that are in flight at the same time, e.g. the same vote
+// requested by several sentries, so that they share one signing process instead of contending
+// for the sign state, and all receive the signature as soon as it exists.
+type signCoalescer struct {
+	mu    sync.Mutex
+	calls map[signCoalescerKey]*signCall
+}
+
+func newSignCoalescer() *signCoalescer {
+	return &signCoalescer{calls: make(map[signCoalescerKey]*signCall)}
+}
+
+// Do signs the block with sign, or waits for the result of an identical request that is already in flight.
+// The signing process is shared, so it is not canceled with the context of the request that started it,
+// but each request stops waiting when its own context is done.
+func (c *signCoalescer) Do(
+	ctx context.Context,
+	chainID string,
+	block Block,
+	sign func(ctx context.Context) ([]byte, []byte, time.Time, error),
+) ([]byte, []byte, time.Time, error) {
+	key := signCoalescerKey{
+		chainID:                chainID,
+		hrst:                   block.HRSTKey(),
+		signBytes:              string(block.SignBytes),
+		voteExtensionSignBytes: string(block.VoteExtensionSignBytes),
+	}
+
+	c.mu.Lock()
+	call, ok := c.calls[key]
+	if ok {
+		c.mu.Unlock()
+		totalCoalescedSignRequests.WithLabelValues(chainID).Inc()
+	} else {
+		call = &signCall{done: make(chan struct{})}
+		c.calls[key] = call
+		c.mu.Unlock()
+
+		signCtx := context.WithoutCancel(ctx)
+		go func() {
+			call.signature, call.voteExtensionSignature, call.timestamp, call.err = sign(signCtx)
+
+			c.mu.Lock()
+			delete(c.calls, key)
+			c.mu.Unlock()
+
+			close(call.done)
+		}()
+	}
+
+	select {
+	case <-call.done:
+		return call.signature, call.voteExtensionSignature, call.timestamp, call.err
+	case <-ctx.Done():
+		return nil, nil, block.Timestamp, ctx.Err()
+	}
+}
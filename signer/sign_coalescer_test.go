@@ -0,0 +1,67 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestSignCoalescer(t *testing.T) {
+	c := newSignCoalescer()
+	ctx := context.Background()
+
+	block := Block{Height: 1, Step: stepPrevote, SignBytes: []byte("vote"), Timestamp: time.Unix(1, 0)}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	sign := func(context.Context) ([]byte, []byte, time.Time, error) {
+		calls.Add(1)
+		<-release
+		return []byte("signature"), nil, block.Timestamp, nil
+	}
+
+	// identical requests in flight share one signing process
+	var eg errgroup.Group
+	for i := 0; i < 3; i++ {
+		eg.Go(func() error {
+			sig, _, _, err := c.Do(ctx, testChainID, block, sign)
+			if err == nil && string(sig) != "signature" {
+				return fmt.Errorf("unexpected signature (%s)", sig)
+			}
+			return err
+		})
+	}
+
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	// a request that stops waiting does not cancel the shared signing process
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, _, err := c.Do(cancelCtx, testChainID, block, sign)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// requests for a different block are not coalesced
+	other := block
+	other.SignBytes = []byte("other vote")
+	go func() { _, _, _, _ = c.Do(ctx, testChainID, other, sign) }()
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+
+	// nor are requests whose sign bytes only match concatenated with the vote extension sign bytes
+	split := block
+	split.SignBytes, split.VoteExtensionSignBytes = []byte("vo"), []byte("te")
+	go func() { _, _, _, _ = c.Do(ctx, testChainID, split, sign) }()
+	require.Eventually(t, func() bool { return calls.Load() == 3 }, time.Second, time.Millisecond)
+
+	close(release)
+	require.NoError(t, eg.Wait())
+
+	// once the signing process completed, the next request signs again
+	_, _, _, err = c.Do(ctx, testChainID, block, sign)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), calls.Load())
+}
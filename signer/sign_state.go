@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"sync"
 
 	cometbytes "github.com/cometbft/cometbft/libs/bytes"
@@ -108,6 +109,11 @@ type SignState struct {
 	SignBytes              cometbytes.HexBytes `json:"signbytes,omitempty"`
 	VoteExtensionSignature []byte              `json:"vote_ext_signature,omitempty"`
 
+	// Nonces and VoteExtensionNonces are the nonces that a cosigner signed with,
+	// which it returns with an existing partial signature for the leader to verify it.
+	Nonces              CosignerNonces `json:"nonces,omitempty"`
+	VoteExtensionNonces CosignerNonces `json:"vote_ext_nonces,omitempty"`
+
 	filePath string
 
 	// mu protects the cache and is used for signaling with cond.
@@ -116,7 +122,10 @@ type SignState struct {
 	cond  *cond.Cond
 }
 
-func (signState *SignState) existingSignatureOrErrorIfRegression(hrst HRSTKey, signBytes []byte) ([]byte, error) {
+func (signState *SignState) existingSignatureOrErrorIfRegression(
+	hrst HRSTKey,
+	signBytes []byte,
+) (*SignStateConsensus, error) {
 	signState.mu.RLock()
 	defer signState.mu.RUnlock()
 
@@ -133,7 +142,16 @@ func (signState *SignState) existingSignatureOrErrorIfRegression(hrst HRSTKey, s
 	// If the HRS is the same the sign bytes may still differ by timestamp
 	// It is ok to re-sign a different timestamp if that is the only difference in the sign bytes
 	if bytes.Equal(signBytes, signState.SignBytes) {
-		return signState.Signature, nil
+		return &SignStateConsensus{
+			Height:                 signState.Height,
+			Round:                  signState.Round,
+			Step:                   signState.Step,
+			Signature:              signState.Signature,
+			VoteExtensionSignature: signState.VoteExtensionSignature,
+			SignBytes:              signState.SignBytes,
+			Nonces:                 signState.Nonces,
+			VoteExtensionNonces:    signState.VoteExtensionNonces,
+		}, nil
 	} else if err := signState.OnlyDifferByTimestamp(signBytes); err != nil {
 		return nil, err
 	}
@@ -157,6 +175,8 @@ type SignStateConsensus struct {
 	Signature              []byte
 	VoteExtensionSignature []byte
 	SignBytes              cometbytes.HexBytes
+	Nonces                 CosignerNonces
+	VoteExtensionNonces    CosignerNonces
 }
 
 func (signState SignStateConsensus) HRSKey() HRSKey {
@@ -231,6 +251,8 @@ func (signState *SignState) blockDoubleSign(ssc SignStateConsensus) (*SignState,
 	signState.Signature = ssc.Signature
 	signState.SignBytes = ssc.SignBytes
 	signState.VoteExtensionSignature = ssc.VoteExtensionSignature
+	signState.Nonces = ssc.Nonces
+	signState.VoteExtensionNonces = ssc.VoteExtensionNonces
 
 	return signState.lockedCopy(), nil
 }
@@ -285,6 +307,8 @@ func (signState *SignState) lockedCopy() *SignState {
 		Signature:              sig,
 		SignBytes:              signBz,
 		VoteExtensionSignature: voteExtSig,
+		Nonces:                 slices.Clone(signState.Nonces),
+		VoteExtensionNonces:    slices.Clone(signState.VoteExtensionNonces),
 		filePath:               signState.filePath,
 	}
 }
@@ -441,6 +465,8 @@ func (signState *SignState) FreshCache() *SignState {
 		Signature:              signState.Signature,
 		SignBytes:              signState.SignBytes,
 		VoteExtensionSignature: signState.VoteExtensionSignature,
+		Nonces:                 signState.Nonces,
+		VoteExtensionNonces:    signState.VoteExtensionNonces,
 		cache:                  make(map[HRSKey]SignStateConsensus),
 
 		filePath: signState.filePath,
@@ -459,6 +485,8 @@ func (signState *SignState) FreshCache() *SignState {
 		Signature:              signState.Signature,
 		SignBytes:              signState.SignBytes,
 		VoteExtensionSignature: signState.VoteExtensionSignature,
+		Nonces:                 signState.Nonces,
+		VoteExtensionNonces:    signState.VoteExtensionNonces,
 	}
 
 	return newSignState
@@ -493,12 +521,7 @@ func LoadOrCreateSignState(filepath string) (*SignState, error) {
 		}
 		// the only scenario where we want to create a new sign state file is when the file does not exist.
 		// Make an empty sign state and save it.
-		state := &SignState{
-			filePath: filepath,
-			cache:    make(map[HRSKey]SignStateConsensus),
-		}
-		state.cond = cond.New(&state.mu)
-
+		state := newEmptySignState(filepath)
 		saveSignState(state)
 		return state, nil
 	}
@@ -506,6 +529,29 @@ func LoadOrCreateSignState(filepath string) (*SignState, error) {
 	return LoadSignState(filepath)
 }
 
+// LoadSignStateIfExists loads the sign state from filepath.
+// If the file does not exist, an empty sign state is returned, but unlike
+// LoadOrCreateSignState, it is not saved, so that read-only commands leave no files behind.
+func LoadSignStateIfExists(filepath string) (*SignState, error) {
+	if _, err := os.Stat(filepath); err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("unexpected error checking file existence (%s): %w", filepath, err)
+		}
+		return newEmptySignState(filepath), nil
+	}
+
+	return LoadSignState(filepath)
+}
+
+func newEmptySignState(filepath string) *SignState {
+	state := &SignState{
+		filePath: filepath,
+		cache:    make(map[HRSKey]SignStateConsensus),
+	}
+	state.cond = cond.New(&state.mu)
+	return state
+}
+
 // OnlyDifferByTimestamp returns true if the sign bytes of the sign state
 // are the same as the new sign bytes excluding the timestamp.
 func (signState *SignState) OnlyDifferByTimestamp(signBytes []byte) error {
@@ -0,0 +1,173 @@
+package signer
+
+import (
+	"fmt"
+	"sort"
+
+	cometbytes "github.com/cometbft/cometbft/libs/bytes"
+	cometjson "github.com/cometbft/cometbft/libs/json"
+)
+
+const (
+	// SignStateInterchangeFormat identifies a sign state interchange file.
+	SignStateInterchangeFormat = "horcrux-sign-state-interchange"
+
+	// SignStateInterchangeVersion is the version of the sign state interchange format.
+	SignStateInterchangeVersion = 1
+)
+
+// SignStateInterchange is a versioned format to move the high watermarks of all chains
+// between horcrux clusters, or between horcrux and other signers.
+type SignStateInterchange struct {
+	Format  string                      `json:"format"`
+	Version int                         `json:"version"`
+	Chains  []ChainSignStateInterchange `json:"chains"`
+}
+
+// ChainSignStateInterchange is the high watermark of a chain. The sign bytes and signature
+// of the last signed block are optional.
+type ChainSignStateInterchange struct {
+	ChainID   string              `json:"chain_id"`
+	Height    int64               `json:"height"`
+	Round     int64               `json:"round"`
+	Step      int8                `json:"step"`
+	SignBytes cometbytes.HexBytes `json:"signbytes,omitempty"`
+	Signature []byte              `json:"signature,omitempty"`
+}
+
+func (c ChainSignStateInterchange) HRSKey() HRSKey {
+	return HRSKey{
+		Height: c.Height,
+		Round:  c.Round,
+		Step:   c.Step,
+	}
+}
+
+// SignStateConsensus returns the sign state of the high watermark, with the sign bytes and signature if any.
+func (c ChainSignStateInterchange) SignStateConsensus() SignStateConsensus {
+	return SignStateConsensus{
+		Height:    c.Height,
+		Round:     c.Round,
+		Step:      c.Step,
+		Signature: c.Signature,
+		SignBytes: c.SignBytes,
+	}
+}
+
+// NewSignStateInterchange creates a new SignStateInterchange with the high watermarks of the chains.
+// The chains are sorted by chain ID.
+func NewSignStateInterchange(chains ...ChainSignStateInterchange) *SignStateInterchange {
+	sort.Slice(chains, func(i, j int) bool {
+		return chains[i].ChainID < chains[j].ChainID
+	})
+	return &SignStateInterchange{
+		Format:  SignStateInterchangeFormat,
+		Version: SignStateInterchangeVersion,
+		Chains:  chains,
+	}
+}
+
+// NewChainSignStateInterchange returns the high watermark of a chain, the greater of its priv-validator
+// and share sign states. The share sign state only holds partial signatures, so the sign bytes and
+// signature are only included if the priv-validator sign state is at the high watermark.
+func NewChainSignStateInterchange(chainID string, pv, cs *SignState) ChainSignStateInterchange {
+	pv.mu.RLock()
+	defer pv.mu.RUnlock()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	hrs := pv.lockedHrsKey()
+	chain := ChainSignStateInterchange{
+		ChainID:   chainID,
+		Height:    hrs.Height,
+		Round:     hrs.Round,
+		Step:      hrs.Step,
+		SignBytes: pv.SignBytes,
+		Signature: pv.Signature,
+	}
+
+	if csHRS := cs.lockedHrsKey(); csHRS.GreaterThan(hrs) {
+		chain.Height, chain.Round, chain.Step = csHRS.Height, csHRS.Round, csHRS.Step
+		chain.SignBytes, chain.Signature = nil, nil
+	}
+
+	return chain
+}
+
+// LoadSignStateInterchange parses and validates a sign state interchange file.
+func LoadSignStateInterchange(bz []byte) (*SignStateInterchange, error) {
+	ic := new(SignStateInterchange)
+	if err := cometjson.Unmarshal(bz, ic); err != nil {
+		return nil, fmt.Errorf("error parsing sign state interchange: %w", err)
+	}
+	if err := ic.Validate(); err != nil {
+		return nil, err
+	}
+	return ic, nil
+}
+
+// Validate returns an error if the interchange is not of a supported format and version,
+// or if a chain is invalid or duplicated.
+func (ic *SignStateInterchange) Validate() error {
+	if ic.Format != SignStateInterchangeFormat {
+		return fmt.Errorf("invalid sign state interchange format (%s), must be %s",
+			ic.Format, SignStateInterchangeFormat)
+	}
+	if ic.Version != SignStateInterchangeVersion {
+		return fmt.Errorf("unsupported sign state interchange version (%d), must be %d",
+			ic.Version, SignStateInterchangeVersion)
+	}
+
+	seen := make(map[string]struct{}, len(ic.Chains))
+	for _, chain := range ic.Chains {
+		if chain.ChainID == "" {
+			return fmt.Errorf("chain id cannot be empty")
+		}
+		if _, ok := seen[chain.ChainID]; ok {
+			return fmt.Errorf("duplicate chain id (%s)", chain.ChainID)
+		}
+		seen[chain.ChainID] = struct{}{}
+
+		if chain.Height < 0 || chain.Round < 0 {
+			return fmt.Errorf("[%s] height and round must not be negative", chain.ChainID)
+		}
+		if chain.Step < 0 || chain.Step > stepPrecommit {
+			return fmt.Errorf("[%s] invalid step (%d)", chain.ChainID, chain.Step)
+		}
+		if (len(chain.Signature) == 0) != (len(chain.SignBytes) == 0) {
+			return fmt.Errorf("[%s] signbytes and signature must either both be set or both be empty", chain.ChainID)
+		}
+	}
+
+	return nil
+}
+
+// CheckImport returns an error if importing the high watermark of the chain would lower the high watermark
+// of the sign state, and false if the sign state is already at the high watermark.
+func (c ChainSignStateInterchange) CheckImport(ss *SignState) (bool, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+
+	hrs := ss.lockedHrsKey()
+	if hrs.GreaterThan(c.HRSKey()) {
+		return false, fmt.Errorf("[%s] refusing to lower the high watermark from %d.%d.%d to %d.%d.%d",
+			c.ChainID, hrs.Height, hrs.Round, hrs.Step, c.Height, c.Round, c.Step)
+	}
+	return hrs != c.HRSKey(), nil
+}
+
+// CheckSlashingProtection returns an error if the slashing protection database already holds a signature
+// of the chain for a greater HRS than the high watermark of the chain.
+func (c ChainSignStateInterchange) CheckSlashingProtection(db *SlashingProtectionDB) error {
+	for _, signer := range []string{slashingProtectionValidator, slashingProtectionCosigner} {
+		hrs, ok, err := db.lastHRS(signer, c.ChainID)
+		if err != nil {
+			return fmt.Errorf("[%s] %w", c.ChainID, err)
+		}
+		if ok && hrs.GreaterThan(c.HRSKey()) {
+			return fmt.Errorf("[%s] refusing to import %d.%d.%d, the slashing protection database already holds "+
+				"a signature for %d.%d.%d", c.ChainID, c.Height, c.Round, c.Step, hrs.Height, hrs.Round, hrs.Step)
+		}
+	}
+	return nil
+}
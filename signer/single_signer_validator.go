@@ -6,6 +6,8 @@ import (
 	"os"
 	"sync"
 	"time"
+
+	cometcrypto "github.com/cometbft/cometbft/crypto"
 )
 
 var _ PrivValidator = &SingleSignerValidator{}
@@ -15,18 +17,35 @@ var _ PrivValidator = &SingleSignerValidator{}
 type SingleSignerValidator struct {
 	config     *RuntimeConfig
 	chainState sync.Map
+
+	sentryMonitor *SentryMonitor
 }
 
 // SingleSignerChainState holds the priv validator and associated mutex for a single chain.
 type SingleSignerChainState struct {
 	filePV *FilePV
 
+	// key is the key of the chain, and rotatedKey the new key of the scheduled key rotation of the chain, if any,
+	// which signs from the activation height of keyRotation on. The key that signs the height of a request
+	// is set as the key of the filePV, so that both keys share the last sign state.
+	key         FilePVKey
+	rotatedKey  *FilePVKey
+	keyRotation *KeyRotation
+
 	// The filePV does not have any locking internally for signing operations.
 	// The high-watermark/last-signed-state within the FilePV prevents double sign
 	// as long as operations are synchronous. This lock is used to ensure that.
 	pvMutex sync.Mutex
 }
 
+// keyForHeight returns the key that signs the height.
+func (cs *SingleSignerChainState) keyForHeight(height int64) FilePVKey {
+	if cs.rotatedKey != nil && cs.keyRotation.Active(height) {
+		return *cs.rotatedKey
+	}
+	return cs.key
+}
+
 // NewSingleSignerValidator constructs a validator for single-sign mode (not recommended).
 // NewThresholdValidator is recommended, but single-sign mode can be used for convenience.
 func NewSingleSignerValidator(config *RuntimeConfig) *SingleSignerValidator {
@@ -35,22 +54,28 @@ func NewSingleSignerValidator(config *RuntimeConfig) *SingleSignerValidator {
 	}
 }
 
-// GetPubKey implements types.PrivValidator
-func (pv *SingleSignerValidator) GetPubKey(_ context.Context, chainID string) ([]byte, error) {
+// SetSentryMonitor sets the monitor that detects, and optionally quarantines, sentries that request
+// conflicting votes.
+func (pv *SingleSignerValidator) SetSentryMonitor(monitor *SentryMonitor) {
+	pv.sentryMonitor = monitor
+}
+
+// GetPubKey returns the public key of the key that signs the height after the last signed height.
+// Implements types.PrivValidator
+func (pv *SingleSignerValidator) GetPubKey(_ context.Context, chainID string) (cometcrypto.PubKey, error) {
 	chainState, err := pv.loadChainStateIfNecessary(chainID)
 	if err != nil {
 		return nil, err
 	}
-	pubKey, err := chainState.filePV.GetPubKey()
-	if err != nil {
-		return nil, err
-	}
-	return pubKey.Bytes(), nil
+	chainState.pvMutex.Lock()
+	defer chainState.pvMutex.Unlock()
+
+	return chainState.keyForHeight(chainState.filePV.LastSignState.Height + 1).PubKey, nil
 }
 
 // SignVote implements types.PrivValidator
 func (pv *SingleSignerValidator) Sign(
-	_ context.Context,
+	ctx context.Context,
 	chainID string,
 	block Block) (
 	[]byte,
@@ -58,6 +83,20 @@ func (pv *SingleSignerValidator) Sign(
 	time.Time,
 	error,
 ) {
+	// single signer mode has no raft, so only the haltHeight of the chains config applies
+	chain, _ := pv.config.Config.Chains.Get(chainID)
+	if err := checkHaltHeight(chainID, block.Height, chain.HaltHeight); err != nil {
+		return nil, nil, block.Timestamp, err
+	}
+
+	sentry := sentryFromContext(ctx)
+	if err := pv.sentryMonitor.Check(sentry); err != nil {
+		return nil, nil, block.Timestamp, err
+	}
+	if err := pv.sentryMonitor.Record(chainID, block, sentry); err != nil {
+		return nil, nil, block.Timestamp, err
+	}
+
 	chainState, err := pv.loadChainStateIfNecessary(chainID)
 	if err != nil {
 		return nil, nil, block.Timestamp, err
@@ -65,6 +104,8 @@ func (pv *SingleSignerValidator) Sign(
 	chainState.pvMutex.Lock()
 	defer chainState.pvMutex.Unlock()
 
+	chainState.filePV.Key = chainState.keyForHeight(block.Height)
+
 	return chainState.filePV.Sign(chainID, block)
 }
 
@@ -99,7 +140,18 @@ func (pv *SingleSignerValidator) loadChainStateIfNecessary(chainID string) (*Sin
 	}
 
 	chainState := &SingleSignerChainState{
-		filePV: filePV,
+		filePV:      filePV,
+		key:         filePV.Key,
+		keyRotation: pv.config.keyRotation(chainID),
+	}
+
+	if chainState.keyRotation != nil {
+		rotatedKeyFile := pv.config.KeyFilePathRotation(chainID)
+		rotatedPV, err := LoadFilePV(rotatedKeyFile, stateFile, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rotated key file (%s) - %w", rotatedKeyFile, err)
+		}
+		chainState.rotatedKey = &rotatedPV.Key
 	}
 	pv.chainState.Store(chainID, chainState)
 
@@ -9,10 +9,13 @@ import (
 	"testing"
 
 	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometcryptoencoding "github.com/cometbft/cometbft/crypto/encoding"
+	cometcryptosecp256k1 "github.com/cometbft/cometbft/crypto/secp256k1"
 	"github.com/cometbft/cometbft/crypto/tmhash"
 	cometjson "github.com/cometbft/cometbft/libs/json"
 	cometrand "github.com/cometbft/cometbft/libs/rand"
 	cometprivval "github.com/cometbft/cometbft/privval"
+	cometprotocrypto "github.com/cometbft/cometbft/proto/tendermint/crypto"
 	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	"github.com/stretchr/testify/require"
 )
@@ -125,3 +128,117 @@ func TestSingleSignerValidator(t *testing.T) {
 		"vote extension signature verification failed")
 
 }
+
+func TestSingleSignerValidatorSecp256k1(t *testing.T) {
+	tmpDir := t.TempDir()
+	stateDir := filepath.Join(tmpDir, "state")
+
+	err := os.MkdirAll(stateDir, 0700)
+	require.NoError(t, err)
+
+	runtimeConfig := &RuntimeConfig{
+		HomeDir:  tmpDir,
+		StateDir: stateDir,
+	}
+
+	privateKey := cometcryptosecp256k1.GenPrivKey()
+
+	marshaled, err := cometjson.Marshal(cometprivval.FilePVKey{
+		Address: privateKey.PubKey().Address(),
+		PubKey:  privateKey.PubKey(),
+		PrivKey: privateKey,
+	})
+	require.NoError(t, err)
+
+	err = os.WriteFile(runtimeConfig.KeyFilePathSingleSigner(testChainID), marshaled, 0600)
+	require.NoError(t, err)
+
+	validator := NewSingleSignerValidator(runtimeConfig)
+
+	ctx := context.Background()
+
+	// the public key keeps the key type of the priv validator key file
+	pubKey, err := validator.GetPubKey(ctx, testChainID)
+	require.NoError(t, err)
+	require.Equal(t, privateKey.PubKey(), pubKey)
+
+	pk, err := cometcryptoencoding.PubKeyToProto(pubKey)
+	require.NoError(t, err)
+	require.IsType(t, &cometprotocrypto.PublicKey_Secp256K1{}, pk.Sum)
+
+	// only precommits for a block carry a vote extension
+	randHash := cometrand.Bytes(tmhash.Size)
+	precommit := cometproto.Vote{
+		Height:    1,
+		Round:     0,
+		Type:      cometproto.PrecommitType,
+		BlockID:   cometproto.BlockID{Hash: randHash, PartSetHeader: cometproto.PartSetHeader{Total: 5, Hash: randHash}},
+		Timestamp: time.Now(),
+		Extension: []byte("test"),
+	}
+
+	block := VoteToBlock(testChainID, &precommit)
+	sig, voteExtSig, _, err := validator.Sign(ctx, testChainID, block)
+	require.NoError(t, err)
+
+	require.True(t, pubKey.VerifySignature(block.SignBytes, sig), "signature verification failed")
+	require.True(t, pubKey.VerifySignature(block.VoteExtensionSignBytes, voteExtSig),
+		"vote extension signature verification failed")
+}
+
+func TestSingleSignerValidatorKeyRotation(t *testing.T) {
+	tmpDir := t.TempDir()
+	stateDir := filepath.Join(tmpDir, "state")
+
+	err := os.MkdirAll(stateDir, 0700)
+	require.NoError(t, err)
+
+	runtimeConfig := &RuntimeConfig{
+		HomeDir:  tmpDir,
+		StateDir: stateDir,
+		Config: Config{
+			Chains: ChainsConfig{{
+				ChainID:     testChainID,
+				KeyRotation: &KeyRotation{ActivationHeight: 3, KeyFile: "rotated_priv_validator_key.json"},
+			}},
+		},
+	}
+
+	oldKey, newKey := cometcryptoed25519.GenPrivKey(), cometcryptoed25519.GenPrivKey()
+
+	for keyFile, privateKey := range map[string]cometcryptoed25519.PrivKey{
+		runtimeConfig.KeyFilePathSingleSigner(testChainID): oldKey,
+		runtimeConfig.KeyFilePathRotation(testChainID):     newKey,
+	} {
+		marshaled, err := cometjson.Marshal(cometprivval.FilePVKey{
+			Address: privateKey.PubKey().Address(),
+			PubKey:  privateKey.PubKey(),
+			PrivKey: privateKey,
+		})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(keyFile, marshaled, 0600))
+	}
+
+	validator := NewSingleSignerValidator(runtimeConfig)
+
+	ctx := context.Background()
+
+	pubKey, err := validator.GetPubKey(ctx, testChainID)
+	require.NoError(t, err)
+	require.Equal(t, oldKey.PubKey(), pubKey)
+
+	block := VoteToBlock(testChainID, &cometproto.Vote{Height: 2, Type: cometproto.PrevoteType, Timestamp: time.Now()})
+	sig, _, _, err := validator.Sign(ctx, testChainID, block)
+	require.NoError(t, err)
+	require.True(t, oldKey.PubKey().VerifySignature(block.SignBytes, sig))
+
+	// the next height to sign is the activation height
+	pubKey, err = validator.GetPubKey(ctx, testChainID)
+	require.NoError(t, err)
+	require.Equal(t, newKey.PubKey(), pubKey)
+
+	block = VoteToBlock(testChainID, &cometproto.Vote{Height: 3, Type: cometproto.PrevoteType, Timestamp: time.Now()})
+	sig, _, _, err = validator.Sign(ctx, testChainID, block)
+	require.NoError(t, err)
+	require.True(t, newKey.PubKey().VerifySignature(block.SignBytes, sig))
+}
@@ -0,0 +1,323 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	cometbytes "github.com/cometbft/cometbft/libs/bytes"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// slashingProtectionValidator is the bucket of the combined signatures of the ThresholdValidator.
+	slashingProtectionValidator = "validator"
+
+	// slashingProtectionCosigner is the bucket of the partial signatures of the LocalCosigner.
+	slashingProtectionCosigner = "cosigner"
+
+	// slashingProtectionHRSKeySize is the size of the height, round and step prefix of a record key.
+	slashingProtectionHRSKeySize = 8 + 8 + 1
+)
+
+// SlashingProtectionRecord is a signature that was produced for a height, round and step (HRS).
+type SlashingProtectionRecord struct {
+	Height                 int64               `json:"height"`
+	Round                  int64               `json:"round"`
+	Step                   int8                `json:"step"`
+	SignBytesHash          cometbytes.HexBytes `json:"signbytes_hash"`
+	SignBytes              cometbytes.HexBytes `json:"signbytes"`
+	Signature              []byte              `json:"signature"`
+	VoteExtensionSignature []byte              `json:"vote_ext_signature,omitempty"`
+	// Nonces and VoteExtensionNonces are the nonces that a cosigner signed with.
+	Nonces              CosignerNonces `json:"nonces,omitempty"`
+	VoteExtensionNonces CosignerNonces `json:"vote_ext_nonces,omitempty"`
+}
+
+func (r SlashingProtectionRecord) HRSKey() HRSKey {
+	return HRSKey{
+		Height: r.Height,
+		Round:  r.Round,
+		Step:   r.Step,
+	}
+}
+
+// Timestamp returns the timestamp of the sign bytes of the record.
+func (r SlashingProtectionRecord) Timestamp(chainID string) (time.Time, error) {
+	hrst, _, err := verifySignPayload(chainID, r.SignBytes, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(0, hrst.Timestamp), nil
+}
+
+// NewSlashingProtectionRecord returns the record of a signature.
+func NewSlashingProtectionRecord(ssc SignStateConsensus) SlashingProtectionRecord {
+	return SlashingProtectionRecord{
+		Height:                 ssc.Height,
+		Round:                  ssc.Round,
+		Step:                   ssc.Step,
+		SignBytes:              ssc.SignBytes,
+		Signature:              ssc.Signature,
+		VoteExtensionSignature: ssc.VoteExtensionSignature,
+	}
+}
+
+// SignStateConsensus returns the sign state of the record.
+func (r SlashingProtectionRecord) SignStateConsensus() SignStateConsensus {
+	return SignStateConsensus{
+		Height:                 r.Height,
+		Round:                  r.Round,
+		Step:                   r.Step,
+		Signature:              r.Signature,
+		VoteExtensionSignature: r.VoteExtensionSignature,
+		SignBytes:              r.SignBytes,
+	}
+}
+
+// SlashingProtectionDB is an append-only history of every signature horcrux produced, per chain.
+// Unlike the sign state files, which only hold the high watermark, the history survives the restore
+// of a stale backup of the sign state files, so that horcrux never signs conflicting data for an HRS
+// it already signed.
+//
+// Every record is committed and fsynced before its signature is released.
+// Records below the retained height window are pruned.
+type SlashingProtectionDB struct {
+	db            *bolt.DB
+	retainHeights int64
+}
+
+// OpenSlashingProtectionDB opens, or creates, the slashing protection database at path.
+// If retainHeights is greater than zero, only the records of the latest retainHeights heights
+// of each chain are kept, otherwise the full history is kept.
+func OpenSlashingProtectionDB(path string, retainHeights int64) (*SlashingProtectionDB, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening slashing protection database (%s): %w", path, err)
+	}
+
+	return &SlashingProtectionDB{
+		db:            db,
+		retainHeights: retainHeights,
+	}, nil
+}
+
+// Close closes the database.
+func (s *SlashingProtectionDB) Close() error {
+	return s.db.Close()
+}
+
+// slashingProtectionKey is the key of a record. Records are ordered by HRS, and there may be
+// several records for one HRS whose sign bytes only differ by timestamp.
+func slashingProtectionKey(hrs HRSKey, signBytesHash []byte) []byte {
+	key := make([]byte, slashingProtectionHRSKeySize, slashingProtectionHRSKeySize+len(signBytesHash))
+	binary.BigEndian.PutUint64(key[0:8], uint64(hrs.Height))
+	binary.BigEndian.PutUint64(key[8:16], uint64(hrs.Round))
+	key[16] = byte(hrs.Step)
+	return append(key, signBytesHash...)
+}
+
+func slashingProtectionKeyHRS(key []byte) HRSKey {
+	return HRSKey{
+		Height: int64(binary.BigEndian.Uint64(key[0:8])),
+		Round:  int64(binary.BigEndian.Uint64(key[8:16])),
+		Step:   int8(key[16]),
+	}
+}
+
+// chainBucket returns the bucket of the records of a signer for a chain, or nil if there are none.
+func chainBucket(tx *bolt.Tx, signer, chainID string) *bolt.Bucket {
+	b := tx.Bucket([]byte(signer))
+	if b == nil {
+		return nil
+	}
+	return b.Bucket([]byte(chainID))
+}
+
+// ExistingSignature checks the sign bytes for an HRS against the history of the signer for the chain.
+// It returns the existing record if the same sign bytes, or sign bytes that only differ by timestamp, were already
+// signed, and nil if it is okay to sign. Like the FilePV of CometBFT, the signature of the existing record must then
+// be used with the timestamp of its sign bytes, see SlashingProtectionRecord.Timestamp, instead of signing again.
+// It returns an error if a greater HRS was already signed, or if conflicting data was signed for the HRS.
+func (s *SlashingProtectionDB) ExistingSignature(
+	signer, chainID string,
+	hrs HRSKey,
+	signBytes []byte,
+) (*SlashingProtectionRecord, error) {
+	var existing *SlashingProtectionRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := chainBucket(tx, signer, chainID)
+		if b == nil {
+			return nil
+		}
+		var err error
+		existing, err = checkSlashingProtection(b, hrs, signBytes)
+		return err
+	})
+	return existing, err
+}
+
+// checkSlashingProtection is the check of ExistingSignature within a transaction.
+func checkSlashingProtection(b *bolt.Bucket, hrs HRSKey, signBytes []byte) (*SlashingProtectionRecord, error) {
+	c := b.Cursor()
+
+	lastKey, _ := c.Last()
+	if lastKey == nil {
+		return nil, nil
+	}
+	last := slashingProtectionKeyHRS(lastKey)
+
+	hash := sha256.Sum256(signBytes)
+	prefix := slashingProtectionKey(hrs, nil)
+
+	var sameBlock *SlashingProtectionRecord
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		var record SlashingProtectionRecord
+		if err := json.Unmarshal(v, &record); err != nil {
+			return nil, fmt.Errorf("error reading slashing protection record: %w", err)
+		}
+
+		if bytes.Equal(record.SignBytesHash, hash[:]) {
+			return &record, nil
+		}
+
+		// The sign bytes may only differ by timestamp, then the existing signature is reused with its timestamp
+		if err := onlyDifferByTimestamp(record.Step, record.SignBytes, signBytes); err != nil {
+			return nil, err
+		}
+		if sameBlock == nil {
+			sameBlock = &record
+		}
+	}
+
+	if last.GreaterThan(hrs) {
+		// not signed before, or only differs by timestamp from an HRS we are already beyond
+		return nil, newSlashingProtectionRegressionError(hrs, last)
+	}
+
+	return sameBlock, nil
+}
+
+// Record appends the record to the history of the signer for the chain, and prunes the records below
+// the retained height window. The record is checked again within the transaction, so that concurrent
+// sign requests can not both record conflicting data. Recording a record that exists is a no-op.
+func (s *SlashingProtectionDB) Record(signer, chainID string, record SlashingProtectionRecord) error {
+	hash := sha256.Sum256(record.SignBytes)
+	record.SignBytesHash = hash[:]
+
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		sb, err := tx.CreateBucketIfNotExists([]byte(signer))
+		if err != nil {
+			return err
+		}
+		b, err := sb.CreateBucketIfNotExists([]byte(chainID))
+		if err != nil {
+			return err
+		}
+
+		existing, err := checkSlashingProtection(b, record.HRSKey(), record.SignBytes)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return nil
+		}
+
+		if err := b.Put(slashingProtectionKey(record.HRSKey(), record.SignBytesHash), value); err != nil {
+			return err
+		}
+
+		return s.prune(b, record.Height)
+	})
+}
+
+// prune deletes the records below the retained height window of the latest height.
+func (s *SlashingProtectionDB) prune(b *bolt.Bucket, latestHeight int64) error {
+	if s.retainHeights <= 0 || latestHeight <= s.retainHeights {
+		return nil
+	}
+
+	threshold := slashingProtectionKey(HRSKey{Height: latestHeight - s.retainHeights}, nil)
+
+	// collect copies of the keys first, deleting while iterating a cursor may skip keys
+	var keys [][]byte
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k, threshold) < 0; k, _ = c.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Records returns the records of the signer for the chain, ordered by HRS.
+func (s *SlashingProtectionDB) Records(signer, chainID string) ([]SlashingProtectionRecord, error) {
+	var records []SlashingProtectionRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := chainBucket(tx, signer, chainID)
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(_, v []byte) error {
+			var record SlashingProtectionRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return fmt.Errorf("error reading slashing protection record: %w", err)
+			}
+			records = append(records, record)
+			return nil
+		})
+	})
+	return records, err
+}
+
+// lastHRS returns the greatest HRS the signer recorded for the chain, and false if there are no records.
+func (s *SlashingProtectionDB) lastHRS(signer, chainID string) (HRSKey, bool, error) {
+	var (
+		last HRSKey
+		ok   bool
+	)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := chainBucket(tx, signer, chainID)
+		if b == nil {
+			return nil
+		}
+		if k, _ := b.Cursor().Last(); k != nil {
+			last, ok = slashingProtectionKeyHRS(k), true
+		}
+		return nil
+	})
+	return last, ok, err
+}
+
+// SlashingProtectionRegressionError is returned when the slashing protection database already holds
+// a signature for a greater HRS than the one requested.
+type SlashingProtectionRegressionError struct {
+	requested, last HRSKey
+}
+
+func (e *SlashingProtectionRegressionError) Error() string {
+	return fmt.Sprintf(
+		"slashing protection: already signed %d.%d.%d, refusing to sign %d.%d.%d",
+		e.last.Height, e.last.Round, e.last.Step,
+		e.requested.Height, e.requested.Round, e.requested.Step,
+	)
+}
+
+func newSlashingProtectionRegressionError(requested, last HRSKey) *SlashingProtectionRegressionError {
+	return &SlashingProtectionRegressionError{
+		requested: requested,
+		last:      last,
+	}
+}
@@ -0,0 +1,151 @@
+package signer
+
+import (
+	"crypto/sha256"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
+	comet "github.com/cometbft/cometbft/types"
+	"github.com/stretchr/testify/require"
+)
+
+func testVoteSignBytes(height, round int64, step int8, blockHash []byte, timestamp time.Time) []byte {
+	vote := cometproto.Vote{
+		Type:      StepToType(step),
+		Height:    height,
+		Round:     int32(round),
+		Timestamp: timestamp,
+	}
+	if blockHash != nil {
+		// block IDs must hold a hash of the block hash size
+		hash := sha256.Sum256(blockHash)
+		vote.BlockID = cometproto.BlockID{
+			Hash:          hash[:],
+			PartSetHeader: cometproto.PartSetHeader{Total: 1, Hash: hash[:]},
+		}
+	}
+	return comet.VoteSignBytes(testChainID, &vote)
+}
+
+func testSlashingProtectionRecord(height, round int64, step int8, signBytes []byte) SlashingProtectionRecord {
+	return SlashingProtectionRecord{
+		Height:    height,
+		Round:     round,
+		Step:      step,
+		SignBytes: signBytes,
+		Signature: []byte{byte(height), byte(round), byte(step)},
+	}
+}
+
+func TestSlashingProtectionDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "slashing_protection.db")
+	db, err := OpenSlashingProtectionDB(path, 0)
+	require.NoError(t, err)
+
+	now := time.Now()
+	blockHash := []byte("block hash")
+	signBytes := testVoteSignBytes(10, 0, stepPrevote, blockHash, now)
+	hrs := HRSKey{Height: 10, Round: 0, Step: stepPrevote}
+
+	// nothing signed yet
+	existing, err := db.ExistingSignature(slashingProtectionCosigner, testChainID, hrs, signBytes)
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	require.NoError(t, db.Record(slashingProtectionCosigner, testChainID,
+		testSlashingProtectionRecord(10, 0, stepPrevote, signBytes)))
+
+	// same sign bytes, the existing signature is returned
+	existing, err = db.ExistingSignature(slashingProtectionCosigner, testChainID, hrs, signBytes)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	require.Equal(t, []byte{10, 0, byte(stepPrevote)}, existing.Signature)
+
+	// conflicting block, refused
+	_, err = db.ExistingSignature(slashingProtectionCosigner, testChainID, hrs,
+		testVoteSignBytes(10, 0, stepPrevote, []byte("other block hash"), now))
+	require.Error(t, err)
+	require.Error(t, db.Record(slashingProtectionCosigner, testChainID,
+		testSlashingProtectionRecord(10, 0, stepPrevote,
+			testVoteSignBytes(10, 0, stepPrevote, []byte("other block hash"), now))))
+
+	// nil vote after a vote for a block, refused
+	_, err = db.ExistingSignature(slashingProtectionCosigner, testChainID, hrs,
+		testVoteSignBytes(10, 0, stepPrevote, nil, now))
+	require.Error(t, err)
+
+	// only the timestamp differs, the existing signature is reused with its timestamp
+	laterSignBytes := testVoteSignBytes(10, 0, stepPrevote, blockHash, now.Add(time.Second))
+	existing, err = db.ExistingSignature(slashingProtectionCosigner, testChainID, hrs, laterSignBytes)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	require.Equal(t, []byte{10, 0, byte(stepPrevote)}, existing.Signature)
+	timestamp, err := existing.Timestamp(testChainID)
+	require.NoError(t, err)
+	require.True(t, now.Equal(timestamp))
+	require.NoError(t, db.Record(slashingProtectionCosigner, testChainID,
+		testSlashingProtectionRecord(10, 0, stepPrevote, laterSignBytes)))
+
+	// other signers and chains have their own history
+	existing, err = db.ExistingSignature(slashingProtectionValidator, testChainID, hrs,
+		testVoteSignBytes(10, 0, stepPrevote, []byte("other block hash"), now))
+	require.NoError(t, err)
+	require.Nil(t, existing)
+	existing, err = db.ExistingSignature(slashingProtectionCosigner, testChainID2, hrs,
+		testVoteSignBytes(10, 0, stepPrevote, []byte("other block hash"), now))
+	require.NoError(t, err)
+	require.Nil(t, existing)
+
+	precommitSignBytes := testVoteSignBytes(10, 0, stepPrecommit, blockHash, now)
+	require.NoError(t, db.Record(slashingProtectionCosigner, testChainID,
+		testSlashingProtectionRecord(10, 0, stepPrecommit, precommitSignBytes)))
+
+	// the history survives a restart
+	require.NoError(t, db.Close())
+	db, err = OpenSlashingProtectionDB(path, 0)
+	require.NoError(t, err)
+	defer db.Close()
+
+	// a signature of the history is still returned for a lower HRS
+	existing, err = db.ExistingSignature(slashingProtectionCosigner, testChainID, hrs, signBytes)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+
+	// but a lower HRS is not signed again, not even with a different timestamp
+	_, err = db.ExistingSignature(slashingProtectionCosigner, testChainID, hrs,
+		testVoteSignBytes(10, 0, stepPrevote, blockHash, now.Add(2*time.Second)))
+	require.ErrorAs(t, err, new(*SlashingProtectionRegressionError))
+
+	_, err = db.ExistingSignature(slashingProtectionCosigner, testChainID, HRSKey{Height: 9, Step: stepPrecommit},
+		testVoteSignBytes(9, 0, stepPrecommit, blockHash, now))
+	require.ErrorAs(t, err, new(*SlashingProtectionRegressionError))
+
+	records, err := db.Records(slashingProtectionCosigner, testChainID)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, signBytes, []byte(records[0].SignBytes))
+	require.Equal(t, stepPrecommit, records[1].Step)
+}
+
+func TestSlashingProtectionDBPrune(t *testing.T) {
+	db, err := OpenSlashingProtectionDB(filepath.Join(t.TempDir(), "slashing_protection.db"), 2)
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	for height := int64(1); height <= 5; height++ {
+		for _, step := range []int8{stepPrevote, stepPrecommit} {
+			signBytes := testVoteSignBytes(height, 0, step, []byte("block hash"), now)
+			require.NoError(t, db.Record(slashingProtectionValidator, testChainID,
+				testSlashingProtectionRecord(height, 0, step, signBytes)))
+		}
+	}
+
+	records, err := db.Records(slashingProtectionValidator, testChainID)
+	require.NoError(t, err)
+	require.Len(t, records, 6)
+	require.Equal(t, int64(3), records[0].Height)
+	require.Equal(t, int64(5), records[5].Height)
+}
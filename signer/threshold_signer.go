@@ -1,8 +1,18 @@
 package signer
 
-import "time"
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
 
-// Interface for the local signer whether it's a soft sign or HSM
+	"filippo.io/edwards25519"
+	cometcrypto "github.com/cometbft/cometbft/crypto"
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+)
+
+// ThresholdSigner signs with the key shard of this cosigner for the sign scheme of the cluster.
+// The key shard is held in memory, whether it is loaded from a shard file or from a PKCS#11 token.
 type ThresholdSigner interface {
 	// PubKey returns the public key bytes for the combination of all cosigners.
 	PubKey() []byte
@@ -10,14 +20,167 @@ type ThresholdSigner interface {
 	// Sign signs a byte payload with the provided nonces.
 	Sign(nonces []Nonce, payload []byte) ([]byte, error)
 
+	// VerifyPartialSignature verifies a partial signature against the public key shard of the cosigner
+	// that produced it, given the nonces of all cosigners that participated in the signature.
+	VerifyPartialSignature(payload []byte, nonces *CosignerUUIDNonces, signature PartialSignature) error
+
 	// CombineSignatures combines multiple partial signatures to a full signature.
 	CombineSignatures([]PartialSignature) ([]byte, error)
 }
 
+// ErrUnverifiablePartialSignature is returned when a partial signature can not be verified individually,
+// e.g. because the key shard does not have the public key shard of the cosigner.
+// Such partial signatures are only verified as part of the combined signature.
+var ErrUnverifiablePartialSignature = errors.New("partial signature can not be verified")
+
+// NewThresholdSigner returns the threshold signer of cosigner id for the chain,
+// for the sign scheme and key shard storage of the cluster.
+func NewThresholdSigner(config *RuntimeConfig, id int, chainID string) (ThresholdSigner, error) {
+	var (
+		key CosignerKey
+		err error
+	)
+	if config.Config.ThresholdModeConfig.PKCS11ShardStorage != nil {
+		key, err = loadThresholdSignerKeyFromPKCS11ShardStore(config, id, chainID)
+	} else {
+		key, err = loadThresholdSignerKey(config, id, chainID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newThresholdSignerForKey(config, key)
+}
+
+// newThresholdSignerForKey returns the threshold signer of the key type of the key shard, and for Ed25519
+// key shards of the sign scheme of the cluster. BLS12-381 key shards do not depend on the sign scheme,
+// as BLS signatures do not need nonces.
+func newThresholdSignerForKey(config *RuntimeConfig, key CosignerKey) (ThresholdSigner, error) {
+	switch key := key.(type) {
+	case *CosignerBLS12381Key:
+		return newThresholdSignerBLS12381(config, *key)
+	case *CosignerEd25519Key:
+		if config.Config.ThresholdModeConfig.SignScheme == SignSchemeFROST {
+			return newThresholdSignerFROST(config, *key)
+		}
+		return newThresholdSignerSoft(config, *key), nil
+	default:
+		return nil, fmt.Errorf("unsupported key shard type: %T", key)
+	}
+}
+
+// thresholdPubKey returns the public key bytes of a threshold signer as a public key of the key type of its key shard,
+// which is identified by the public key length.
+func thresholdPubKey(pubKey []byte) cometcrypto.PubKey {
+	if len(pubKey) == BLS12381PubKeySize {
+		return PubKeyBLS12381(pubKey)
+	}
+	return cometcryptoed25519.PubKey(pubKey)
+}
+
+// newThresholdSignerForKeyFile returns the threshold signer of cosigner id for the key shard in the key file,
+// e.g. the new key of a scheduled key rotation.
+func newThresholdSignerForKeyFile(config *RuntimeConfig, id int, keyFile string) (ThresholdSigner, error) {
+	if err := fileExists(keyFile); err != nil {
+		return nil, err
+	}
+
+	key, err := loadThresholdSignerKeyFile(id, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return newThresholdSignerForKey(config, key)
+}
+
+// loadThresholdSignerKey loads the key shard of cosigner id for the chain.
+func loadThresholdSignerKey(config *RuntimeConfig, id int, chainID string) (CosignerKey, error) {
+	keyFile, err := config.KeyFileExistsCosigner(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadThresholdSignerKeyFile(id, keyFile)
+}
+
+// loadThresholdSignerEd25519Key loads the Ed25519 key shard of cosigner id for the chain.
+func loadThresholdSignerEd25519Key(config *RuntimeConfig, id int, chainID string) (CosignerEd25519Key, error) {
+	key, err := loadThresholdSignerKey(config, id, chainID)
+	if err != nil {
+		return CosignerEd25519Key{}, err
+	}
+
+	ed25519Key, ok := key.(*CosignerEd25519Key)
+	if !ok {
+		return CosignerEd25519Key{}, fmt.Errorf("key shard of cosigner %d for chain %s is a %s key shard, not an %s key shard",
+			id, chainID, key.CombinedPubKey().Type(), cometcryptoed25519.KeyType)
+	}
+
+	return *ed25519Key, nil
+}
+
+// loadThresholdSignerKeyFile loads the key shard of cosigner id from the key file.
+func loadThresholdSignerKeyFile(id int, keyFile string) (CosignerKey, error) {
+	key, err := LoadCosignerKey(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cosigner key: %s", err)
+	}
+
+	if err := verifyThresholdSignerKey(key, id, keyFile); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// verifyThresholdSignerKey verifies that the key shard from source belongs to cosigner id,
+// and matches its public key shard if the key has public key shards.
+func verifyThresholdSignerKey(key CosignerKey, id int, source string) error {
+	if key.ShardID() != id {
+		return fmt.Errorf("key shard ID (%d) in (%s) does not match cosigner ID (%d)", key.ShardID(), source, id)
+	}
+
+	var publicShards [][]byte
+	switch key := key.(type) {
+	case *CosignerBLS12381Key:
+		publicShards = key.PublicShards
+	case *CosignerEd25519Key:
+		publicShards = key.PublicShards
+	}
+	if len(publicShards) < id || len(publicShards[id-1]) == 0 {
+		return nil
+	}
+
+	var publicShard []byte
+	switch key := key.(type) {
+	case *CosignerBLS12381Key:
+		privateShard, err := blsScalarFromBytes(key.PrivateShard)
+		if err != nil {
+			return fmt.Errorf("invalid private key shard in (%s): %w", source, err)
+		}
+		publicShard = blsPublicKey(privateShard)
+	case *CosignerEd25519Key:
+		privateShard, err := edwards25519.NewScalar().SetCanonicalBytes(key.PrivateShard)
+		if err != nil {
+			return fmt.Errorf("invalid private key shard in (%s): %w", source, err)
+		}
+		publicShard = new(edwards25519.Point).ScalarBaseMult(privateShard).Bytes()
+	default:
+		return fmt.Errorf("unsupported key shard type in (%s): %T", source, key)
+	}
+
+	if !bytes.Equal(publicShard, publicShards[id-1]) {
+		return fmt.Errorf("public key shard in (%s) does not match the private key shard", source)
+	}
+
+	return nil
+}
+
 // Nonces contains the ephemeral information generated by one cosigner for all other cosigners.
 type Nonces struct {
-	PubKey []byte
-	Shares [][]byte
+	PubKey      []byte
+	Shares      [][]byte
+	Commitments [][]byte
 }
 
 type NoncesWithExpiration struct {
@@ -0,0 +1,126 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+)
+
+var _ ThresholdSigner = &ThresholdSignerBLS12381{}
+
+// ThresholdSignerBLS12381 signs with a shamir share of a BLS12-381 key.
+//
+// The signature share of a cosigner is its key shard times the hash of the payload in G2,
+// and any threshold of signature shares combine to the BLS signature of the full key with
+// lagrange interpolation in the exponent. BLS signatures are deterministic, so no nonces are needed
+// and the nonces that the cosigners exchange for each sign request are ignored.
+type ThresholdSignerBLS12381 struct {
+	id              int
+	privateKeyShard *bls12381.Scalar
+	pubKey          []byte
+	publicShards    [][]byte
+	threshold       uint8
+}
+
+func NewThresholdSignerBLS12381(config *RuntimeConfig, id int, chainID string) (*ThresholdSignerBLS12381, error) {
+	key, err := loadThresholdSignerKey(config, id, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	blsKey, ok := key.(*CosignerBLS12381Key)
+	if !ok {
+		return nil, fmt.Errorf("key shard of cosigner %d for chain %s is a %s key shard, not a %s key shard",
+			id, chainID, key.CombinedPubKey().Type(), KeyTypeBLS12381)
+	}
+
+	return newThresholdSignerBLS12381(config, *blsKey)
+}
+
+func newThresholdSignerBLS12381(config *RuntimeConfig, key CosignerBLS12381Key) (*ThresholdSignerBLS12381, error) {
+	privateKeyShard := new(bls12381.Scalar)
+	if key.PrivateShard != nil {
+		var err error
+		privateKeyShard, err = blsScalarFromBytes(key.PrivateShard)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key shard: %w", err)
+		}
+	}
+
+	return &ThresholdSignerBLS12381{
+		id:              key.ID,
+		privateKeyShard: privateKeyShard,
+		pubKey:          key.PubKey.Bytes(),
+		publicShards:    key.PublicShards,
+		threshold:       uint8(config.Config.ThresholdModeConfig.Threshold),
+	}, nil
+}
+
+func (s *ThresholdSignerBLS12381) PubKey() []byte {
+	return s.pubKey
+}
+
+// Sign produces the signature share of this cosigner. The nonces are ignored.
+func (s *ThresholdSignerBLS12381) Sign(_ []Nonce, payload []byte) ([]byte, error) {
+	if s.privateKeyShard.IsZero() == 1 {
+		return nil, errors.New("no private key shard")
+	}
+	return blsSign(s.privateKeyShard, payload), nil
+}
+
+// VerifyPartialSignature verifies the signature share of the cosigner with the shard ID of the signature
+// against its public key shard. The nonces are ignored.
+func (s *ThresholdSignerBLS12381) VerifyPartialSignature(
+	payload []byte,
+	_ *CosignerUUIDNonces,
+	signature PartialSignature,
+) error {
+	if signature.ID <= 0 || signature.ID > len(s.publicShards) || len(s.publicShards[signature.ID-1]) == 0 {
+		return fmt.Errorf("%w: no public key shard for cosigner %d", ErrUnverifiablePartialSignature, signature.ID)
+	}
+
+	if err := blsVerify(s.publicShards[signature.ID-1], payload, signature.Signature); err != nil {
+		return fmt.Errorf("invalid signature share from cosigner %d: %w", signature.ID, err)
+	}
+
+	return nil
+}
+
+// CombineSignatures interpolates the signature shares of at least the threshold number of cosigners
+// to the signature of the full key.
+func (s *ThresholdSignerBLS12381) CombineSignatures(signatures []PartialSignature) ([]byte, error) {
+	if len(signatures) == 0 || len(signatures) < int(s.threshold) {
+		return nil, fmt.Errorf("%d signature shares, expected at least threshold (%d)", len(signatures), s.threshold)
+	}
+
+	// any threshold of the signature shares determine the signature
+	if s.threshold > 0 {
+		signatures = signatures[:s.threshold]
+	}
+
+	ids := make([]int, len(signatures))
+	for i, sig := range signatures {
+		ids[i] = sig.ID
+	}
+
+	combined := new(bls12381.G2)
+	combined.SetIdentity()
+
+	for _, sig := range signatures {
+		share, err := blsSignatureFromBytes(sig.Signature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature share from cosigner %d: %w", sig.ID, err)
+		}
+
+		lambda, err := blsLagrangeCoefficient(sig.ID, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		share.ScalarMult(lambda, share)
+		combined.Add(combined, share)
+	}
+
+	return combined.BytesCompressed(), nil
+}
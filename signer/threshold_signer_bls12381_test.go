@@ -0,0 +1,136 @@
+package signer
+
+import (
+	"testing"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometprivval "github.com/cometbft/cometbft/privval"
+	"github.com/stretchr/testify/require"
+)
+
+func testBLS12381Signers(t *testing.T, threshold, total uint8) ([]*ThresholdSignerBLS12381, PubKeyBLS12381) {
+	privateKey, err := GenPrivKeyBLS12381()
+	require.NoError(t, err)
+
+	keys, err := CreateCosignerBLS12381Shards(cometprivval.FilePVKey{
+		Address: privateKey.PubKey().Address(),
+		PubKey:  privateKey.PubKey(),
+		PrivKey: privateKey,
+	}, threshold, total)
+	require.NoError(t, err)
+
+	config := &RuntimeConfig{
+		Config: Config{
+			ThresholdModeConfig: &ThresholdModeConfig{
+				Threshold: int(threshold),
+			},
+		},
+	}
+
+	signers := make([]*ThresholdSignerBLS12381, len(keys))
+	for i, key := range keys {
+		require.NoError(t, verifyThresholdSignerKey(&key, i+1, "test"))
+
+		signers[i], err = newThresholdSignerBLS12381(config, key)
+		require.NoError(t, err)
+	}
+
+	return signers, privateKey.PubKey().(PubKeyBLS12381)
+}
+
+func TestThresholdSignerBLS12381(t *testing.T) {
+	const threshold, total = 3, 5
+
+	signers, pubKey := testBLS12381Signers(t, threshold, total)
+	msg := []byte("hello BLS, sign bytes are longer than 32 bytes")
+
+	for _, ids := range [][]int{{1, 2, 3}, {2, 4, 5}, {5, 1, 3, 4}} {
+		sigs := make([]PartialSignature, 0, len(ids))
+		for _, id := range ids {
+			// BLS signatures do not need nonces
+			sig, err := signers[id-1].Sign(nil, msg)
+			require.NoError(t, err)
+			require.Len(t, sig, BLS12381SignatureSize)
+
+			partial := PartialSignature{ID: id, Signature: sig}
+
+			// every signature share verifies against the public key shard of its cosigner
+			require.NoError(t, signers[0].VerifyPartialSignature(msg, nil, partial))
+
+			// but not against the public key shard of another cosigner
+			require.Error(t, signers[0].VerifyPartialSignature(msg, nil, PartialSignature{ID: id%total + 1, Signature: sig}))
+
+			sigs = append(sigs, partial)
+		}
+
+		combined, err := signers[0].CombineSignatures(sigs)
+		require.NoError(t, err)
+		require.True(t, pubKey.VerifySignature(msg, combined))
+	}
+
+	sig1, err := signers[0].Sign(nil, msg)
+	require.NoError(t, err)
+	sig2, err := signers[1].Sign(nil, msg)
+	require.NoError(t, err)
+
+	// fewer than the threshold number of signature shares can not be combined
+	_, err = signers[0].CombineSignatures([]PartialSignature{{ID: 1, Signature: sig1}, {ID: 2, Signature: sig2}})
+	require.Error(t, err)
+
+	// a signature share of a different message does not verify
+	require.Error(t, signers[0].VerifyPartialSignature([]byte("other"), nil, PartialSignature{ID: 1, Signature: sig1}))
+}
+
+func TestThresholdSignerBLS12381FromKeyFile(t *testing.T) {
+	signers, pubKey := testBLS12381Signers(t, 2, 3)
+
+	// the key shard survives a json round trip with its BLS12-381 public key and key type
+	key := CosignerBLS12381Key{
+		PubKey:       pubKey,
+		PrivateShard: []byte{},
+		ID:           1,
+		PublicShards: signers[0].publicShards,
+	}
+	bz, err := key.MarshalJSON()
+	require.NoError(t, err)
+	require.Contains(t, string(bz), `"keyType":"bls12_381"`)
+
+	decoded, err := unmarshalCosignerKey(bz)
+	require.NoError(t, err)
+	require.IsType(t, &CosignerBLS12381Key{}, decoded)
+	require.Equal(t, pubKey, decoded.CombinedPubKey())
+	require.Equal(t, KeyTypeBLS12381, decoded.CombinedPubKey().Type())
+
+	// a BLS12-381 key shard can not be loaded as an Ed25519 key shard
+	var ed25519Key CosignerEd25519Key
+	require.ErrorContains(t, ed25519Key.UnmarshalJSON(bz), "bls12_381 key shard is not an ed25519 key shard")
+
+	// and an Ed25519 key shard, which has no key type, can not be loaded as a BLS12-381 key shard
+	ed25519Keys := CreateCosignerEd25519Shards(cometprivval.FilePVKey{
+		PubKey:  cometcryptoed25519.GenPrivKey().PubKey(),
+		PrivKey: cometcryptoed25519.GenPrivKey(),
+	}, 2, 3)
+	ed25519Bz, err := ed25519Keys[0].MarshalJSON()
+	require.NoError(t, err)
+	var blsKey CosignerBLS12381Key
+	require.ErrorContains(t, blsKey.UnmarshalJSON(ed25519Bz), "untyped key shard is not a bls12_381 key shard")
+	untyped, err := unmarshalCosignerKey(ed25519Bz)
+	require.NoError(t, err)
+	require.IsType(t, &CosignerEd25519Key{}, untyped)
+	require.Equal(t, ed25519Keys[0].PubKey, untyped.CombinedPubKey())
+
+	// the key type of the threshold signer follows the key shard
+	config := &RuntimeConfig{
+		Config: Config{
+			ThresholdModeConfig: &ThresholdModeConfig{
+				Threshold:  2,
+				SignScheme: SignSchemeFROST,
+			},
+		},
+	}
+	decoded.(*CosignerBLS12381Key).PrivateShard = nil
+	signer, err := newThresholdSignerForKey(config, decoded)
+	require.NoError(t, err)
+	require.IsType(t, &ThresholdSignerBLS12381{}, signer)
+	require.Equal(t, pubKey, thresholdPubKey(signer.PubKey()))
+}
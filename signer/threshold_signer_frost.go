@@ -0,0 +1,400 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"sort"
+
+	"filippo.io/edwards25519"
+)
+
+// frostContextString is the context string of the FROST(Ed25519, SHA-512) ciphersuite of RFC 9591.
+const frostContextString = "FROST-ED25519-SHA512-v1"
+
+// frostNonceSize is the size of the hiding and binding nonces, or of their commitments, concatenated.
+const frostNonceSize = 64
+
+var _ ThresholdSigner = &ThresholdSignerFROST{}
+
+// ThresholdSignerFROST signs with FROST(Ed25519, SHA-512) as specified in RFC 9591.
+//
+// Instead of dealing encrypted nonce shares to every other cosigner, each cosigner commits to a pair of
+// hiding and binding nonces and publishes the commitments. The key shards are the same shamir shares
+// that are used by ThresholdSignerSoft, and the combined signature is a standard Ed25519 signature.
+//
+// Nonce.PubKey holds the hiding and binding nonce commitments of a cosigner,
+// and Nonce.Share holds the hiding and binding nonces of this cosigner.
+type ThresholdSignerFROST struct {
+	id              int
+	privateKeyShard *edwards25519.Scalar
+	pubKey          []byte
+	publicShards    [][]byte
+	threshold       uint8
+}
+
+func NewThresholdSignerFROST(config *RuntimeConfig, id int, chainID string) (*ThresholdSignerFROST, error) {
+	key, err := loadThresholdSignerEd25519Key(config, id, chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newThresholdSignerFROST(config, key)
+}
+
+func newThresholdSignerFROST(config *RuntimeConfig, key CosignerEd25519Key) (*ThresholdSignerFROST, error) {
+	privateKeyShard := edwards25519.NewScalar()
+	if key.PrivateShard != nil {
+		var err error
+		privateKeyShard, err = privateKeyShard.SetCanonicalBytes(key.PrivateShard)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key shard: %w", err)
+		}
+	}
+
+	return &ThresholdSignerFROST{
+		id:              key.ID,
+		privateKeyShard: privateKeyShard,
+		pubKey:          key.PubKey.Bytes(),
+		publicShards:    key.PublicShards,
+		threshold:       uint8(config.Config.ThresholdModeConfig.Threshold),
+	}, nil
+}
+
+func (s *ThresholdSignerFROST) PubKey() []byte {
+	return s.pubKey
+}
+
+// GenerateFROSTNonces generates the hiding and binding nonces of cosigner id for one FROST signature.
+// The nonce commitments are published to all cosigners, the nonces are kept as the share of cosigner id.
+//
+// As specified by nonce_generate of RFC 9591, the nonces are hedged with the secret signing share,
+// so that they are not predictable even if the random number generator is.
+func GenerateFROSTNonces(id int, total uint8, secret *edwards25519.Scalar) (Nonces, error) {
+	hiding, err := frostNonceGenerate(secret)
+	if err != nil {
+		return Nonces{}, err
+	}
+
+	binding, err := frostNonceGenerate(secret)
+	if err != nil {
+		return Nonces{}, err
+	}
+
+	commitments := make([]byte, 0, frostNonceSize)
+	commitments = append(commitments, new(edwards25519.Point).ScalarBaseMult(hiding).Bytes()...)
+	commitments = append(commitments, new(edwards25519.Point).ScalarBaseMult(binding).Bytes()...)
+
+	nonceShare := make([]byte, 0, frostNonceSize)
+	nonceShare = append(nonceShare, hiding.Bytes()...)
+	nonceShare = append(nonceShare, binding.Bytes()...)
+
+	nonces := Nonces{
+		PubKey: commitments,
+		Shares: make([][]byte, total),
+	}
+	nonces.Shares[id-1] = nonceShare
+
+	return nonces, nil
+}
+
+// frostNonceGenerate is nonce_generate of RFC 9591 section 4.1.
+func frostNonceGenerate(secret *edwards25519.Scalar) (*edwards25519.Scalar, error) {
+	var randomBytes [32]byte
+	if _, err := rand.Read(randomBytes[:]); err != nil {
+		return nil, err
+	}
+	return frostHashToScalar("nonce", randomBytes[:], secret.Bytes())
+}
+
+// Sign produces the signature share of this cosigner. The nonces are the commitments of all cosigners
+// that participate in the signature, including the commitments and nonces of this cosigner.
+// The signature share is prefixed with the group commitment, which is the R of the combined signature.
+func (s *ThresholdSignerFROST) Sign(nonces []Nonce, payload []byte) ([]byte, error) {
+	commitments, err := newFROSTCommitmentList(nonces)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(commitments) < int(s.threshold) {
+		return nil, fmt.Errorf("%d nonce commitments, expected at least threshold (%d)", len(commitments), s.threshold)
+	}
+
+	var own *Nonce
+	for i := range nonces {
+		if nonces[i].ID == s.id && len(nonces[i].Share) > 0 {
+			own = &nonces[i]
+		}
+	}
+	if own == nil {
+		return nil, fmt.Errorf("nonce commitments do not include cosigner %d", s.id)
+	}
+	if len(own.Share) != frostNonceSize {
+		return nil, fmt.Errorf("invalid nonce length: %d", len(own.Share))
+	}
+
+	hiding, err := edwards25519.NewScalar().SetCanonicalBytes(own.Share[:32])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hiding nonce: %w", err)
+	}
+	binding, err := edwards25519.NewScalar().SetCanonicalBytes(own.Share[32:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid binding nonce: %w", err)
+	}
+
+	// refuse to sign if the commitments do not belong to our nonces,
+	// as the other cosigners would then sign for different group commitments.
+	ownCommitment := commitments.get(s.id)
+	if new(edwards25519.Point).ScalarBaseMult(hiding).Equal(ownCommitment.hiding) != 1 ||
+		new(edwards25519.Point).ScalarBaseMult(binding).Equal(ownCommitment.binding) != 1 {
+		return nil, fmt.Errorf("nonce commitments of cosigner %d do not match its nonces", s.id)
+	}
+
+	bindingFactors, err := commitments.bindingFactors(s.pubKey, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	groupCommitment := commitments.groupCommitment(bindingFactors).Bytes()
+
+	challenge, err := frostChallenge(groupCommitment, s.pubKey, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	lambda, err := lagrangeCoefficient(s.id, commitments.ids(), 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// z_i = d_i + e_i * rho_i + lambda_i * s_i * c
+	z := edwards25519.NewScalar().Multiply(lambda, s.privateKeyShard)
+	z.Multiply(z, challenge)
+	z.MultiplyAdd(binding, bindingFactors[s.id], z)
+	z.Add(z, hiding)
+
+	return append(groupCommitment, z.Bytes()...), nil
+}
+
+// VerifyPartialSignature verifies the signature share of the cosigner with the shard ID of the signature
+// against its public key shard, given the nonce commitments of all cosigners that participated in the signature.
+func (s *ThresholdSignerFROST) VerifyPartialSignature(
+	payload []byte,
+	nonces *CosignerUUIDNonces,
+	signature PartialSignature,
+) error {
+	if signature.ID <= 0 || signature.ID > len(s.publicShards) || len(s.publicShards[signature.ID-1]) == 0 {
+		return fmt.Errorf("%w: no public key shard for cosigner %d", ErrUnverifiablePartialSignature, signature.ID)
+	}
+
+	var commitmentNonces []Nonce
+	for _, n := range nonces.Nonces {
+		if n.DestinationID == 0 {
+			commitmentNonces = append(commitmentNonces, Nonce{ID: n.SourceID, PubKey: n.PubKey})
+		}
+	}
+	if len(commitmentNonces) == 0 {
+		return errNoNonceCommitments
+	}
+
+	commitments, err := newFROSTCommitmentList(commitmentNonces)
+	if err != nil {
+		return err
+	}
+
+	commitment := commitments.get(signature.ID)
+	if commitment == nil {
+		return fmt.Errorf("no nonce commitments from cosigner %d", signature.ID)
+	}
+
+	bindingFactors, err := commitments.bindingFactors(s.pubKey, payload)
+	if err != nil {
+		return err
+	}
+
+	groupCommitment := commitments.groupCommitment(bindingFactors).Bytes()
+	if len(signature.Signature) < 32 || !bytes.Equal(signature.Signature[:32], groupCommitment) {
+		return errors.New("signed with different nonces")
+	}
+
+	lambda, err := lagrangeCoefficient(signature.ID, commitments.ids(), 0)
+	if err != nil {
+		return err
+	}
+
+	publicShard, err := new(edwards25519.Point).SetBytes(s.publicShards[signature.ID-1])
+	if err != nil {
+		return fmt.Errorf("invalid public key shard: %w", err)
+	}
+
+	// z_i * B == D_i + rho_i * E_i + c * lambda_i * Y_i
+	commitmentShare := new(edwards25519.Point).ScalarMult(bindingFactors[signature.ID], commitment.binding)
+	commitmentShare.Add(commitmentShare, commitment.hiding)
+
+	return verifyPartialSignature(
+		s.pubKey,
+		new(edwards25519.Point).ScalarMult(lambda, publicShard).Bytes(),
+		commitmentShare.Bytes(),
+		payload,
+		signature.Signature,
+	)
+}
+
+// CombineSignatures sums the signature shares of all cosigners that participated in the signature.
+func (s *ThresholdSignerFROST) CombineSignatures(signatures []PartialSignature) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("no signature shares to combine")
+	}
+
+	var groupCommitment []byte
+	z := edwards25519.NewScalar()
+
+	for i, sig := range signatures {
+		if len(sig.Signature) != 64 {
+			return nil, fmt.Errorf("invalid signature share length from cosigner %d: %d", sig.ID, len(sig.Signature))
+		}
+		if i == 0 {
+			groupCommitment = sig.Signature[:32]
+		} else if !bytes.Equal(sig.Signature[:32], groupCommitment) {
+			return nil, fmt.Errorf("group commitments do not match")
+		}
+		zi, err := edwards25519.NewScalar().SetCanonicalBytes(sig.Signature[32:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature share from cosigner %d: %w", sig.ID, err)
+		}
+		z.Add(z, zi)
+	}
+
+	return append(bytes.Clone(groupCommitment), z.Bytes()...), nil
+}
+
+// frostCommitment is the pair of hiding and binding nonce commitments of a cosigner.
+type frostCommitment struct {
+	id      int
+	hiding  *edwards25519.Point
+	binding *edwards25519.Point
+}
+
+// frostCommitmentList is the list of nonce commitments of all participants, ordered by cosigner ID.
+type frostCommitmentList []frostCommitment
+
+func newFROSTCommitmentList(nonces []Nonce) (frostCommitmentList, error) {
+	identity := edwards25519.NewIdentityPoint()
+
+	list := make(frostCommitmentList, 0, len(nonces))
+	for _, n := range nonces {
+		if n.ID <= 0 {
+			return nil, fmt.Errorf("invalid cosigner ID: %d", n.ID)
+		}
+		if len(n.PubKey) != frostNonceSize {
+			return nil, fmt.Errorf("invalid nonce commitments length from cosigner %d: %d", n.ID, len(n.PubKey))
+		}
+		hiding, err := new(edwards25519.Point).SetBytes(n.PubKey[:32])
+		if err != nil {
+			return nil, fmt.Errorf("invalid hiding nonce commitment from cosigner %d: %w", n.ID, err)
+		}
+		binding, err := new(edwards25519.Point).SetBytes(n.PubKey[32:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid binding nonce commitment from cosigner %d: %w", n.ID, err)
+		}
+		if hiding.Equal(identity) == 1 || binding.Equal(identity) == 1 {
+			return nil, fmt.Errorf("identity nonce commitment from cosigner %d", n.ID)
+		}
+		list = append(list, frostCommitment{id: n.ID, hiding: hiding, binding: binding})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].id < list[j].id })
+
+	for i := 1; i < len(list); i++ {
+		if list[i].id == list[i-1].id {
+			return nil, fmt.Errorf("duplicate nonce commitments from cosigner %d", list[i].id)
+		}
+	}
+
+	return list, nil
+}
+
+func (l frostCommitmentList) get(id int) *frostCommitment {
+	for i := range l {
+		if l[i].id == id {
+			return &l[i]
+		}
+	}
+	return nil
+}
+
+func (l frostCommitmentList) ids() []int {
+	ids := make([]int, len(l))
+	for i, c := range l {
+		ids[i] = c.id
+	}
+	return ids
+}
+
+// encode serializes the commitment list as specified in RFC 9591.
+func (l frostCommitmentList) encode() []byte {
+	out := make([]byte, 0, len(l)*96)
+	for _, c := range l {
+		out = append(out, scalarFromInt(c.id).Bytes()...)
+		out = append(out, c.hiding.Bytes()...)
+		out = append(out, c.binding.Bytes()...)
+	}
+	return out
+}
+
+// bindingFactors returns the binding factor of each participant by cosigner ID.
+func (l frostCommitmentList) bindingFactors(pubKey []byte, payload []byte) (map[int]*edwards25519.Scalar, error) {
+	prefix := make([]byte, 0, 32+2*sha512.Size+32)
+	prefix = append(prefix, pubKey...)
+	prefix = append(prefix, frostHash("msg", payload)...)
+	prefix = append(prefix, frostHash("com", l.encode())...)
+
+	factors := make(map[int]*edwards25519.Scalar, len(l))
+	for _, c := range l {
+		rho, err := frostHashToScalar("rho", prefix, scalarFromInt(c.id).Bytes())
+		if err != nil {
+			return nil, err
+		}
+		factors[c.id] = rho
+	}
+
+	return factors, nil
+}
+
+// groupCommitment returns the sum of the hiding and binding nonce commitments of all participants,
+// weighted by their binding factors.
+func (l frostCommitmentList) groupCommitment(bindingFactors map[int]*edwards25519.Scalar) *edwards25519.Point {
+	groupCommitment := edwards25519.NewIdentityPoint()
+	for _, c := range l {
+		groupCommitment.Add(groupCommitment, c.hiding)
+		groupCommitment.Add(groupCommitment, new(edwards25519.Point).ScalarMult(bindingFactors[c.id], c.binding))
+	}
+	return groupCommitment
+}
+
+// frostChallenge returns the Ed25519 challenge, which is not domain separated so that
+// the combined signature is a standard Ed25519 signature.
+func frostChallenge(groupCommitment, pubKey, payload []byte) (*edwards25519.Scalar, error) {
+	h := sha512.New()
+	h.Write(groupCommitment)
+	h.Write(pubKey)
+	h.Write(payload)
+	return edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+}
+
+// frostHash is SHA-512 domain separated with the ciphersuite context string and tag.
+func frostHash(tag string, parts ...[]byte) []byte {
+	h := sha512.New()
+	h.Write([]byte(frostContextString))
+	h.Write([]byte(tag))
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
+
+func frostHashToScalar(tag string, parts ...[]byte) (*edwards25519.Scalar, error) {
+	return edwards25519.NewScalar().SetUniformBytes(frostHash(tag, parts...))
+}
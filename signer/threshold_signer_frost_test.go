@@ -0,0 +1,271 @@
+package signer
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"filippo.io/edwards25519"
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometprivval "github.com/cometbft/cometbft/privval"
+	"github.com/stretchr/testify/require"
+)
+
+func testFROSTSigners(t *testing.T, threshold, total uint8) ([]*ThresholdSignerFROST, cometcryptoed25519.PubKey) {
+	privateKey := cometcryptoed25519.GenPrivKey()
+	keys := CreateCosignerEd25519Shards(cometprivval.FilePVKey{
+		Address: privateKey.PubKey().Address(),
+		PubKey:  privateKey.PubKey(),
+		PrivKey: privateKey,
+	}, threshold, total)
+
+	signers := make([]*ThresholdSignerFROST, len(keys))
+	for i, key := range keys {
+		privateKeyShard, err := edwards25519.NewScalar().SetCanonicalBytes(key.PrivateShard)
+		require.NoError(t, err)
+
+		signers[i] = &ThresholdSignerFROST{
+			id:              key.ID,
+			privateKeyShard: privateKeyShard,
+			pubKey:          key.PubKey.Bytes(),
+			publicShards:    key.PublicShards,
+			threshold:       threshold,
+		}
+	}
+
+	return signers, privateKey.PubKey().(cometcryptoed25519.PubKey)
+}
+
+// testFROSTNonces generates the nonces of the participants and returns the nonces for each participant,
+// and the nonce commitments as they are sent by the cosigners.
+func testFROSTNonces(t *testing.T, ids []int, total uint8) (map[int][]Nonce, *CosignerUUIDNonces) {
+	generated := make(map[int]Nonces, len(ids))
+	uuidNonces := &CosignerUUIDNonces{}
+	for _, id := range ids {
+		nonces, err := GenerateFROSTNonces(id, total, edwards25519.NewScalar())
+		require.NoError(t, err)
+		generated[id] = nonces
+		uuidNonces.Nonces = append(uuidNonces.Nonces, CosignerNonce{SourceID: id, PubKey: nonces.PubKey})
+	}
+
+	out := make(map[int][]Nonce, len(ids))
+	for _, id := range ids {
+		for _, source := range ids {
+			nonce := Nonce{ID: source, PubKey: generated[source].PubKey}
+			if source == id {
+				nonce.Share = generated[source].Shares[id-1]
+			}
+			out[id] = append(out[id], nonce)
+		}
+	}
+
+	return out, uuidNonces
+}
+
+func TestThresholdSignerFROST(t *testing.T) {
+	const threshold, total = 3, 5
+
+	signers, pubKey := testFROSTSigners(t, threshold, total)
+	msg := []byte("hello FROST")
+
+	for _, ids := range [][]int{{1, 2, 3}, {2, 4, 5}, {1, 2, 3, 4, 5}} {
+		nonces, uuidNonces := testFROSTNonces(t, ids, total)
+
+		sigs := make([]PartialSignature, 0, len(ids))
+		for _, id := range ids {
+			sig, err := signers[id-1].Sign(nonces[id], msg)
+			require.NoError(t, err)
+
+			partial := PartialSignature{ID: id, Signature: sig}
+
+			// every signature share verifies against the public key shard of its cosigner
+			require.NoError(t, signers[0].VerifyPartialSignature(msg, uuidNonces, partial))
+			require.Error(t, signers[0].VerifyPartialSignature([]byte("other"), uuidNonces, partial))
+
+			sigs = append(sigs, partial)
+		}
+
+		combined, err := signers[0].CombineSignatures(sigs)
+		require.NoError(t, err)
+		require.True(t, pubKey.VerifySignature(msg, combined), "combined signature of %v is invalid", ids)
+	}
+}
+
+func TestThresholdSignerFROSTRefusesInvalidNonces(t *testing.T) {
+	const threshold, total = 2, 3
+
+	signers, _ := testFROSTSigners(t, threshold, total)
+	msg := []byte("hello FROST")
+
+	nonces, uuidNonces := testFROSTNonces(t, []int{1, 2}, total)
+
+	// fewer than the threshold number of participants
+	_, err := signers[0].Sign(nonces[1][:1], msg)
+	require.Error(t, err)
+
+	// no nonces of this cosigner
+	_, err = signers[2].Sign(nonces[1], msg)
+	require.Error(t, err)
+
+	// commitments that do not belong to the nonces of this cosigner
+	other, _ := testFROSTNonces(t, []int{1}, total)
+	tampered := append([]Nonce{}, nonces[1]...)
+	tampered[0].PubKey = other[1][0].PubKey
+	_, err = signers[0].Sign(tampered, msg)
+	require.Error(t, err)
+
+	// duplicate commitments
+	_, err = signers[0].Sign(append(nonces[1], nonces[1][1]), msg)
+	require.Error(t, err)
+
+	// a signature share for different commitments is attributed to the cosigner
+	sig, err := signers[1].Sign(nonces[2], msg)
+	require.NoError(t, err)
+	_, otherUUIDNonces := testFROSTNonces(t, []int{1, 2}, total)
+	err = signers[0].VerifyPartialSignature(msg, otherUUIDNonces, PartialSignature{ID: 2, Signature: sig})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrUnverifiablePartialSignature)
+
+	// a tampered signature share is attributed to the cosigner
+	sig[63] ^= 0x01
+	err = signers[0].VerifyPartialSignature(msg, uuidNonces, PartialSignature{ID: 2, Signature: sig})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrUnverifiablePartialSignature)
+}
+
+// TestThresholdSignerFROSTRFC9591Vectors checks the FROST(Ed25519, SHA-512) test vectors of RFC 9591 appendix E.1.
+func TestThresholdSignerFROSTRFC9591Vectors(t *testing.T) {
+	scalar := func(s string) *edwards25519.Scalar {
+		v, err := edwards25519.NewScalar().SetCanonicalBytes(mustHexDecode(t, s))
+		require.NoError(t, err)
+		return v
+	}
+
+	pubKey := mustHexDecode(t, "15d21ccd7ee42959562fc8aa63224c8851fb3ec85a3faf66040d380fb9738673")
+	msg := mustHexDecode(t, "74657374")
+
+	shares := map[int]*edwards25519.Scalar{
+		1: scalar("929dcc590407aae7d388761cddb0c0db6f5627aea8e217f4a033f2ec83d93509"),
+		2: scalar("a91e66e012e4364ac9aaa405fcafd370402d9859f7b6685c07eed76bf409e80d"),
+		3: scalar("d3cb090a075eb154e82fdb4b3cb507f110040905468bb9c46da8bdea643a9a02"),
+	}
+	publicShards := make([][]byte, len(shares))
+	for id, share := range shares {
+		publicShards[id-1] = new(edwards25519.Point).ScalarBaseMult(share).Bytes()
+	}
+
+	// round one
+	participants := []struct {
+		id                                  int
+		hidingRandomness, bindingRandomness string
+		hidingNonce, bindingNonce           string
+		hidingCommitment, bindingCommitment string
+		bindingFactor                       string
+		sigShare                            string
+	}{
+		{
+			id:                1,
+			hidingRandomness:  "0fd2e39e111cdc266f6c0f4d0fd45c947761f1f5d3cb583dfcb9bbaf8d4c9fec",
+			bindingRandomness: "69cd85f631d5f7f2721ed5e40519b1366f340a87c2f6856363dbdcda348a7501",
+			hidingNonce:       "812d6104142944d5a55924de6d49940956206909f2acaeedecda2b726e630407",
+			bindingNonce:      "b1110165fc2334149750b28dd813a39244f315cff14d4e89e6142f262ed83301",
+			hidingCommitment:  "b5aa8ab305882a6fc69cbee9327e5a45e54c08af61ae77cb8207be3d2ce13de3",
+			bindingCommitment: "67e98ab55aa310c3120418e5050c9cf76cf387cb20ac9e4b6fdb6f82a469f932",
+			bindingFactor:     "f2cb9d7dd9beff688da6fcc83fa89046b3479417f47f55600b106760eb3b5603",
+			sigShare:          "001719ab5a53ee1a12095cd088fd149702c0720ce5fd2f29dbecf24b7281b603",
+		},
+		{
+			id:                3,
+			hidingRandomness:  "86d64a260059e495d0fb4fcc17ea3da7452391baa494d4b00321098ed2a0062f",
+			bindingRandomness: "13e6b25afb2eba51716a9a7d44130c0dbae0004a9ef8d7b5550c8a0e07c61775",
+			hidingNonce:       "c256de65476204095ebdc01bd11dc10e57b36bc96284595b8215222374f99c0e",
+			bindingNonce:      "243d71944d929063bc51205714ae3c2218bd3451d0214dfb5aeec2a90c35180d",
+			hidingCommitment:  "cfbdb165bd8aad6eb79deb8d287bcc0ab6658ae57fdcc98ed12c0669e90aec91",
+			bindingCommitment: "7487bc41a6e712eea2f2af24681b58b1cf1da278ea11fe4e8b78398965f13552",
+			bindingFactor:     "b087686bf35a13f3dc78e780a34b0fe8a77fef1b9938c563f5573d71d8d7890f",
+			sigShare:          "bd86125de990acc5e1f13781d8e32c03a9bbd4c53539bbc106058bfd14326007",
+		},
+	}
+
+	nonces := make([]Nonce, 0, len(participants))
+	uuidNonces := &CosignerUUIDNonces{}
+	for _, p := range participants {
+		share := shares[p.id].Bytes()
+
+		hiding, err := frostHashToScalar("nonce", mustHexDecode(t, p.hidingRandomness), share)
+		require.NoError(t, err)
+		require.Equal(t, p.hidingNonce, hex.EncodeToString(hiding.Bytes()))
+
+		binding, err := frostHashToScalar("nonce", mustHexDecode(t, p.bindingRandomness), share)
+		require.NoError(t, err)
+		require.Equal(t, p.bindingNonce, hex.EncodeToString(binding.Bytes()))
+
+		commitments := append(
+			new(edwards25519.Point).ScalarBaseMult(hiding).Bytes(),
+			new(edwards25519.Point).ScalarBaseMult(binding).Bytes()...,
+		)
+		require.Equal(t, p.hidingCommitment+p.bindingCommitment, hex.EncodeToString(commitments))
+
+		nonces = append(nonces, Nonce{
+			ID:     p.id,
+			PubKey: commitments,
+			Share:  append(hiding.Bytes(), binding.Bytes()...),
+		})
+		uuidNonces.Nonces = append(uuidNonces.Nonces, CosignerNonce{SourceID: p.id, PubKey: commitments})
+	}
+
+	// round two
+	commitments, err := newFROSTCommitmentList(nonces)
+	require.NoError(t, err)
+	bindingFactors, err := commitments.bindingFactors(pubKey, msg)
+	require.NoError(t, err)
+	for _, p := range participants {
+		require.Equal(t, p.bindingFactor, hex.EncodeToString(bindingFactors[p.id].Bytes()))
+	}
+
+	groupCommitment := "36282629c383bb820a88b71cae937d41f2f2adfcc3d02e55507e2fb9e2dd3cbe"
+	require.Equal(t, groupCommitment, hex.EncodeToString(commitments.groupCommitment(bindingFactors).Bytes()))
+
+	var signer *ThresholdSignerFROST
+	sigs := make([]PartialSignature, 0, len(participants))
+	for _, p := range participants {
+		signer = &ThresholdSignerFROST{
+			id:              p.id,
+			privateKeyShard: shares[p.id],
+			pubKey:          pubKey,
+			publicShards:    publicShards,
+			threshold:       2,
+		}
+
+		// the cosigner only holds its own nonces
+		own := make([]Nonce, len(nonces))
+		for i, nonce := range nonces {
+			own[i] = Nonce{ID: nonce.ID, PubKey: nonce.PubKey}
+			if nonce.ID == p.id {
+				own[i].Share = nonce.Share
+			}
+		}
+
+		sig, err := signer.Sign(own, msg)
+		require.NoError(t, err)
+		require.Equal(t, groupCommitment+p.sigShare, hex.EncodeToString(sig))
+
+		partial := PartialSignature{ID: p.id, Signature: sig}
+		require.NoError(t, signer.VerifyPartialSignature(msg, uuidNonces, partial))
+		sigs = append(sigs, partial)
+	}
+
+	combined, err := signer.CombineSignatures(sigs)
+	require.NoError(t, err)
+	require.Equal(t,
+		"36282629c383bb820a88b71cae937d41f2f2adfcc3d02e55507e2fb9e2dd3cbe"+
+			"bd9d2b0844e49ae0f3fa935161e1419aab7b47d21a37ebeae1f17d4987b3160b",
+		hex.EncodeToString(combined),
+	)
+	require.True(t, cometcryptoed25519.PubKey(pubKey).VerifySignature(msg, combined))
+}
+
+func mustHexDecode(t *testing.T, s string) []byte {
+	bz, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return bz
+}
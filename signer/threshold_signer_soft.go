@@ -2,7 +2,6 @@ package signer
 
 import (
 	"bytes"
-	"crypto/rand"
 	"errors"
 	"fmt"
 
@@ -15,33 +14,28 @@ var _ ThresholdSigner = &ThresholdSignerSoft{}
 type ThresholdSignerSoft struct {
 	privateKeyShard []byte
 	pubKey          []byte
+	publicShards    [][]byte
 	threshold       uint8
 	total           uint8
 }
 
 func NewThresholdSignerSoft(config *RuntimeConfig, id int, chainID string) (*ThresholdSignerSoft, error) {
-	keyFile, err := config.KeyFileExistsCosigner(chainID)
+	key, err := loadThresholdSignerEd25519Key(config, id, chainID)
 	if err != nil {
 		return nil, err
 	}
 
-	key, err := LoadCosignerEd25519Key(keyFile)
-	if err != nil {
-		return nil, fmt.Errorf("error reading cosigner key: %s", err)
-	}
-
-	if key.ID != id {
-		return nil, fmt.Errorf("key shard ID (%d) in (%s) does not match cosigner ID (%d)", key.ID, keyFile, id)
-	}
+	return newThresholdSignerSoft(config, key), nil
+}
 
-	s := ThresholdSignerSoft{
+func newThresholdSignerSoft(config *RuntimeConfig, key CosignerEd25519Key) *ThresholdSignerSoft {
+	return &ThresholdSignerSoft{
 		privateKeyShard: key.PrivateShard,
 		pubKey:          key.PubKey.Bytes(),
+		publicShards:    key.PublicShards,
 		threshold:       uint8(config.Config.ThresholdModeConfig.Threshold),
 		total:           uint8(len(config.Config.ThresholdModeConfig.Cosigners)),
 	}
-
-	return &s, nil
 }
 
 func (s *ThresholdSignerSoft) PubKey() []byte {
@@ -85,26 +79,54 @@ func (s *ThresholdSignerSoft) sumNonces(nonces []Nonce) (tsed25519.Scalar, tsed2
 	return nonceShare, noncePub, nil
 }
 
+// GenerateNonces deals shares of a random nonce to all cosigners with Feldman verifiable secret sharing.
+// The commitments allow the share of each cosigner to be verified, and the public nonce share
+// of each cosigner to be computed to verify its partial signature.
 func GenerateNonces(threshold, total uint8) (Nonces, error) {
-	secret := make([]byte, 32)
-	if _, err := rand.Read(secret); err != nil {
+	poly, err := newVSSPolynomial(nil, threshold)
+	if err != nil {
 		return Nonces{}, err
 	}
 
+	commitments := poly.commitments()
+
 	nonces := Nonces{
-		PubKey: tsed25519.ScalarMultiplyBase(secret),
-		Shares: make([][]byte, total),
+		PubKey:      commitments[0],
+		Shares:      make([][]byte, total),
+		Commitments: commitments,
 	}
 
-	shares := tsed25519.DealShares(secret, threshold, total)
-
-	for i, sh := range shares {
-		nonces.Shares[i] = sh
+	for i := range nonces.Shares {
+		nonces.Shares[i] = poly.evaluate(i + 1).Bytes()
 	}
 
 	return nonces, nil
 }
 
+// VerifyPartialSignature verifies the partial signature of the cosigner with the shard ID of the signature
+// against its public key shard, given the nonces of all cosigners that participated in the signature.
+// It returns ErrUnverifiablePartialSignature if the key shard does not have the public key shard of the cosigner.
+func (s *ThresholdSignerSoft) VerifyPartialSignature(
+	payload []byte,
+	nonces *CosignerUUIDNonces,
+	signature PartialSignature,
+) error {
+	if signature.ID <= 0 || signature.ID > len(s.publicShards) || len(s.publicShards[signature.ID-1]) == 0 {
+		return fmt.Errorf("%w: no public key shard for cosigner %d", ErrUnverifiablePartialSignature, signature.ID)
+	}
+
+	noncePub, noncePublicShare, err := nonces.NoncePublicShare(signature.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(signature.Signature) < 32 || !bytes.Equal(signature.Signature[:32], noncePub) {
+		return errors.New("signed with different nonces")
+	}
+
+	return verifyPartialSignature(s.pubKey, s.publicShards[signature.ID-1], noncePublicShare, payload, signature.Signature)
+}
+
 func (s *ThresholdSignerSoft) CombineSignatures(signatures []PartialSignature) ([]byte, error) {
 	sigIds := make([]int, len(signatures))
 	shareSigs := make([][]byte, len(signatures))
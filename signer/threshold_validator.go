@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	cometcrypto "github.com/cometbft/cometbft/crypto"
 	"github.com/cometbft/cometbft/libs/log"
 	cometrpcjsontypes "github.com/cometbft/cometbft/rpc/jsonrpc/types"
 	"github.com/google/uuid"
@@ -47,6 +49,16 @@ type ThresholdValidator struct {
 	cosignerHealth *CosignerHealth
 
 	nonceCache *CosignerNonceCache
+
+	slashingProtection *SlashingProtectionDB
+
+	haltHeights HaltHeights
+
+	sentryQuorum *sentryQuorum
+
+	sentryMonitor *SentryMonitor
+
+	signCoalescer *signCoalescer
 }
 
 type ChainSignState struct {
@@ -89,6 +101,8 @@ func NewThresholdValidator(
 		uint8(threshold),
 		nil,
 	)
+	nc.allowMissingCommitments = config.Config.ThresholdModeConfig.AllowMissingNonceCommitments
+
 	return &ThresholdValidator{
 		logger:                      logger,
 		config:                      config,
@@ -100,9 +114,63 @@ func NewThresholdValidator(
 		leader:                      leader,
 		cosignerHealth:              NewCosignerHealth(logger, peerCosigners, leader),
 		nonceCache:                  nc,
+		sentryQuorum:                newSentryQuorum(),
+		signCoalescer:               newSignCoalescer(),
 	}
 }
 
+// SetSlashingProtection sets the slashing protection database which is consulted before signing,
+// and which records every combined signature. It must be called before Start.
+func (pv *ThresholdValidator) SetSlashingProtection(db *SlashingProtectionDB) {
+	pv.slashingProtection = db
+}
+
+// SetHaltHeights sets the runtime halt heights of the chains, which are consulted in addition to
+// the haltHeight of the chains config. It must be called before Start.
+func (pv *ThresholdValidator) SetHaltHeights(haltHeights HaltHeights) {
+	pv.haltHeights = haltHeights
+}
+
+// SetSentryMonitor sets the monitor that detects, and optionally quarantines, sentries that request
+// conflicting votes. It must be called before Start.
+func (pv *ThresholdValidator) SetSentryMonitor(monitor *SentryMonitor) {
+	pv.sentryMonitor = monitor
+}
+
+// waitForSentryQuorum waits until the sentry quorum of the chain, if any, requested the vote.
+// Proposals do not wait. Votes that were not received from a chain node, e.g. through the gRPC API,
+// are rejected unless the quorum allows them without a sentry.
+func (pv *ThresholdValidator) waitForSentryQuorum(ctx context.Context, chainID string, block Block) error {
+	chain, _ := pv.config.Config.Chains.Get(chainID)
+	if chain.SentryQuorum == nil || block.Step == stepPropose {
+		return nil
+	}
+
+	sentry := sentryFromContext(ctx)
+	if sentry == "" {
+		if chain.SentryQuorum.AllowWithoutSentry {
+			return nil
+		}
+		return fmt.Errorf("%s for chain id (%s) at height %d round %d was not requested by a chain node, "+
+			"which the sentryQuorum requires unless allowWithoutSentry is set",
+			signType(block.Step), chainID, block.Height, block.Round)
+	}
+
+	err := pv.sentryQuorum.Wait(
+		ctx, chainID, block, sentry, chain.SentryQuorum.Threshold, chain.SentryQuorum.TimeoutDuration(),
+	)
+	// a canceled sign request or an invalid vote is not a quorum timeout
+	if _, ok := err.(*SentryQuorumError); ok {
+		totalSentryQuorumTimeouts.WithLabelValues(chainID).Inc()
+	}
+	return err
+}
+
+// HaltHeight returns the last height that is signed for the chain, or zero if the chain does not halt.
+func (pv *ThresholdValidator) HaltHeight(chainID string) (int64, error) {
+	return effectiveHaltHeight(pv.config, pv.haltHeights, chainID)
+}
+
 // Start starts the ThresholdValidator.
 func (pv *ThresholdValidator) Start(ctx context.Context) error {
 	pv.logger.Info("Starting ThresholdValidator services")
@@ -274,12 +342,8 @@ func (pv *ThresholdValidator) waitForSignStatesToFlushToDisk() {
 
 // GetPubKey returns the public key of the validator.
 // Implements PrivValidator.
-func (pv *ThresholdValidator) GetPubKey(_ context.Context, chainID string) ([]byte, error) {
-	pubKey, err := pv.myCosigner.GetPubKey(chainID)
-	if err != nil {
-		return nil, err
-	}
-	return pubKey.Bytes(), nil
+func (pv *ThresholdValidator) GetPubKey(_ context.Context, chainID string) (cometcrypto.PubKey, error) {
+	return pv.myCosigner.GetPubKey(chainID)
 }
 
 type Block struct {
@@ -517,6 +581,73 @@ func (pv *ThresholdValidator) getNoncesFallback(
 	return &thresholdNonces, nil
 }
 
+// verifyPartialSignature verifies the partial signature of a cosigner before it is combined,
+// so that an invalid partial signature can be attributed to the cosigner that produced it.
+// Partial signatures that can not be verified individually because the key shard has no public key shards
+// are accepted and only verified as part of the combined signature. Missing nonce commitments are an error,
+// unless they are allowed for a rolling upgrade. A partial signature for different nonces is invalid.
+func (pv *ThresholdValidator) verifyPartialSignature(
+	chainID string,
+	height int64,
+	nonces *CosignerUUIDNonces,
+	id int,
+	payload []byte,
+	signature []byte,
+) error {
+	err := pv.myCosigner.VerifyPartialSignature(chainID, height, payload, nonces, PartialSignature{
+		ID:        id,
+		Signature: signature,
+	})
+	if errors.Is(err, ErrUnverifiablePartialSignature) ||
+		(errors.Is(err, errNoNonceCommitments) && pv.allowMissingNonceCommitments()) {
+		return nil
+	}
+
+	return err
+}
+
+// allowMissingNonceCommitments returns whether nonces without commitments, and existing partial signatures
+// without nonces, of cosigners of previous versions are accepted.
+func (pv *ThresholdValidator) allowMissingNonceCommitments() bool {
+	return pv.config.Config.ThresholdModeConfig.AllowMissingNonceCommitments
+}
+
+// verifyExistingPartialSignature verifies an existing partial signature against the nonces that the cosigner
+// returned with it, which it persisted when it signed the block for an earlier sign request, possibly of
+// another leader. A cosigner can not skip the verification by claiming that its partial signature is an existing one.
+func (pv *ThresholdValidator) verifyExistingPartialSignature(
+	chainID string,
+	block Block,
+	hasVoteExtensions bool,
+	id int,
+	res *CosignerSignResponse,
+) error {
+	if len(res.Nonces) == 0 || (hasVoteExtensions && len(res.VoteExtensionNonces) == 0) {
+		if pv.allowMissingNonceCommitments() {
+			return nil
+		}
+		return fmt.Errorf("%w: existing partial signature was returned without its nonces", errNoNonceCommitments)
+	}
+
+	if err := pv.myCosigner.VerifyNonces(id, res.Nonces); err != nil {
+		return fmt.Errorf("existing partial signature: %w", err)
+	}
+	err := pv.verifyPartialSignature(
+		chainID, block.Height, &CosignerUUIDNonces{Nonces: res.Nonces}, id, block.SignBytes, res.Signature,
+	)
+	if err != nil || !hasVoteExtensions {
+		return err
+	}
+
+	if err := pv.myCosigner.VerifyNonces(id, res.VoteExtensionNonces); err != nil {
+		return fmt.Errorf("existing vote extension partial signature: %w", err)
+	}
+	return pv.verifyPartialSignature(
+		chainID, block.Height, &CosignerUUIDNonces{Nonces: res.VoteExtensionNonces}, id,
+		block.VoteExtensionSignBytes, res.VoteExtensionSignature,
+	)
+}
+
 func waitUntilCompleteOrTimeout(wg *sync.WaitGroup, timeout time.Duration) bool {
 	c := make(chan struct{})
 	go func() {
@@ -547,6 +678,9 @@ func (pv *ThresholdValidator) waitForPeerNonces(
 	peerStartTime := time.Now()
 
 	peerNonces, err := peer.GetNonces(ctx, uuids)
+	if err == nil {
+		err = peerNonces.verifyCommitments(peer.GetID(), pv.allowMissingNonceCommitments())
+	}
 	if err != nil {
 		missedNonces.WithLabelValues(peer.GetAddress()).Inc()
 		totalMissedNonces.WithLabelValues(peer.GetAddress()).Inc()
@@ -624,6 +758,7 @@ func (pv *ThresholdValidator) proxyIfNecessary(
 	signRes, err := cosignerLeader.(*RemoteCosigner).Sign(ctx, CosignerSignBlockRequest{
 		ChainID: chainID,
 		Block:   &block,
+		Sentry:  sentryFromContext(ctx),
 	})
 	if err != nil {
 		if _, ok := err.(*cometrpcjsontypes.RPCError); ok {
@@ -644,7 +779,6 @@ func (pv *ThresholdValidator) Sign(
 	block Block,
 ) ([]byte, []byte, time.Time, error) {
 	height, round, step, stamp := block.Height, block.Round, block.Step, block.Timestamp
-	signBytes, voteExtensionSignBytes := block.SignBytes, block.VoteExtensionSignBytes
 
 	log := pv.logger.With(
 		"chain_id", chainID,
@@ -653,6 +787,19 @@ func (pv *ThresholdValidator) Sign(
 		"type", signType(step),
 	)
 
+	haltHeight, err := pv.HaltHeight(chainID)
+	if err != nil {
+		return nil, nil, stamp, err
+	}
+	if err := checkHaltHeight(chainID, height, haltHeight); err != nil {
+		return nil, nil, stamp, err
+	}
+
+	// the quarantine is replicated to all cosigners, so quarantined sentries are rejected before proxying
+	if err := pv.sentryMonitor.Check(sentryFromContext(ctx)); err != nil {
+		return nil, nil, stamp, err
+	}
+
 	if err := pv.LoadSignStateIfNecessary(chainID); err != nil {
 		return nil, nil, stamp, err
 	}
@@ -668,6 +815,37 @@ func (pv *ThresholdValidator) Sign(
 
 	log.Debug("I am the leader. Managing the sign process for this block")
 
+	// requests are proxied to the leader with their sentry, so the leader sees the requests of all sentries
+	if err := pv.sentryMonitor.Record(chainID, block, sentryFromContext(ctx)); err != nil {
+		return nil, nil, stamp, err
+	}
+
+	if err := pv.waitForSentryQuorum(ctx, chainID, block); err != nil {
+		return nil, nil, stamp, err
+	}
+
+	// identical requests from several sentries share one signing process, and are all answered once it completes
+	return pv.signCoalescer.Do(ctx, chainID, block, func(ctx context.Context) ([]byte, []byte, time.Time, error) {
+		return pv.signBlock(ctx, chainID, block)
+	})
+}
+
+// signBlock manages the threshold signing process for the block on the leader.
+func (pv *ThresholdValidator) signBlock(
+	ctx context.Context,
+	chainID string,
+	block Block,
+) ([]byte, []byte, time.Time, error) {
+	height, round, step, stamp := block.Height, block.Round, block.Step, block.Timestamp
+	signBytes, voteExtensionSignBytes := block.SignBytes, block.VoteExtensionSignBytes
+
+	log := pv.logger.With(
+		"chain_id", chainID,
+		"height", height,
+		"round", round,
+		"type", signType(step),
+	)
+
 	timeStartSignBlock := time.Now()
 
 	hrst := HRSTKey{
@@ -687,6 +865,35 @@ func (pv *ThresholdValidator) Sign(
 		return existingSignature, existingVoteExtSig, existingTimestamp, nil
 	}
 
+	// The sign state only knows the latest signed block, so consult the full signing history as well.
+	if pv.slashingProtection != nil {
+		existing, err := pv.slashingProtection.ExistingSignature(
+			slashingProtectionValidator, chainID, block.HRSKey(), signBytes,
+		)
+		if err != nil {
+			totalSlashingProtectionRefusals.WithLabelValues(chainID, slashingProtectionValidator).Inc()
+			pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
+			return nil, nil, stamp, fmt.Errorf("slashing protection: %w", err)
+		}
+		if existing != nil {
+			log.Debug("Returning existing signature from slashing protection database",
+				"signature", fmt.Sprintf("%x", existing.Signature))
+			// the sign bytes may only differ by timestamp, the signature is only valid with its own timestamp
+			existingTimestamp, err := existing.Timestamp(chainID)
+			if err != nil {
+				pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
+				return nil, nil, stamp, fmt.Errorf("slashing protection: %w", err)
+			}
+			if err := pv.SaveLastSignedState(chainID, existing.SignStateConsensus()); err != nil {
+				if _, isSameHRSError := err.(*SameHRSError); !isSameHRSError {
+					pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
+					return nil, nil, stamp, fmt.Errorf("error saving last sign state: %w", err)
+				}
+			}
+			return existing.Signature, existing.VoteExtensionSignature, existingTimestamp, nil
+		}
+	}
+
 	numPeers := len(pv.peerCosigners)
 	total := uint8(numPeers + 1)
 
@@ -746,6 +953,9 @@ func (pv *ThresholdValidator) Sign(
 					if err != nil {
 						return err
 					}
+					if err := nonces.verifyCommitments(c.GetID(), pv.allowMissingNonceCommitments()); err != nil {
+						return err
+					}
 					mu.Lock()
 					defer mu.Unlock()
 					if voteExtNonces == nil {
@@ -849,6 +1059,44 @@ func (pv *ThresholdValidator) Sign(
 					continue
 				}
 
+				// an existing signature for the same HRS was produced with the nonces of an earlier attempt
+				if sigRes.Existing {
+					err = pv.verifyExistingPartialSignature(
+						chainID, block, voteExtNonces != nil, cosigner.GetID(), sigRes,
+					)
+				} else {
+					err = pv.verifyPartialSignature(chainID, height, nonces, cosigner.GetID(), signBytes, sigRes.Signature)
+					if err == nil && voteExtNonces != nil {
+						err = pv.verifyPartialSignature(
+							chainID, height, voteExtNonces, cosigner.GetID(),
+							voteExtensionSignBytes, sigRes.VoteExtensionSignature,
+						)
+					}
+				}
+				if err != nil {
+					totalInvalidPartialSignatures.WithLabelValues(chainID, strconv.Itoa(cosigner.GetID())).Inc()
+					log.Error(
+						"Cosigner produced an invalid partial signature",
+						"cosigner", cosigner.GetID(),
+						"err", err.Error(),
+					)
+
+					if cosigner.GetID() == pv.myCosigner.GetID() {
+						return err
+					}
+
+					pv.cosignerHealth.MarkUnhealthy(cosigner)
+
+					if dontIterateFastestCosigners {
+						cosigner = nil
+						continue
+					}
+
+					// retry with the next fastest cosigner, under the same conditions as a failed sign request
+					cosigner = getNextFastestCosigner()
+					continue
+				}
+
 				if cosigner != pv.myCosigner {
 					timedCosignerSignLag.WithLabelValues(cosigner.GetAddress()).Observe(time.Since(peerStartTime).Seconds())
 				}
@@ -893,14 +1141,14 @@ func (pv *ThresholdValidator) Sign(
 	}
 
 	// assemble into final signature
-	signature, err := pv.myCosigner.CombineSignatures(chainID, shareSigs)
+	signature, err := pv.myCosigner.CombineSignatures(chainID, height, shareSigs)
 	if err != nil {
 		pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
 		return nil, nil, stamp, fmt.Errorf("error combining signatures: %w", err)
 	}
 
 	// verify the combined signature before saving to watermark
-	if !pv.myCosigner.VerifySignature(chainID, signBytes, signature) {
+	if !pv.myCosigner.VerifySignatureForHeight(chainID, height, signBytes, signature) {
 		totalInvalidSignature.Inc()
 
 		pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
@@ -935,14 +1183,14 @@ func (pv *ThresholdValidator) Sign(
 		}
 
 		// assemble into final signature
-		voteExtSig, err = pv.myCosigner.CombineSignatures(chainID, voteExtShareSigs)
+		voteExtSig, err = pv.myCosigner.CombineSignatures(chainID, height, voteExtShareSigs)
 		if err != nil {
 			pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
 			return nil, nil, stamp, fmt.Errorf("error combining vote extension signatures: %w", err)
 		}
 
 		// verify the combined signature before saving to watermark
-		if !pv.myCosigner.VerifySignature(chainID, voteExtensionSignBytes, voteExtSig) {
+		if !pv.myCosigner.VerifySignatureForHeight(chainID, height, voteExtensionSignBytes, voteExtSig) {
 			totalInvalidSignature.Inc()
 
 			pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
@@ -962,6 +1210,18 @@ func (pv *ThresholdValidator) Sign(
 		},
 	}
 
+	// The signature is only released once it is persisted in the signing history.
+	if pv.slashingProtection != nil {
+		err := pv.slashingProtection.Record(
+			slashingProtectionValidator, chainID, NewSlashingProtectionRecord(newLss.SignStateConsensus),
+		)
+		if err != nil {
+			totalSlashingProtectionRefusals.WithLabelValues(chainID, slashingProtectionValidator).Inc()
+			pv.notifyBlockSignError(chainID, block.HRSKey(), signBytes)
+			return nil, nil, stamp, fmt.Errorf("error recording signature for slashing protection: %w", err)
+		}
+	}
+
 	css := pv.mustLoadChainState(chainID)
 
 	// Err will be present if newLss is not above high watermark
@@ -5,6 +5,7 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/json"
 	"fmt"
 	mrand "math/rand"
 	"path/filepath"
@@ -19,30 +20,121 @@ import (
 	"github.com/cometbft/cometbft/crypto/tmhash"
 	cometlog "github.com/cometbft/cometbft/libs/log"
 	cometrand "github.com/cometbft/cometbft/libs/rand"
+	cometprivval "github.com/cometbft/cometbft/privval"
 	cometproto "github.com/cometbft/cometbft/proto/tendermint/types"
 	comet "github.com/cometbft/cometbft/types"
 	"github.com/ethereum/go-ethereum/crypto/ecies"
 	"github.com/ethereum/go-ethereum/crypto/secp256k1"
 	"github.com/google/uuid"
+	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
 	tsed25519 "gitlab.com/unit410/threshold-ed25519/pkg"
 	"golang.org/x/sync/errgroup"
 )
 
 func TestThresholdValidator2of2(t *testing.T) {
-	testThresholdValidator(t, 2, 2)
+	testThresholdValidator(t, 2, 2, "", "")
 }
 
 func TestThresholdValidator3of3(t *testing.T) {
-	testThresholdValidator(t, 3, 3)
+	testThresholdValidator(t, 3, 3, "", "")
 }
 
 func TestThresholdValidator2of3(t *testing.T) {
-	testThresholdValidator(t, 2, 3)
+	testThresholdValidator(t, 2, 3, "", "")
 }
 
 func TestThresholdValidator3of5(t *testing.T) {
-	testThresholdValidator(t, 3, 5)
+	testThresholdValidator(t, 3, 5, "", "")
+}
+
+func TestThresholdValidatorFROST2of3(t *testing.T) {
+	testThresholdValidator(t, 2, 3, SignSchemeFROST, "")
+}
+
+func TestThresholdValidatorFROST3of5(t *testing.T) {
+	testThresholdValidator(t, 3, 5, SignSchemeFROST, "")
+}
+
+func TestThresholdValidatorBLS2of3(t *testing.T) {
+	testThresholdValidator(t, 2, 3, "", KeyTypeBLS12381)
+}
+
+func TestThresholdValidatorBLS3of5(t *testing.T) {
+	testThresholdValidator(t, 3, 5, "", KeyTypeBLS12381)
+}
+
+func TestThresholdValidatorKeyRotation(t *testing.T) {
+	const threshold, total = 2, 3
+
+	cosigners, oldPubKey := getTestLocalCosigners(t, threshold, total)
+
+	newPrivKey := cometcryptoed25519.GenPrivKey()
+	newPubKey := newPrivKey.PubKey()
+	privShards := tsed25519.DealShares(tsed25519.ExpandSecret(newPrivKey[:32]), threshold, total)
+
+	for i, cosigner := range cosigners {
+		cosigner.config.Config.Chains = ChainsConfig{{
+			ChainID:     testChainID,
+			KeyRotation: &KeyRotation{ActivationHeight: 3, KeyFile: "rotated_shard.json"},
+		}}
+
+		key := CosignerEd25519Key{
+			PubKey:       newPubKey,
+			PrivateShard: privShards[i],
+			ID:           cosigner.GetID(),
+		}
+		keyBz, err := key.MarshalJSON()
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(cosigner.config.KeyFilePathRotation(testChainID), keyBz, 0600))
+	}
+
+	leader := &MockLeader{id: 1}
+
+	validator := NewThresholdValidator(
+		cometlog.NewNopLogger(),
+		cosigners[0].config,
+		threshold,
+		time.Second,
+		1,
+		cosigners[0],
+		[]Cosigner{cosigners[1]},
+		leader,
+	)
+	defer validator.Stop()
+	defer cosigners[1].waitForSignStatesToFlushToDisk()
+
+	leader.leader = validator
+
+	ctx := context.Background()
+
+	require.NoError(t, validator.LoadSignStateIfNecessary(testChainID))
+
+	pubKey, err := validator.GetPubKey(ctx, testChainID)
+	require.NoError(t, err)
+	require.Equal(t, oldPubKey, pubKey)
+
+	block := VoteToBlock(testChainID, &cometproto.Vote{Height: 2, Type: cometproto.PrevoteType, Timestamp: time.Now()})
+
+	validator.nonceCache.LoadN(ctx, 1)
+
+	sig, _, _, err := validator.Sign(ctx, testChainID, block)
+	require.NoError(t, err)
+	require.True(t, oldPubKey.VerifySignature(block.SignBytes, sig))
+
+	// the next height to sign is the activation height
+	pubKey, err = validator.GetPubKey(ctx, testChainID)
+	require.NoError(t, err)
+	require.Equal(t, newPubKey, pubKey)
+
+	block = VoteToBlock(testChainID, &cometproto.Vote{Height: 3, Type: cometproto.PrevoteType, Timestamp: time.Now()})
+
+	validator.nonceCache.LoadN(ctx, 1)
+
+	sig, _, _, err = validator.Sign(ctx, testChainID, block)
+	require.NoError(t, err)
+	require.True(t, newPubKey.VerifySignature(block.SignBytes, sig))
+	require.False(t, oldPubKey.VerifySignature(block.SignBytes, sig))
 }
 
 func loadKeyForLocalCosigner(
@@ -50,14 +142,24 @@ func loadKeyForLocalCosigner(
 	pubKey cometcrypto.PubKey,
 	chainID string,
 	privateShard []byte,
+	publicShards [][]byte,
 ) error {
-	key := CosignerEd25519Key{
+	var key CosignerKey = &CosignerEd25519Key{
 		PubKey:       pubKey,
 		PrivateShard: privateShard,
 		ID:           cosigner.GetID(),
+		PublicShards: publicShards,
+	}
+	if blsPubKey, ok := pubKey.(PubKeyBLS12381); ok {
+		key = &CosignerBLS12381Key{
+			PubKey:       blsPubKey,
+			PrivateShard: privateShard,
+			ID:           cosigner.GetID(),
+			PublicShards: publicShards,
+		}
 	}
 
-	keyBz, err := key.MarshalJSON()
+	keyBz, err := json.Marshal(key)
 	if err != nil {
 		return err
 	}
@@ -65,8 +167,8 @@ func loadKeyForLocalCosigner(
 	return os.WriteFile(cosigner.config.KeyFilePathCosigner(chainID), keyBz, 0600)
 }
 
-func testThresholdValidator(t *testing.T, threshold, total uint8) {
-	cosigners, pubKey := getTestLocalCosigners(t, threshold, total)
+func testThresholdValidator(t *testing.T, threshold, total uint8, signScheme, keyType string) {
+	cosigners, pubKey := getTestLocalCosignersWithScheme(t, threshold, total, signScheme, keyType)
 
 	thresholdCosigners := make([]Cosigner, 0, threshold-1)
 
@@ -114,7 +216,11 @@ func testThresholdValidator(t *testing.T, threshold, total uint8) {
 
 	firstSignature := signature
 
-	require.Len(t, firstSignature, 64)
+	if keyType == KeyTypeBLS12381 {
+		require.Len(t, firstSignature, BLS12381SignatureSize)
+	} else {
+		require.Len(t, firstSignature, 64)
+	}
 
 	proposal = cometproto.Proposal{
 		Height:    1,
@@ -340,6 +446,14 @@ func testThresholdValidator(t *testing.T, threshold, total uint8) {
 }
 
 func getTestLocalCosigners(t *testing.T, threshold, total uint8) ([]*LocalCosigner, cometcrypto.PubKey) {
+	return getTestLocalCosignersWithScheme(t, threshold, total, "", "")
+}
+
+func getTestLocalCosignersWithScheme(
+	t *testing.T,
+	threshold, total uint8,
+	signScheme, keyType string,
+) ([]*LocalCosigner, cometcrypto.PubKey) {
 	eciesKeys := make([]*ecies.PrivateKey, total)
 	pubKeys := make([]*ecies.PublicKey, total)
 	cosigners := make([]*LocalCosigner, total)
@@ -353,9 +467,32 @@ func getTestLocalCosigners(t *testing.T, threshold, total uint8) ([]*LocalCosign
 		pubKeys[i] = &eciesKey.PublicKey
 	}
 
-	privateKey := cometcryptoed25519.GenPrivKey()
-	privKeyBytes := privateKey[:]
-	privShards := tsed25519.DealShares(tsed25519.ExpandSecret(privKeyBytes[:32]), threshold, total)
+	var pubKey cometcrypto.PubKey
+	var privShards, publicShards [][]byte
+	if keyType == KeyTypeBLS12381 {
+		privateKey, err := GenPrivKeyBLS12381()
+		require.NoError(t, err)
+		pubKey = privateKey.PubKey()
+		keys, err := CreateCosignerBLS12381Shards(
+			cometprivval.FilePVKey{PubKey: pubKey, PrivKey: privateKey},
+			threshold, total,
+		)
+		require.NoError(t, err)
+		for _, key := range keys {
+			privShards = append(privShards, key.PrivateShard)
+		}
+		publicShards = keys[0].PublicShards
+	} else {
+		privateKey := cometcryptoed25519.GenPrivKey()
+		pubKey = privateKey.PubKey()
+		privKeyBytes := privateKey[:]
+		shards := tsed25519.DealShares(tsed25519.ExpandSecret(privKeyBytes[:32]), threshold, total)
+		publicShards = make([][]byte, total)
+		for i, shard := range shards {
+			privShards = append(privShards, shard)
+			publicShards[i] = tsed25519.ScalarMultiplyBase(shard)
+		}
+	}
 
 	tmpDir := t.TempDir()
 
@@ -377,8 +514,9 @@ func getTestLocalCosigners(t *testing.T, threshold, total uint8) ([]*LocalCosign
 			StateDir: cosignerDir,
 			Config: Config{
 				ThresholdModeConfig: &ThresholdModeConfig{
-					Threshold: int(threshold),
-					Cosigners: cosignersConfig,
+					Threshold:  int(threshold),
+					Cosigners:  cosignersConfig,
+					SignScheme: signScheme,
 				},
 			},
 		}
@@ -399,14 +537,14 @@ func getTestLocalCosigners(t *testing.T, threshold, total uint8) ([]*LocalCosign
 
 		cosigners[i] = cosigner
 
-		err = loadKeyForLocalCosigner(cosigner, privateKey.PubKey(), testChainID, privShards[i])
+		err = loadKeyForLocalCosigner(cosigner, pubKey, testChainID, privShards[i], publicShards)
 		require.NoError(t, err)
 
-		err = loadKeyForLocalCosigner(cosigner, privateKey.PubKey(), testChainID2, privShards[i])
+		err = loadKeyForLocalCosigner(cosigner, pubKey, testChainID2, privShards[i], publicShards)
 		require.NoError(t, err)
 	}
 
-	return cosigners, privateKey.PubKey()
+	return cosigners, pubKey
 }
 
 func testThresholdValidatorLeaderElection(t *testing.T, threshold, total uint8) {
@@ -786,3 +924,188 @@ func (c *InvalidCosigner) SetNoncesAndSign(
 func (c *InvalidCosigner) VerifySignature(chainID string, payload, signature []byte) bool {
 	return c.cosigner.VerifySignature(chainID, payload, signature)
 }
+
+func TestThresholdValidatorInvalidPartialSignature(t *testing.T) {
+	// tampered signature share
+	testThresholdValidatorInvalidPartialSignature(t, false, false)
+	// signature share for different nonces, not reported as an existing signature
+	testThresholdValidatorInvalidPartialSignature(t, true, false)
+	// tampered signature share, reported as an existing signature
+	testThresholdValidatorInvalidPartialSignature(t, false, true)
+}
+
+func testThresholdValidatorInvalidPartialSignature(t *testing.T, nonce, existing bool) {
+	cosigners, _ := getTestLocalCosigners(t, 2, 3)
+
+	leader := &MockLeader{id: 1}
+
+	validator := NewThresholdValidator(
+		cometlog.NewNopLogger(),
+		cosigners[0].config,
+		2,
+		time.Second,
+		1,
+		cosigners[0],
+		[]Cosigner{&TamperingCosigner{cosigner: cosigners[1], nonce: nonce, existing: existing}},
+		leader,
+	)
+	defer validator.Stop()
+
+	leader.leader = validator
+
+	require.NoError(t, validator.LoadSignStateIfNecessary(testChainID))
+
+	counter := totalInvalidPartialSignatures.WithLabelValues(testChainID, "2")
+	var before dto.Metric
+	require.NoError(t, counter.Write(&before))
+
+	block := ProposalToBlock(testChainID, &cometproto.Proposal{
+		Height: 1,
+		Round:  0,
+		Type:   cometproto.ProposalType,
+	})
+
+	_, _, _, err := validator.Sign(context.Background(), testChainID, block)
+	require.Error(t, err)
+
+	var after dto.Metric
+	require.NoError(t, counter.Write(&after))
+	require.Equal(t, before.GetCounter().GetValue()+1, after.GetCounter().GetValue())
+}
+
+func TestNonceCommitmentsConsistent(t *testing.T) {
+	cosigners, _ := getTestLocalCosigners(t, 2, 3)
+
+	nonces, err := cosigners[0].GetNonces(context.Background(), []uuid.UUID{uuid.New()})
+	require.NoError(t, err)
+	require.Len(t, nonces[0].Nonces, 2)
+	require.NotEmpty(t, nonces[0].Nonces[0].Commitments)
+
+	require.NoError(t, nonces.verifyCommitments(cosigners[0].GetID(), false))
+	require.ErrorContains(t, nonces.verifyCommitments(cosigners[1].GetID(), false), "sent a nonce of cosigner")
+
+	// a cosigner that sends different commitments to each destination is detected by the leader
+	nonces[0].Nonces[1].Commitments = [][]byte{nonces[0].Nonces[1].Commitments[0]}
+	require.ErrorContains(t, nonces.verifyCommitments(cosigners[0].GetID(), false), "different nonce commitments")
+
+	// nonces without commitments are only accepted for a rolling upgrade
+	for i := range nonces[0].Nonces {
+		nonces[0].Nonces[i].Commitments = nil
+	}
+	require.ErrorIs(t, nonces.verifyCommitments(cosigners[0].GetID(), false), errNoNonceCommitments)
+	require.NoError(t, nonces.verifyCommitments(cosigners[0].GetID(), true))
+}
+
+// TamperingCosigner produces partial signatures with a valid nonce, but an invalid signature share.
+// If nonce is set, it instead produces partial signatures for different nonces
+// without reporting them as existing signatures. If existing is set, the partial signatures are
+// reported as existing signatures.
+type TamperingCosigner struct {
+	cosigner *LocalCosigner
+	nonce    bool
+	existing bool
+}
+
+var _ Cosigner = &TamperingCosigner{}
+
+func (c *TamperingCosigner) GetID() int {
+	return c.cosigner.GetID()
+}
+
+func (c *TamperingCosigner) GetAddress() string {
+	return c.cosigner.GetAddress()
+}
+
+func (c *TamperingCosigner) GetPubKey(chainID string) (cometcrypto.PubKey, error) {
+	return c.cosigner.GetPubKey(chainID)
+}
+
+func (c *TamperingCosigner) VerifySignature(chainID string, payload, signature []byte) bool {
+	return c.cosigner.VerifySignature(chainID, payload, signature)
+}
+
+func (c *TamperingCosigner) GetNonces(ctx context.Context, uuids []uuid.UUID) (CosignerUUIDNoncesMultiple, error) {
+	return c.cosigner.GetNonces(ctx, uuids)
+}
+
+func (c *TamperingCosigner) SetNoncesAndSign(
+	ctx context.Context,
+	req CosignerSetNoncesAndSignRequest,
+) (*CosignerSignResponse, error) {
+	res, err := c.cosigner.SetNoncesAndSign(ctx, req)
+	if err != nil {
+		return res, err
+	}
+
+	if c.nonce {
+		res.Signature[0]++
+	} else {
+		res.Signature[32]++
+	}
+	if c.existing {
+		ccs, err := c.cosigner.getChainState(req.ChainID)
+		if err != nil {
+			return nil, err
+		}
+		res.Existing = true
+		res.Nonces = ccs.lastSignState.Nonces
+	}
+	return res, nil
+}
+
+// NoncelessCosigner returns valid partial signatures as existing signatures without their nonces,
+// like cosigners of previous versions.
+type NoncelessCosigner struct {
+	*LocalCosigner
+}
+
+func (c *NoncelessCosigner) SetNoncesAndSign(
+	ctx context.Context,
+	req CosignerSetNoncesAndSignRequest,
+) (*CosignerSignResponse, error) {
+	res, err := c.LocalCosigner.SetNoncesAndSign(ctx, req)
+	if err != nil {
+		return res, err
+	}
+	res.Existing = true
+	res.Nonces = nil
+	return res, nil
+}
+
+func TestThresholdValidatorExistingPartialSignatureWithoutNonces(t *testing.T) {
+	for _, allowMissing := range []bool{false, true} {
+		cosigners, _ := getTestLocalCosigners(t, 2, 3)
+		cosigners[0].config.Config.ThresholdModeConfig.AllowMissingNonceCommitments = allowMissing
+
+		leader := &MockLeader{id: 1}
+
+		validator := NewThresholdValidator(
+			cometlog.NewNopLogger(),
+			cosigners[0].config,
+			2,
+			time.Second,
+			1,
+			cosigners[0],
+			[]Cosigner{&NoncelessCosigner{cosigners[1]}},
+			leader,
+		)
+		defer validator.Stop()
+
+		leader.leader = validator
+
+		require.NoError(t, validator.LoadSignStateIfNecessary(testChainID))
+
+		block := ProposalToBlock(testChainID, &cometproto.Proposal{
+			Height: 1,
+			Round:  0,
+			Type:   cometproto.ProposalType,
+		})
+
+		_, _, _, err := validator.Sign(context.Background(), testChainID, block)
+		if allowMissing {
+			require.NoError(t, err)
+		} else {
+			require.Error(t, err)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package signer
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	"github.com/cometbft/cometbft/privval"
+)
+
+// ParseTmkmsSoftsignKey parses a tmkms softsign Ed25519 key file, in either the raw or the base64 key format.
+// The key is the 32 byte Ed25519 seed, or the 64 byte seed and public key.
+func ParseTmkmsSoftsignKey(bz []byte) (privval.FilePVKey, error) {
+	var pv privval.FilePVKey
+
+	seed, err := tmkmsSoftsignSeed(bz)
+	if err != nil {
+		return pv, err
+	}
+
+	privKey := cometcryptoed25519.PrivKey(ed25519.NewKeyFromSeed(seed))
+	pv.PrivKey = privKey
+	pv.PubKey = privKey.PubKey()
+	pv.Address = pv.PubKey.Address()
+	return pv, nil
+}
+
+// tmkmsSoftsignSeed returns the Ed25519 seed of a raw or base64 tmkms softsign key.
+func tmkmsSoftsignSeed(bz []byte) ([]byte, error) {
+	key := bz
+	if len(bz) != ed25519.SeedSize && len(bz) != ed25519.PrivateKeySize {
+		var err error
+		if key, err = base64.StdEncoding.DecodeString(string(bytes.TrimSpace(bz))); err != nil {
+			return nil, fmt.Errorf("tmkms softsign key is neither raw nor base64: %w", err)
+		}
+	}
+
+	switch len(key) {
+	case ed25519.SeedSize:
+		return key, nil
+	case ed25519.PrivateKeySize:
+		if !bytes.Equal(ed25519.NewKeyFromSeed(key[:ed25519.SeedSize]), key) {
+			return nil, fmt.Errorf("tmkms softsign key: public key does not match the seed")
+		}
+		return key[:ed25519.SeedSize], nil
+	default:
+		return nil, fmt.Errorf("invalid tmkms softsign key size: %d", len(key))
+	}
+}
+
+// TmkmsSoftsignKey returns the tmkms softsign key file in the base64 key format of an Ed25519 key.
+// Keys that were combined from shards only hold the secret scalar and not the seed, so they can not be
+// used by tmkms.
+func TmkmsSoftsignKey(pv privval.FilePVKey) ([]byte, error) {
+	privKey, ok := pv.PrivKey.(cometcryptoed25519.PrivKey)
+	if !ok {
+		return nil, fmt.Errorf("%s keys can not be used by tmkms softsign, only %s keys with a seed",
+			pv.PrivKey.Type(), cometcryptoed25519.KeyType)
+	}
+	return []byte(base64.StdEncoding.EncodeToString(privKey[:ed25519.SeedSize]) + "\n"), nil
+}
+
+// tmkms records the step of the last signed message in its consensus state as 0 for a proposal,
+// 1 for a prevote and 2 for a precommit, which is one less than the CometBFT and horcrux steps.
+const tmkmsStepOffset = 1
+
+// TmkmsConsensusState is the consensus state file of tmkms, state/{chain-id}_consensus.json.
+type TmkmsConsensusState struct {
+	Height  string          `json:"height"`
+	Round   string          `json:"round"`
+	Step    int8            `json:"step"`
+	BlockID json.RawMessage `json:"block_id"`
+}
+
+// NewTmkmsConsensusState returns the tmkms consensus state of a high watermark.
+// The block ID is not known to horcrux, so it is left empty.
+func NewTmkmsConsensusState(hrs HRSKey) TmkmsConsensusState {
+	step := hrs.Step - tmkmsStepOffset
+	if step < 0 {
+		step = 0
+	}
+	return TmkmsConsensusState{
+		Height:  strconv.FormatInt(hrs.Height, 10),
+		Round:   strconv.FormatInt(hrs.Round, 10),
+		Step:    step,
+		BlockID: json.RawMessage("null"),
+	}
+}
+
+// consensusStateInt is an integer of a consensus state file, which may be encoded as a string or a number.
+type consensusStateInt int64
+
+func (i *consensusStateInt) UnmarshalJSON(bz []byte) error {
+	var s string
+	if err := json.Unmarshal(bz, &s); err != nil {
+		s = string(bz)
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s: %w", bz, err)
+	}
+	*i = consensusStateInt(v)
+	return nil
+}
+
+// ParseConsensusState parses the height, round and step of a CometBFT priv_validator_state.json
+// or of a tmkms consensus state file, which is recognized by its block_id.
+// tmkms steps are converted to horcrux steps. Steps above a precommit, as recorded by older tmkms
+// versions, are read as a precommit, so the high watermark is never lowered.
+func ParseConsensusState(bz []byte) (HRSKey, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bz, &fields); err != nil {
+		return HRSKey{}, fmt.Errorf("error parsing consensus state: %w", err)
+	}
+	if _, ok := fields["height"]; !ok {
+		return HRSKey{}, fmt.Errorf("error parsing consensus state: height is missing")
+	}
+
+	var state struct {
+		Height consensusStateInt `json:"height"`
+		Round  consensusStateInt `json:"round"`
+		Step   consensusStateInt `json:"step"`
+	}
+	if err := json.Unmarshal(bz, &state); err != nil {
+		return HRSKey{}, fmt.Errorf("error parsing consensus state: %w", err)
+	}
+	if state.Height < 0 || state.Round < 0 || state.Step < 0 {
+		return HRSKey{}, fmt.Errorf("invalid consensus state: height %d, round %d, step %d",
+			state.Height, state.Round, state.Step)
+	}
+
+	step := int64(state.Step)
+	if _, tmkms := fields["block_id"]; tmkms {
+		step += tmkmsStepOffset
+		if step > int64(stepPrecommit) {
+			step = int64(stepPrecommit)
+		}
+	} else if step > int64(stepPrecommit) {
+		return HRSKey{}, fmt.Errorf("invalid consensus state step (%d)", step)
+	}
+
+	return HRSKey{
+		Height: int64(state.Height),
+		Round:  int64(state.Round),
+		Step:   int8(step),
+	}, nil
+}
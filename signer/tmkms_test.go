@@ -0,0 +1,143 @@
+package signer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cometcryptoed25519 "github.com/cometbft/cometbft/crypto/ed25519"
+	cometprivval "github.com/cometbft/cometbft/privval"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTmkmsSoftsignKey(t *testing.T) {
+	privateKey := cometcryptoed25519.GenPrivKey()
+	seed := []byte(privateKey[:32])
+
+	for name, bz := range map[string][]byte{
+		"raw seed":         seed,
+		"raw keypair":      privateKey,
+		"base64 seed":      []byte(base64.StdEncoding.EncodeToString(seed) + "\n"),
+		"base64 keypair":   []byte(base64.StdEncoding.EncodeToString(privateKey)),
+		"exported keyfile": mustTmkmsSoftsignKey(t, privateKey),
+	} {
+		t.Run(name, func(t *testing.T) {
+			keyFile := filepath.Join(t.TempDir(), "consensus.key")
+			require.NoError(t, os.WriteFile(keyFile, bz, 0600))
+
+			pv, err := ReadPrivValidatorFile(keyFile)
+			require.NoError(t, err)
+			require.Equal(t, privateKey, pv.PrivKey)
+			require.Equal(t, privateKey.PubKey(), pv.PubKey)
+			require.Equal(t, privateKey.PubKey().Address(), pv.Address)
+		})
+	}
+
+	// the public key must match the seed
+	invalid := append([]byte{}, privateKey...)
+	invalid[63] ^= 0xff
+	_, err := ParseTmkmsSoftsignKey(invalid)
+	require.Error(t, err)
+
+	_, err = ParseTmkmsSoftsignKey([]byte(base64.StdEncoding.EncodeToString(seed[:16])))
+	require.Error(t, err)
+
+	// combined keys hold no seed
+	shards := CreateCosignerEd25519Shards(cometprivval.FilePVKey{
+		Address: privateKey.PubKey().Address(),
+		PubKey:  privateKey.PubKey(),
+		PrivKey: privateKey,
+	}, 2, 3)
+	combined, err := CombineCosignerEd25519Shards(shards[:2])
+	require.NoError(t, err)
+	_, err = TmkmsSoftsignKey(cometprivval.FilePVKey{PubKey: combined.PubKey(), PrivKey: combined})
+	require.Error(t, err)
+}
+
+func mustTmkmsSoftsignKey(t *testing.T, privateKey cometcryptoed25519.PrivKey) []byte {
+	bz, err := TmkmsSoftsignKey(cometprivval.FilePVKey{PubKey: privateKey.PubKey(), PrivKey: privateKey})
+	require.NoError(t, err)
+	return bz
+}
+
+func TestParseConsensusState(t *testing.T) {
+	tcs := []struct {
+		name      string
+		state     string
+		expected  HRSKey
+		expectErr bool
+	}{
+		{
+			name:     "cometbft",
+			state:    `{"height":"100","round":2,"step":3,"signature":"","signbytes":""}`,
+			expected: HRSKey{Height: 100, Round: 2, Step: stepPrecommit},
+		},
+		{
+			name:     "cometbft initial",
+			state:    `{"height":"0","round":0,"step":0}`,
+			expected: HRSKey{},
+		},
+		{
+			name:      "cometbft invalid step",
+			state:     `{"height":"100","round":0,"step":4}`,
+			expectErr: true,
+		},
+		{
+			name:     "tmkms proposal",
+			state:    `{"height":"100","round":"1","step":0,"block_id":null}`,
+			expected: HRSKey{Height: 100, Round: 1, Step: stepPropose},
+		},
+		{
+			name:     "tmkms precommit",
+			state:    `{"height":"100","round":"1","step":2,"block_id":{"hash":"AB","part_set_header":{"total":1}}}`,
+			expected: HRSKey{Height: 100, Round: 1, Step: stepPrecommit},
+		},
+		{
+			name:     "tmkms legacy step",
+			state:    `{"height":"100","round":"0","step":6,"block_id":null}`,
+			expected: HRSKey{Height: 100, Round: 0, Step: stepPrecommit},
+		},
+		{
+			name:      "negative height",
+			state:     `{"height":"-1","round":"0","step":0,"block_id":null}`,
+			expectErr: true,
+		},
+		{
+			name:      "missing height",
+			state:     `{"round":"0","step":0}`,
+			expectErr: true,
+		},
+		{
+			name:      "invalid height",
+			state:     `{"height":"abc","round":0,"step":0}`,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			hrs, err := ParseConsensusState([]byte(tc.state))
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, hrs)
+		})
+	}
+
+	// the exported tmkms consensus state is read back as the same high watermark
+	for _, hrs := range []HRSKey{
+		{Height: 100, Round: 1, Step: stepPropose},
+		{Height: 100, Round: 1, Step: stepPrevote},
+		{Height: 100, Round: 1, Step: stepPrecommit},
+	} {
+		bz, err := json.Marshal(NewTmkmsConsensusState(hrs))
+		require.NoError(t, err)
+		parsed, err := ParseConsensusState(bz)
+		require.NoError(t, err)
+		require.Equal(t, hrs, parsed)
+	}
+}
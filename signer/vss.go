@@ -0,0 +1,250 @@
+package signer
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+)
+
+// vssPolynomial is a random polynomial over the ed25519 scalar field used for
+// Feldman verifiable secret sharing. The constant term is the shared secret.
+type vssPolynomial []*edwards25519.Scalar
+
+// newVSSPolynomial creates a random polynomial of degree threshold-1 with the provided secret
+// as the constant term. If secret is nil, a random secret is generated.
+func newVSSPolynomial(secret *edwards25519.Scalar, threshold uint8) (vssPolynomial, error) {
+	if threshold == 0 {
+		return nil, errors.New("threshold must be greater than zero")
+	}
+
+	p := make(vssPolynomial, threshold)
+	for i := range p {
+		if i == 0 && secret != nil {
+			p[i] = edwards25519.NewScalar().Set(secret)
+			continue
+		}
+		s, err := randomScalar()
+		if err != nil {
+			return nil, err
+		}
+		p[i] = s
+	}
+
+	return p, nil
+}
+
+// evaluate returns the share of the polynomial for the shamir index id.
+func (p vssPolynomial) evaluate(id int) *edwards25519.Scalar {
+	x := scalarFromInt(id)
+
+	// horner's method
+	out := edwards25519.NewScalar()
+	for i := len(p) - 1; i >= 0; i-- {
+		out.MultiplyAdd(out, x, p[i])
+	}
+
+	return out
+}
+
+// commitments returns the public commitments to each coefficient of the polynomial.
+func (p vssPolynomial) commitments() [][]byte {
+	out := make([][]byte, len(p))
+	for i, c := range p {
+		out[i] = new(edwards25519.Point).ScalarBaseMult(c).Bytes()
+	}
+	return out
+}
+
+// verifyVSSShare checks that share is the evaluation for the shamir index id
+// of the polynomial committed to by commitments.
+func verifyVSSShare(id int, share []byte, commitments [][]byte) error {
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(share)
+	if err != nil {
+		return fmt.Errorf("invalid share: %w", err)
+	}
+
+	expected, err := vssCommitmentAt(id, commitments)
+	if err != nil {
+		return err
+	}
+
+	if new(edwards25519.Point).ScalarBaseMult(s).Equal(expected) != 1 {
+		return errors.New("share does not match commitments")
+	}
+
+	return nil
+}
+
+// vssCommitmentAt evaluates the committed polynomial in the exponent for the shamir index id.
+func vssCommitmentAt(id int, commitments [][]byte) (*edwards25519.Point, error) {
+	if len(commitments) == 0 {
+		return nil, errors.New("no commitments")
+	}
+
+	x := scalarFromInt(id)
+
+	out := edwards25519.NewIdentityPoint()
+	for i := len(commitments) - 1; i >= 0; i-- {
+		c, err := new(edwards25519.Point).SetBytes(commitments[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid commitment %d: %w", i, err)
+		}
+		out.ScalarMult(x, out)
+		out.Add(out, c)
+	}
+
+	return out, nil
+}
+
+// vssPublicShards returns the public key shards for the shamir indexes ids of the sum
+// of the polynomials committed to by each set of commitments, indexed by shamir index - 1.
+// Shamir indexes that are not in ids have an empty public key shard.
+func vssPublicShards(ids []int, commitments [][][]byte) ([][]byte, error) {
+	var maxID int
+	for _, id := range ids {
+		if id > maxID {
+			maxID = id
+		}
+	}
+
+	out := make([][]byte, maxID)
+	for _, id := range ids {
+		sum := edwards25519.NewIdentityPoint()
+		for _, c := range commitments {
+			p, err := vssCommitmentAt(id, c)
+			if err != nil {
+				return nil, err
+			}
+			sum.Add(sum, p)
+		}
+		out[id-1] = sum.Bytes()
+	}
+
+	return out, nil
+}
+
+// verifyPartialSignature checks a partial ed25519 signature, the combined public nonce followed by
+// the signature share, against the public key shard and the public nonce share of the cosigner:
+// s*B == R_i + k*Y_i, where k = H(R || A || M).
+func verifyPartialSignature(pubKey, publicShard, noncePublicShare, payload, signature []byte) error {
+	if len(signature) != 64 {
+		return fmt.Errorf("invalid partial signature length: %d", len(signature))
+	}
+
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(signature[32:])
+	if err != nil {
+		return fmt.Errorf("invalid signature share: %w", err)
+	}
+
+	y, err := new(edwards25519.Point).SetBytes(publicShard)
+	if err != nil {
+		return fmt.Errorf("invalid public key shard: %w", err)
+	}
+
+	r, err := new(edwards25519.Point).SetBytes(noncePublicShare)
+	if err != nil {
+		return fmt.Errorf("invalid public nonce share: %w", err)
+	}
+
+	h := sha512.New()
+	h.Write(signature[:32])
+	h.Write(pubKey)
+	h.Write(payload)
+	k, err := edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	if err != nil {
+		return err
+	}
+
+	expected := new(edwards25519.Point).ScalarMult(k, y)
+	expected.Add(expected, r)
+
+	if new(edwards25519.Point).ScalarBaseMult(s).Equal(expected) != 1 {
+		return errors.New("signature share does not match the public key shard")
+	}
+
+	return nil
+}
+
+// addPoints returns the sum of the encoded points.
+func addPoints(points [][]byte) ([]byte, error) {
+	out := edwards25519.NewIdentityPoint()
+	for i, bz := range points {
+		p, err := new(edwards25519.Point).SetBytes(bz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %d: %w", i, err)
+		}
+		out.Add(out, p)
+	}
+	return out.Bytes(), nil
+}
+
+// lagrangeCoefficient returns the lagrange basis polynomial for the shamir index id
+// over the shamir indexes ids, evaluated at x.
+func lagrangeCoefficient(id int, ids []int, x int) (*edwards25519.Scalar, error) {
+	num := scalarFromInt(1)
+	den := scalarFromInt(1)
+	found := false
+
+	xs := scalarFromInt(x)
+	is := scalarFromInt(id)
+
+	for _, j := range ids {
+		if j == id {
+			if found {
+				return nil, fmt.Errorf("shamir index %d is repeated in %v", id, ids)
+			}
+			found = true
+			continue
+		}
+		js := scalarFromInt(j)
+		num.Multiply(num, edwards25519.NewScalar().Subtract(xs, js))
+		den.Multiply(den, edwards25519.NewScalar().Subtract(is, js))
+	}
+
+	if !found {
+		return nil, fmt.Errorf("shamir index %d is not in %v", id, ids)
+	}
+
+	return num.Multiply(num, edwards25519.NewScalar().Invert(den)), nil
+}
+
+// interpolatePoints evaluates the polynomial in the exponent through the encoded points
+// for the shamir indexes ids at x.
+func interpolatePoints(ids []int, points [][]byte, x int) (*edwards25519.Point, error) {
+	out := edwards25519.NewIdentityPoint()
+	for i, id := range ids {
+		p, err := new(edwards25519.Point).SetBytes(points[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid point for shamir index %d: %w", id, err)
+		}
+		lambda, err := lagrangeCoefficient(id, ids, x)
+		if err != nil {
+			return nil, err
+		}
+		out.Add(out, p.ScalarMult(lambda, p))
+	}
+	return out, nil
+}
+
+func randomScalar() (*edwards25519.Scalar, error) {
+	var bz [64]byte
+	if _, err := rand.Read(bz[:]); err != nil {
+		return nil, err
+	}
+	return edwards25519.NewScalar().SetUniformBytes(bz[:])
+}
+
+func scalarFromInt(i int) *edwards25519.Scalar {
+	var bz [32]byte
+	binary.LittleEndian.PutUint64(bz[:8], uint64(i))
+	s, err := edwards25519.NewScalar().SetCanonicalBytes(bz[:])
+	if err != nil {
+		// unreachable, any uint64 is a canonical scalar
+		panic(err)
+	}
+	return s
+}